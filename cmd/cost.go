@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/datablast-analytics/blast-cli/pkg/bigquery"
+	"github.com/datablast-analytics/blast-cli/pkg/cost"
+	"github.com/datablast-analytics/blast-cli/pkg/lint"
+	"github.com/datablast-analytics/blast-cli/pkg/path"
+	"github.com/urfave/cli/v2"
+)
+
+func Cost(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "cost",
+		Usage:     "estimate the bytes scanned and the on-demand cost of the BigQuery tasks in the given pipelines",
+		ArgsUsage: "[path to pipelines]",
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			rootPath := c.Args().Get(0)
+			if rootPath == "" {
+				rootPath = defaultPipelinePath
+			}
+
+			builder, err := newPipelineBuilder(rootPath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			bqConfig, err := bigquery.LoadConfigFromEnv()
+			if err != nil || !bqConfig.IsValid() {
+				errorPrinter.Println("BigQuery credentials are required to estimate the cost, make sure BIGQUERY_PROJECT and BIGQUERY_CREDENTIALS_FILE are set")
+				return cli.Exit("", 1)
+			}
+
+			bq, err := bigquery.NewDB(bqConfig)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while connecting to BigQuery: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			pipelinePaths, err := path.GetPipelinePaths(rootPath, pipelineDefinitionFile)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while finding the pipelines: %v\n", err)
+				return cli.Exit("", 1)
+			}
+			sort.Strings(pipelinePaths)
+
+			ctx, cancel := interruptibleContext(c.Context, 0)
+			defer cancel()
+
+			estimator := lint.NewBigqueryCostEstimator(bq, builder.cfg)
+
+			hasErrors := false
+			var totalBytes int64
+			var totalCost float64
+			for _, pipelinePath := range pipelinePaths {
+				logger.Debugf("creating pipeline from path '%s'", pipelinePath)
+
+				p, err := builder.CreatePipelineFromPath(pipelinePath)
+				if err != nil {
+					errorPrinter.Printf("An error occurred while creating the pipeline from path '%s': %v\n", pipelinePath, err)
+					return cli.Exit("", 1)
+				}
+
+				estimate := estimator.EstimatePipeline(ctx, p)
+				printPipelineEstimate(rootPath, estimate)
+
+				hasErrors = hasErrors || estimate.HasErrors()
+				totalBytes += estimate.BytesProcessed
+				totalCost += estimate.Cost
+			}
+
+			fmt.Printf("\nTotal: %s (≈ $%.2f)\n", cost.FormatBytes(totalBytes), totalCost)
+
+			if hasErrors {
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+func printPipelineEstimate(rootPath string, estimate *cost.PipelineEstimate) {
+	fmt.Println()
+	pipelinePrinter.Printf("Pipeline: %s %s\n", estimate.Pipeline.Name, faint(fmt.Sprintf("(%s)", relativePath(rootPath, filepath.Dir(estimate.Pipeline.DefinitionFile.Path)))))
+
+	if len(estimate.Tasks) == 0 {
+		fmt.Println("  No BigQuery tasks found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	for _, taskEstimate := range estimate.Tasks {
+		if taskEstimate.Err != nil {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t\n", taskEstimate.Task.Name, errorPrinter.Sprint(taskEstimate.Err))
+			continue
+		}
+
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t$%.2f\n", taskEstimate.Task.Name, cost.FormatBytes(taskEstimate.BytesProcessed), taskEstimate.Cost)
+	}
+	_, _ = fmt.Fprintf(w, "  %s\t%s\t$%.2f\n", "Total", cost.FormatBytes(estimate.BytesProcessed), estimate.Cost)
+	_ = w.Flush()
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/export/airflow"
+	"github.com/datablast-analytics/blast-cli/pkg/path"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v2"
+)
+
+const defaultDagOutputDir = "dags"
+
+func Export(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:  "export",
+		Usage: "export the pipelines to run them on other orchestrators",
+		Subcommands: []*cli.Command{
+			exportAirflow(isDebug),
+		},
+	}
+}
+
+func exportAirflow(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "airflow",
+		Usage:     "generate an Airflow DAG file for every pipeline in the given path",
+		ArgsUsage: "[path to pipelines]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Value:   defaultDagOutputDir,
+				Usage:   "the directory to write the DAG files to",
+			},
+			&cli.StringFlag{
+				Name:  "start-date",
+				Value: airflow.DefaultStartDate.Format("2006-01-02"),
+				Usage: "the start date of the DAGs, in the YYYY-MM-DD format",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			rootPath := c.Args().Get(0)
+			if rootPath == "" {
+				rootPath = defaultPipelinePath
+			}
+
+			startDate, err := time.Parse("2006-01-02", c.String("start-date"))
+			if err != nil {
+				errorPrinter.Printf("Invalid start date '%s', it must be in the YYYY-MM-DD format\n", c.String("start-date"))
+				return cli.Exit("", 1)
+			}
+
+			outputDir, err := filepath.Abs(c.String("output"))
+			if err != nil {
+				errorPrinter.Printf("An error occurred while resolving the output directory: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			pipelinePaths, err := path.GetPipelinePaths(rootPath, pipelineDefinitionFile)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while finding the pipelines: %v\n", err)
+				return cli.Exit("", 1)
+			}
+			sort.Strings(pipelinePaths)
+
+			exporter := &airflow.Exporter{
+				Fs:        afero.NewOsFs(),
+				OutputDir: outputDir,
+				StartDate: startDate,
+			}
+			builder, err := newPipelineBuilder(rootPath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			hasErrors := false
+			for _, pipelinePath := range pipelinePaths {
+				logger.Debugf("creating pipeline from path '%s'", pipelinePath)
+
+				p, err := builder.CreatePipelineFromPath(pipelinePath)
+				if err != nil {
+					errorPrinter.Printf("An error occurred while creating the pipeline from path '%s': %v\n", pipelinePath, err)
+					return cli.Exit("", 1)
+				}
+
+				dagPath, err := exporter.Export(p)
+				if err != nil {
+					errorPrinter.Printf("Failed to export the pipeline '%s': %v\n", p.Name, err)
+					hasErrors = true
+					continue
+				}
+
+				successPrinter.Printf("Exported the pipeline '%s' to %s\n", p.Name, relativePath(defaultPipelinePath, dagPath))
+			}
+
+			if hasErrors {
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/datablast-analytics/blast-cli/pkg/format"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+func Format(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "fmt",
+		Usage:     "format the SQL files of the BigQuery and Snowflake tasks",
+		ArgsUsage: "[path to pipelines or to a task file]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "check",
+				Usage: "only report the files that are not formatted and exit with an error if there are any, e.g. in CI",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			inputPath := c.Args().Get(0)
+			if inputPath == "" {
+				inputPath = defaultPipelinePath
+			}
+
+			// allow passing the pipeline definition file directly, e.g. with shell completion
+			if filepath.Base(inputPath) == pipelineDefinitionFile {
+				inputPath = filepath.Dir(inputPath)
+			}
+
+			pipelines, onlyFile, err := pipelinesToFormat(logger, inputPath)
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			displayRoot := inputPath
+			if onlyFile != "" {
+				displayRoot = defaultPipelinePath
+			}
+
+			check := c.Bool("check")
+			formatter := format.NewFormatter(afero.NewOsFs())
+			seen := make(map[string]bool)
+
+			hasErrors := false
+			unformatted := 0
+			for _, p := range pipelines {
+				pipelinePrinter.Printf("\nPipeline: %s %s\n", p.Name, faint("("+relativePath(displayRoot, filepath.Dir(p.DefinitionFile.Path))+")"))
+
+				changed := 0
+				for _, task := range p.Tasks {
+					path := task.ExecutableFile.Path
+					if !format.Supports(task.Type) || seen[path] || (onlyFile != "" && path != onlyFile) {
+						continue
+					}
+					seen[path] = true
+
+					change, err := formatter.Plan(task)
+					if err != nil {
+						errorPrinter.Printf("  Cannot format the task '%s' %s: %v\n", task.Name, faint("("+p.RelativeTaskPath(task)+")"), err)
+						hasErrors = true
+						continue
+					}
+
+					if change == nil {
+						continue
+					}
+
+					changed++
+					if check {
+						errorPrinter.Printf("  The task '%s' is not formatted %s\n", task.Name, faint("("+p.RelativeTaskPath(task)+")"))
+						continue
+					}
+
+					if err := formatter.Apply(change); err != nil {
+						errorPrinter.Printf("  Failed to format the task '%s': %v\n", task.Name, err)
+						hasErrors = true
+						continue
+					}
+
+					successPrinter.Printf("  Formatted the task '%s' %s\n", task.Name, faint("("+p.RelativeTaskPath(task)+")"))
+				}
+
+				if changed == 0 {
+					successPrinter.Printf("  All the files are formatted\n")
+				}
+				unformatted += changed
+			}
+
+			if check && unformatted > 0 {
+				errorPrinter.Printf("\nThe files above are not formatted, run 'blast fmt' to format them\n")
+				return cli.Exit("", 1)
+			}
+
+			if hasErrors {
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+// pipelinesToFormat builds the pipelines under the given path, or the pipeline of the given task file, in which case
+// the absolute path of the file is returned as well to format only that file.
+func pipelinesToFormat(logger *zap.SugaredLogger, inputPath string) ([]*pipeline.Pipeline, string, error) {
+	info, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "cannot read the path '%s'", inputPath)
+	}
+
+	if info.IsDir() {
+		builder, err := newPipelineBuilder(inputPath)
+		if err != nil {
+			return nil, "", err
+		}
+
+		pipelines, err := buildPipelines(logger, builder, inputPath)
+		return pipelines, "", err
+	}
+
+	filePath, err := filepath.Abs(inputPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pipelineRoot, err := findPipelineRoot(filepath.Dir(filePath))
+	if err != nil {
+		return nil, "", err
+	}
+
+	builder, err := newPipelineBuilder(pipelineRoot)
+	if err != nil {
+		return nil, "", err
+	}
+
+	logger.Debugf("creating pipeline from path '%s'", pipelineRoot)
+	p, err := builder.CreatePipelineFromPath(pipelineRoot)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "an error occurred while creating the pipeline from path '%s'", pipelineRoot)
+	}
+
+	for _, task := range p.Tasks {
+		if task.ExecutableFile.Path == filePath {
+			if !format.Supports(task.Type) {
+				return nil, "", errors.Errorf("the file '%s' belongs to the task '%s' of type '%s', only the BigQuery and Snowflake SQL tasks can be formatted", inputPath, task.Name, task.Type)
+			}
+
+			return []*pipeline.Pipeline{p}, filePath, nil
+		}
+	}
+
+	return nil, "", errors.Errorf("the file '%s' is not the file of a task in the pipeline '%s'", inputPath, p.Name)
+}
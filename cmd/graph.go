@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/datablast-analytics/blast-cli/pkg/graph"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/urfave/cli/v2"
+)
+
+func Graph(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "graph",
+		Usage:     "export the tasks of a pipeline and their dependencies as a graph",
+		ArgsUsage: "[path to the pipeline]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Value: "mermaid",
+				Usage: fmt.Sprintf("the output format, one of: %s", strings.Join(graph.Formats(), ", ")),
+			},
+			&cli.StringFlag{
+				Name:  "task",
+				Usage: "the name of a task to highlight along with its upstream and downstream tasks",
+			},
+			filterFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			pipelinePath := c.Args().Get(0)
+			if pipelinePath == "" {
+				pipelinePath = defaultPipelinePath
+			}
+
+			// allow passing the pipeline definition file directly, e.g. with shell completion
+			if filepath.Base(pipelinePath) == pipelineDefinitionFile {
+				pipelinePath = filepath.Dir(pipelinePath)
+			}
+
+			builder, err := newPipelineBuilder(pipelinePath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			logger.Debugf("creating pipeline from path '%s'", pipelinePath)
+			p, err := builder.CreatePipelineFromPath(pipelinePath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while creating the pipeline from path '%s': %v\n", pipelinePath, err)
+				return cli.Exit("", 1)
+			}
+
+			filter, err := pipeline.ParseTaskFilters(c.StringSlice("filter"))
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			p, matched := filter.Apply(p)
+			if !matched {
+				errorPrinter.Printf("None of the tasks in the pipeline '%s' match the filters\n", p.Name)
+				return cli.Exit("", 1)
+			}
+
+			var selected *pipeline.Task
+			if taskName := c.String("task"); taskName != "" {
+				selected = p.GetTaskByName(taskName)
+				if selected == nil {
+					errorPrinter.Printf("There is no task named '%s' in the pipeline '%s'\n", taskName, p.Name)
+					return cli.Exit("", 1)
+				}
+			}
+
+			err = graph.Write(os.Stdout, graph.New(p, selected), c.String("format"))
+			if err != nil {
+				errorPrinter.Printf("An error occurred while exporting the graph: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
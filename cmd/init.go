@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/datablast-analytics/blast-cli/pkg/scaffold"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v2"
+)
+
+func Init(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "init",
+		Usage:     "create a new pipeline in a directory with the given name",
+		ArgsUsage: "[name of the pipeline]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "schedule",
+				Value: scaffold.DefaultSchedule,
+				Usage: "the schedule of the pipeline, either a cron expression or one of the descriptors such as @daily",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			name := c.Args().Get(0)
+			if name == "" {
+				errorPrinter.Println("Please give a name to the pipeline, e.g. `blast init sales`")
+				return cli.Exit("", 1)
+			}
+
+			pipelineDir, err := filepath.Abs(name)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while resolving the pipeline directory: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			cfg, err := config.LoadOrDefault(filepath.Dir(pipelineDir))
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			logger.Debugf("creating pipeline '%s' in '%s' with the templates in '%s'", name, pipelineDir, cfg.TemplatesDir())
+			files, err := scaffold.NewScaffolder(afero.NewOsFs(), cfg.TemplatesDir()).CreatePipeline(pipelineDir, filepath.Base(name), c.String("schedule"))
+			if err != nil {
+				errorPrinter.Printf("Failed to create the pipeline: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			successPrinter.Printf("Created the pipeline '%s'\n", filepath.Base(name))
+			for _, file := range files {
+				successPrinter.Printf("  %s\n", faint(relativePath(defaultPipelinePath, file)))
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/datablast-analytics/blast-cli/pkg/inspect"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/urfave/cli/v2"
+)
+
+func Inspect(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "inspect",
+		Usage:     "print the resolved pipeline or task as JSON, with the defaults of the pipeline applied",
+		ArgsUsage: "[path to the pipeline or to the task file]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "task",
+				Usage: "the name of the task to inspect in the pipeline",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			inputPath := c.Args().Get(0)
+			if inputPath == "" {
+				inputPath = defaultPipelinePath
+			}
+
+			info, err := os.Stat(inputPath)
+			if err != nil {
+				errorPrinter.Printf("Failed to read the path '%s': %v\n", inputPath, err)
+				return cli.Exit("", 1)
+			}
+
+			// a file other than the pipeline definition is a task file, the pipeline is the one the file is in
+			pipelinePath := inputPath
+			taskFile := ""
+			if !info.IsDir() {
+				pipelinePath = filepath.Dir(inputPath)
+				if filepath.Base(inputPath) != pipelineDefinitionFile {
+					taskFile, err = filepath.Abs(inputPath)
+					if err != nil {
+						errorPrinter.Printf("Failed to get the absolute path of '%s': %v\n", inputPath, err)
+						return cli.Exit("", 1)
+					}
+				}
+			}
+
+			pipelinePath, err = findPipelineRoot(pipelinePath)
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			builder, err := newPipelineBuilder(pipelinePath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			logger.Debugf("creating pipeline from path '%s'", pipelinePath)
+			p, err := builder.CreatePipelineFromPath(pipelinePath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while creating the pipeline from path '%s': %v\n", pipelinePath, err)
+				return cli.Exit("", 1)
+			}
+
+			var task *pipeline.Task
+			switch {
+			case c.String("task") != "":
+				task = p.GetTaskByName(c.String("task"))
+				if task == nil {
+					errorPrinter.Printf("There is no task named '%s' in the pipeline '%s'\n", c.String("task"), p.Name)
+					return cli.Exit("", 1)
+				}
+			case taskFile != "":
+				task = findTaskByFile(p, taskFile)
+				if task == nil {
+					errorPrinter.Printf("The file '%s' does not define a task in the pipeline '%s'\n", inputPath, p.Name)
+					return cli.Exit("", 1)
+				}
+			}
+
+			if task != nil {
+				err = inspect.Write(os.Stdout, inspect.NewTaskDocument(p, task, builder.taskTypes))
+			} else {
+				err = inspect.Write(os.Stdout, inspect.NewPipelineDocument(p, builder.taskTypes))
+			}
+			if err != nil {
+				errorPrinter.Printf("An error occurred while printing the output: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+// findTaskByFile finds the task either defined in the given file or running it, which allows passing the script of a
+// `task.yml` task as well.
+func findTaskByFile(p *pipeline.Pipeline, absPath string) *pipeline.Task {
+	for _, t := range p.Tasks {
+		if t.DefinitionFile.Path == absPath {
+			return t
+		}
+	}
+
+	for _, t := range p.Tasks {
+		if t.ExecutableFile.Path == absPath {
+			return t
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/git"
+	"github.com/datablast-analytics/blast-cli/pkg/lint"
+	"github.com/datablast-analytics/blast-cli/pkg/path"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/urfave/cli/v2"
+)
+
+func Lint(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "validate",
+		Usage:     "validate the blast pipeline configuration for all the pipelines in a given directory",
+		ArgsUsage: "[path to pipelines]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "no-cache",
+				Usage: "validate all the queries, ignoring the results cached by the previous runs",
+			},
+			&cli.IntFlag{
+				Name:  "jobs",
+				Usage: "the number of pipelines and rules to process in parallel, defaults to the number of CPUs",
+			},
+			&cli.IntFlag{
+				Name:  "warehouse-concurrency",
+				Usage: "the maximum number of queries to validate against the warehouses at the same time",
+			},
+			&cli.DurationFlag{
+				Name:  "query-timeout",
+				Usage: "the maximum duration to wait for a single query to be validated, e.g. 30s",
+			},
+			&cli.IntFlag{
+				Name:  "max-attempts",
+				Usage: "the number of times to try validating a query when the warehouse returns a transient error",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "the maximum duration for the whole validation, e.g. 10m",
+			},
+			&cli.StringFlag{
+				Name:  "changed-since",
+				Usage: "only validate the pipelines and tasks that changed since the given git ref, e.g. origin/main",
+			},
+			filterFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			rootPath := c.Args().Get(0)
+			if rootPath == "" {
+				rootPath = defaultPipelinePath
+			}
+
+			builder, err := newCollectingPipelineBuilder(rootPath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			cfg := builder.cfg
+
+			if c.Bool("no-cache") {
+				cfg.Cache.Disabled = true
+			}
+
+			if c.IsSet("jobs") {
+				cfg.Validation.Jobs = c.Int("jobs")
+			}
+
+			if c.IsSet("warehouse-concurrency") {
+				cfg.Validation.WarehouseConcurrency = c.Int("warehouse-concurrency")
+			}
+
+			if c.IsSet("query-timeout") {
+				cfg.Validation.QueryTimeout = c.Duration("query-timeout").String()
+			}
+
+			if c.IsSet("max-attempts") {
+				cfg.Validation.MaxAttempts = c.Int("max-attempts")
+			}
+
+			if c.IsSet("timeout") {
+				cfg.Validation.Timeout = c.Duration("timeout").String()
+			}
+
+			timeout, err := cfg.ValidationTimeout()
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			rules, err := lint.GetRules(logger, cfg, builder.taskTypes)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while linting the pipelines: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			linter := lint.NewLinter(path.GetPipelinePaths, builder, rules, logger)
+			if cfg.Validation.Jobs > 0 {
+				linter.SetJobs(cfg.Validation.Jobs)
+			}
+
+			if ref := c.String("changed-since"); ref != "" {
+				changedFiles, err := git.ChangedFilesSince(rootPath, ref)
+				if err != nil {
+					errorPrinter.Printf("An error occurred while finding the changed files: %v\n", err)
+					return cli.Exit("", 1)
+				}
+
+				logger.Debugf("found %d changed files since '%s'", len(changedFiles), ref)
+				linter.LimitToChangedFiles(changedFiles)
+			}
+
+			filter, err := pipeline.ParseTaskFilters(c.StringSlice("filter"))
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+			linter.FilterTasks(filter)
+
+			ctx, cancel := interruptibleContext(c.Context, timeout)
+			defer cancel()
+
+			result, err := linter.Lint(ctx, rootPath, pipelineDefinitionFile)
+			if err != nil {
+				if ctx.Err() != nil {
+					errorPrinter.Printf("The validation is interrupted before the pipelines are built: %v\n", ctx.Err())
+					return cli.Exit("", 1)
+				}
+
+				errorPrinter.Printf("An error occurred while linting the pipelines: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			if len(result.Pipelines) == 0 {
+				successPrinter.Println("No pipelines are affected by the changes or match the filters")
+				return nil
+			}
+
+			printer := lint.Printer{
+				RootCheckPath: rootPath,
+			}
+			printer.PrintIssues(result)
+
+			if result.Interrupted {
+				errorPrinter.Printf("\nThe validation is interrupted before all the checks are completed (%v), the results above are partial.\n", ctx.Err())
+				return cli.Exit("", 1)
+			}
+
+			if result.HasErrors() {
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+// interruptibleContext returns a context that is cancelled on the first SIGINT or SIGTERM, which allows the in-flight
+// queries to be stopped and a partial report to be printed. A second signal terminates the process immediately.
+func interruptibleContext(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if timeout <= 0 {
+		return ctx, stop
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+
+	return timeoutCtx, func() {
+		cancel()
+		stop()
+	}
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/datablast-analytics/blast-cli/pkg/inspect"
+	"github.com/datablast-analytics/blast-cli/pkg/path"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+func List(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "list the pipelines and the tasks under the given path",
+		Subcommands: []*cli.Command{
+			listPipelines(isDebug),
+			listTasks(isDebug),
+		},
+	}
+}
+
+func outputFlag() *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:    "output",
+		Aliases: []string{"o"},
+		Value:   outputTable,
+		Usage:   fmt.Sprintf("the output format, either '%s' or '%s'", outputTable, outputJSON),
+	}
+}
+
+func filterFlag() *cli.StringSliceFlag {
+	return &cli.StringSliceFlag{
+		Name:  "filter",
+		Usage: "only include the tasks matching the filter, either 'tag:<tag>' or 'owner:<owner>', can be repeated",
+	}
+}
+
+// filterTasks keeps only the pipelines with at least one task matching the filters given with the `--filter` flag,
+// along with their matching tasks.
+func filterTasks(c *cli.Context, pipelines []*pipeline.Pipeline) ([]*pipeline.Pipeline, error) {
+	filter, err := pipeline.ParseTaskFilters(c.StringSlice("filter"))
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*pipeline.Pipeline, 0, len(pipelines))
+	for _, p := range pipelines {
+		if subset, matched := filter.Apply(p); matched {
+			filtered = append(filtered, subset)
+		}
+	}
+
+	return filtered, nil
+}
+
+func listPipelines(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "pipelines",
+		Usage:     "list the pipelines",
+		ArgsUsage: "[path to pipelines]",
+		Flags:     []cli.Flag{outputFlag(), filterFlag()},
+		Action: func(c *cli.Context) error {
+			rootPath := c.Args().Get(0)
+			if rootPath == "" {
+				rootPath = defaultPipelinePath
+			}
+
+			builder, err := newPipelineBuilder(rootPath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			pipelines, err := buildPipelines(makeLogger(*isDebug), builder, rootPath)
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			pipelines, err = filterTasks(c, pipelines)
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			doc := inspect.NewPipelineListDocument(pipelines, builder.taskTypes)
+			switch c.String("output") {
+			case outputJSON:
+				err = inspect.Write(os.Stdout, doc)
+			case outputTable:
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+				_, _ = fmt.Fprintln(w, "NAME\tSCHEDULE\tOWNER\tTASKS\tPATH")
+				for _, p := range doc.Pipelines {
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", p.Name, p.Schedule, p.Owner, len(p.Tasks), relativePath(rootPath, filepath.Dir(p.DefinitionFile)))
+				}
+				err = w.Flush()
+			default:
+				err = errors.Errorf("unknown output format '%s'", c.String("output"))
+			}
+
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+func listTasks(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "tasks",
+		Usage:     "list the tasks of all the pipelines, or of a single one",
+		ArgsUsage: "[path to pipelines]",
+		Flags: []cli.Flag{
+			outputFlag(),
+			filterFlag(),
+			&cli.StringFlag{
+				Name:  "pipeline",
+				Usage: "the name of the pipeline to list the tasks of",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			rootPath := c.Args().Get(0)
+			if rootPath == "" {
+				rootPath = defaultPipelinePath
+			}
+
+			builder, err := newPipelineBuilder(rootPath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			pipelines, err := buildPipelines(makeLogger(*isDebug), builder, rootPath)
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			if name := c.String("pipeline"); name != "" {
+				pipelines = filterPipelines(pipelines, name)
+				if len(pipelines) == 0 {
+					errorPrinter.Printf("There is no pipeline named '%s' in '%s'\n", name, rootPath)
+					return cli.Exit("", 1)
+				}
+			}
+
+			pipelines, err = filterTasks(c, pipelines)
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			doc := inspect.NewTaskListDocument(pipelines, builder.taskTypes)
+			switch c.String("output") {
+			case outputJSON:
+				err = inspect.Write(os.Stdout, doc)
+			case outputTable:
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+				_, _ = fmt.Fprintln(w, "PIPELINE\tNAME\tTYPE\tOWNER\tTAGS\tDEFINITION\tDEPENDS ON\tPATH")
+				for _, t := range doc.Tasks {
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", t.Pipeline, t.Name, t.Type, t.Owner, strings.Join(t.Tags, ", "), t.Definition.Type, strings.Join(t.DependsOn, ", "), relativePath(rootPath, t.Definition.Path))
+				}
+				err = w.Flush()
+			default:
+				err = errors.Errorf("unknown output format '%s'", c.String("output"))
+			}
+
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+// buildPipelines builds all the pipelines under the given path, in the order of their paths.
+func buildPipelines(logger *zap.SugaredLogger, builder pipelineBuilder, rootPath string) ([]*pipeline.Pipeline, error) {
+	pipelinePaths, err := path.GetPipelinePaths(rootPath, pipelineDefinitionFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "an error occurred while finding the pipelines")
+	}
+	sort.Strings(pipelinePaths)
+
+	pipelines := make([]*pipeline.Pipeline, 0, len(pipelinePaths))
+	for _, pipelinePath := range pipelinePaths {
+		logger.Debugf("creating pipeline from path '%s'", pipelinePath)
+
+		p, err := builder.CreatePipelineFromPath(pipelinePath)
+		if err != nil {
+			return nil, errors.Wrapf(err, "an error occurred while creating the pipeline from path '%s'", pipelinePath)
+		}
+
+		pipelines = append(pipelines, p)
+	}
+
+	return pipelines, nil
+}
+
+func filterPipelines(pipelines []*pipeline.Pipeline, name string) []*pipeline.Pipeline {
+	filtered := make([]*pipeline.Pipeline, 0, 1)
+	for _, p := range pipelines {
+		if p.Name == name {
+			filtered = append(filtered, p)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"sort"
+
+	"github.com/datablast-analytics/blast-cli/pkg/migrate"
+	"github.com/datablast-analytics/blast-cli/pkg/path"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v2"
+)
+
+func Migrate(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "migrate",
+		Usage:     "convert the tasks between the comment annotations and the task.yml definitions",
+		ArgsUsage: "[path to pipelines]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "to",
+				Required: true,
+				Usage:    "the format to convert the tasks to, either 'yaml' or 'comments'",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			to, err := migrate.ParseFormat(c.String("to"))
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			rootPath := c.Args().Get(0)
+			if rootPath == "" {
+				rootPath = defaultPipelinePath
+			}
+
+			pipelinePaths, err := path.GetPipelinePaths(rootPath, pipelineDefinitionFile)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while finding the pipelines: %v\n", err)
+				return cli.Exit("", 1)
+			}
+			sort.Strings(pipelinePaths)
+
+			builder, err := newPipelineBuilder(rootPath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+			migrator := migrate.NewMigrator(afero.NewOsFs(), defaultTasksPath)
+			migrator.SetCommentSyntaxes(builder.syntaxes)
+
+			hasErrors := false
+			for _, pipelinePath := range pipelinePaths {
+				logger.Debugf("creating pipeline from path '%s'", pipelinePath)
+
+				p, err := builder.CreatePipelineFromPath(pipelinePath)
+				if err != nil {
+					errorPrinter.Printf("An error occurred while creating the pipeline from path '%s': %v\n", pipelinePath, err)
+					return cli.Exit("", 1)
+				}
+
+				pipelinePrinter.Printf("\nPipeline: %s %s\n", p.Name, faint("("+relativePath(rootPath, pipelinePath)+")"))
+
+				converted := 0
+				for _, task := range p.Tasks {
+					change, err := migrator.Plan(p, task, to)
+					if err != nil {
+						errorPrinter.Printf("  Cannot convert the task '%s' %s: %v\n", task.Name, faint("("+p.RelativeTaskPath(task)+")"), err)
+						hasErrors = true
+						continue
+					}
+
+					if change == nil {
+						continue
+					}
+
+					if err := migrator.Apply(change); err != nil {
+						errorPrinter.Printf("  Failed to convert the task '%s': %v\n", task.Name, err)
+						hasErrors = true
+						continue
+					}
+
+					converted++
+					successPrinter.Printf("  Converted the task '%s' %s\n", task.Name, faint("("+p.RelativeTaskPath(task)+")"))
+				}
+
+				if converted == 0 {
+					successPrinter.Printf("  Nothing to convert\n")
+				}
+			}
+
+			if hasErrors {
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/datablast-analytics/blast-cli/pkg/scaffold"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v2"
+)
+
+func New(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:  "new",
+		Usage: "create new assets out of templates",
+		Subcommands: []*cli.Command{
+			newTask(isDebug),
+		},
+	}
+}
+
+func newTask(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "task",
+		Usage:     "create a new task, in the tasks directory of the current pipeline unless a directory is given",
+		ArgsUsage: "[directory to create the task in]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "name",
+				Required: true,
+				Usage:    "the name of the task",
+			},
+			&cli.StringFlag{
+				Name:     "type",
+				Required: true,
+				Usage:    fmt.Sprintf("the type of the task, one of: %s, or a custom type from the project config", strings.Join(scaffold.TaskTypes(), ", ")),
+			},
+			&cli.StringFlag{
+				Name:  "depends",
+				Usage: "a comma-separated list of the tasks this task depends on",
+			},
+			&cli.StringFlag{
+				Name:  "style",
+				Usage: fmt.Sprintf("how the task is defined, either '%s' or '%s', defaults to comments if the task type supports them", scaffold.StyleComment, scaffold.StyleYaml),
+			},
+		},
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			tasksDir := c.Args().Get(0)
+			if tasksDir == "" {
+				pipelineRoot, err := findPipelineRoot(defaultPipelinePath)
+				if err != nil {
+					errorPrinter.Printf("%v, please give the directory to create the task in\n", err)
+					return cli.Exit("", 1)
+				}
+
+				tasksDir = filepath.Join(pipelineRoot, defaultTasksPath)
+			}
+
+			tasksDir, err := filepath.Abs(tasksDir)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while resolving the tasks directory: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			builder, err := newPipelineBuilder(tasksDir)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			opts := scaffold.TaskOptions{
+				Name:      c.String("name"),
+				Type:      c.String("type"),
+				DependsOn: splitList(c.String("depends")),
+				Style:     c.String("style"),
+			}
+
+			logger.Debugf("creating task '%s' in '%s' with the templates in '%s'", opts.Name, tasksDir, builder.cfg.TemplatesDir())
+			scaffolder := scaffold.NewScaffolder(afero.NewOsFs(), builder.cfg.TemplatesDir())
+			scaffolder.SetTaskTypes(builder.taskTypes)
+			scaffolder.SetCommentSyntaxes(builder.syntaxes)
+
+			files, err := scaffolder.CreateTask(tasksDir, opts)
+			if err != nil {
+				errorPrinter.Printf("Failed to create the task: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			successPrinter.Printf("Created the task '%s'\n", opts.Name)
+			for _, file := range files {
+				successPrinter.Printf("  %s\n", faint(relativePath(defaultPipelinePath, file)))
+			}
+
+			return nil
+		},
+	}
+}
+
+// findPipelineRoot walks up from the given path until it finds the directory that contains the pipeline definition.
+func findPipelineRoot(startPath string) (string, error) {
+	dir, err := filepath.Abs(startPath)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, pipelineDefinitionFile)); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.Errorf("could not find a '%s' file in '%s' or its parents", pipelineDefinitionFile, startPath)
+		}
+
+		dir = parent
+	}
+}
+
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
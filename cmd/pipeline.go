@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultPipelinePath    = "."
+	pipelineDefinitionFile = "pipeline.yml"
+	defaultTasksPath       = "tasks"
+	defaultTaskFileName    = "task.yml"
+)
+
+type pipelineBuilder interface {
+	CreatePipelineFromPath(pathToPipeline string) (*pipeline.Pipeline, error)
+}
+
+// projectBuilder builds the pipelines of a project along with the config and the task types of the project, which
+// are loaded before building any pipeline since the custom types can add the comment syntaxes of their files.
+type projectBuilder struct {
+	pipelineBuilder
+	cfg       *config.Config
+	taskTypes *tasktype.Registry
+	syntaxes  pipeline.CommentSyntaxes
+}
+
+// newPipelineBuilder loads the project config from the given path or its parents, every command builds its pipelines
+// with it.
+func newPipelineBuilder(path string) (*projectBuilder, error) {
+	return newProjectBuilder(path, newBuilderConfig())
+}
+
+// newCollectingPipelineBuilder keeps building the pipelines when some of their files are broken, which allows the
+// linter to report them together with the rest of the issues.
+func newCollectingPipelineBuilder(path string) (*projectBuilder, error) {
+	builderConfig := newBuilderConfig()
+	builderConfig.CollectErrors = true
+
+	return newProjectBuilder(path, builderConfig)
+}
+
+func newProjectBuilder(path string, builderConfig pipeline.BuilderConfig) (*projectBuilder, error) {
+	cfg, err := config.LoadOrDefault(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the project config")
+	}
+
+	taskTypes, syntaxes, err := loadTaskTypes(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &projectBuilder{
+		pipelineBuilder: pipeline.NewBuilder(builderConfig, pipeline.CreateTaskFromYamlDefinition, syntaxes.CreateTaskFromFileComments),
+		cfg:             cfg,
+		taskTypes:       taskTypes,
+		syntaxes:        syntaxes,
+	}, nil
+}
+
+func newBuilderConfig() pipeline.BuilderConfig {
+	return pipeline.BuilderConfig{
+		PipelineFileName:   pipelineDefinitionFile,
+		TasksDirectoryName: defaultTasksPath,
+		TasksFileName:      defaultTaskFileName,
+	}
+}
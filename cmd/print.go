@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"path/filepath"
+
+	"github.com/fatih/color"
+)
+
+var (
+	faint           = color.New(color.Faint).SprintFunc()
+	pipelinePrinter = color.New(color.FgBlue, color.Bold)
+	errorPrinter    = color.New(color.FgRed, color.Bold)
+	successPrinter  = color.New(color.FgGreen)
+)
+
+func relativePath(rootPath, target string) string {
+	absRootPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		return target
+	}
+
+	relative, err := filepath.Rel(absRootPath, target)
+	if err != nil {
+		return target
+	}
+
+	return relative
+}
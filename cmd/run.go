@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/bigquery"
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/run"
+	"github.com/datablast-analytics/blast-cli/pkg/sensor"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+func Run(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "run",
+		Usage:     "run the tasks of a pipeline locally, following their execution policies",
+		ArgsUsage: "[path to the pipeline]",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:  "jobs",
+				Usage: "the maximum number of tasks running at the same time, defaults to the project config or the number of CPUs",
+			},
+			filterFlag(),
+		},
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			pipelinePath := c.Args().Get(0)
+			if pipelinePath == "" {
+				pipelinePath = defaultPipelinePath
+			}
+
+			// allow passing the pipeline definition file directly, e.g. with shell completion
+			if filepath.Base(pipelinePath) == pipelineDefinitionFile {
+				pipelinePath = filepath.Dir(pipelinePath)
+			}
+
+			builder, err := newPipelineBuilder(pipelinePath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			cfg := builder.cfg
+
+			if c.IsSet("jobs") {
+				cfg.Run.Jobs = c.Int("jobs")
+			}
+
+			logger.Debugf("creating pipeline from path '%s'", pipelinePath)
+			p, err := builder.CreatePipelineFromPath(pipelinePath)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while creating the pipeline from path '%s': %v\n", pipelinePath, err)
+				return cli.Exit("", 1)
+			}
+
+			filter, err := pipeline.ParseTaskFilters(c.StringSlice("filter"))
+			if err != nil {
+				errorPrinter.Printf("%v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			p, matched := filter.Apply(p)
+			if !matched {
+				errorPrinter.Printf("None of the tasks in the pipeline '%s' match the filters\n", p.Name)
+				return cli.Exit("", 1)
+			}
+
+			ctx, cancel := interruptibleContext(c.Context, 0)
+			defer cancel()
+
+			executors, err := runExecutors(ctx, logger, cfg, builder.taskTypes, p)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while preparing the tasks: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			runner := &run.Runner{
+				Executors: executors,
+				Logger:    logger,
+				Output:    os.Stdout,
+				Jobs:      cfg.Run.Jobs,
+				Pools:     cfg.Run.Pools,
+			}
+
+			pipelinePrinter.Printf("Running the pipeline '%s'\n\n", p.Name)
+			result, err := runner.Run(ctx, p)
+			if err != nil {
+				errorPrinter.Printf("An error occurred while running the pipeline '%s': %v\n", p.Name, err)
+				return cli.Exit("", 1)
+			}
+
+			printRunResult(result)
+			if result.HasFailures() {
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
+
+// runExecutors returns the executors for the task types that can run locally, including the custom types with a
+// command. The clients of the sensors are created only when the pipeline has sensors that need them, since creating
+// them requires credentials.
+func runExecutors(ctx context.Context, logger *zap.SugaredLogger, cfg *config.Config, taskTypes *tasktype.Registry, p *pipeline.Pipeline) (map[string]run.Executor, error) {
+	executors := run.DefaultExecutors()
+	for _, name := range taskTypes.Names() {
+		taskType := taskTypes.Get(name)
+		if taskType.Command == "" {
+			continue
+		}
+
+		executor, err := run.NewCommandExecutor(taskType)
+		if err != nil {
+			return nil, err
+		}
+
+		executors[name] = executor
+	}
+
+	if dir := cfg.SensorStorageDir(); dir != "" {
+		logger.Debugf("the S3 and GCS sensors use the local directory '%s'", dir)
+		store := &sensor.FileStore{Fs: afero.NewOsFs(), Root: dir}
+		executors[sensor.TypeS3Key] = sensor.NewS3KeySensor(store)
+		executors[sensor.TypeGCSPrefix] = sensor.NewGCSPrefixSensor(store)
+	} else {
+		if hasTaskType(p, sensor.TypeS3Key) {
+			s3Config, err := sensor.LoadS3ConfigFromEnv()
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to load the S3 config from env")
+			}
+			s3Config.Endpoint = cfg.Run.Sensors.S3Endpoint
+
+			executors[sensor.TypeS3Key] = sensor.NewS3KeySensor(sensor.NewS3Store(s3Config))
+		}
+
+		if hasTaskType(p, sensor.TypeGCSPrefix) {
+			store, err := sensor.NewGCSStore(ctx, cfg.Run.Sensors.GCSEndpoint)
+			if err != nil {
+				return nil, err
+			}
+
+			executors[sensor.TypeGCSPrefix] = sensor.NewGCSPrefixSensor(store)
+		}
+	}
+
+	if !hasTaskType(p, sensor.TypeBigqueryTable) && !hasTaskType(p, sensor.TypeBigqueryQuery) {
+		return executors, nil
+	}
+
+	bqConfig, err := bigquery.LoadConfigFromEnv()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the BigQuery config from env")
+	}
+
+	if !bqConfig.IsValid() {
+		logger.Debug("no BigQuery credentials found in env variables, the BigQuery sensors cannot run")
+		return executors, nil
+	}
+
+	bq, err := bigquery.NewDB(bqConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	executors[sensor.TypeBigqueryTable] = sensor.NewTableSensor(bq)
+	executors[sensor.TypeBigqueryQuery] = sensor.NewQuerySensor(bq)
+
+	return executors, nil
+}
+
+func hasTaskType(p *pipeline.Pipeline, taskType string) bool {
+	for _, task := range p.Tasks {
+		if task.Type == taskType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func printRunResult(result *run.Result) {
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	for _, task := range result.Tasks {
+		status := successPrinter.Sprint(task.Status)
+		if task.Status != run.StatusSucceeded {
+			status = errorPrinter.Sprint(task.Status)
+		}
+
+		details := ""
+		if task.Attempts > 1 {
+			details = fmt.Sprintf("%d attempts", task.Attempts)
+		}
+		if task.SLAMissed {
+			details = joinDetails(details, errorPrinter.Sprint("missed the SLA"))
+		}
+		if task.Err != nil && task.Status != run.StatusSucceeded {
+			details = joinDetails(details, task.Err.Error())
+		}
+
+		_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", task.Task.Name, status, task.Duration().Round(time.Millisecond), faint(details))
+	}
+	_ = w.Flush()
+}
+
+func joinDetails(details, detail string) string {
+	if details == "" {
+		return detail
+	}
+
+	return details + ", " + detail
+}
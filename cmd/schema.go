@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/datablast-analytics/blast-cli/pkg/jsonschema"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	schemaPipeline = "pipeline"
+	schemaTask     = "task"
+)
+
+func Schema(isDebug *bool) *cli.Command {
+	return &cli.Command{
+		Name:      "schema",
+		Usage:     "print the JSON Schema of the pipeline.yml or the task.yml files, which can be used by the editors",
+		ArgsUsage: "[pipeline|task]",
+		Action: func(c *cli.Context) error {
+			logger := makeLogger(*isDebug)
+
+			var schema *jsonschema.Schema
+			switch c.Args().Get(0) {
+			case schemaPipeline:
+				schema = pipeline.PipelineSchema()
+			case schemaTask:
+				builder, err := newPipelineBuilder(defaultPipelinePath)
+				if err != nil {
+					errorPrinter.Printf("An error occurred while reading the project config: %v\n", err)
+					return cli.Exit("", 1)
+				}
+
+				schema = pipeline.TaskDefinitionSchema(builder.taskTypes.Names())
+			default:
+				errorPrinter.Printf("Please give the schema to print, either '%s' or '%s'\n", schemaPipeline, schemaTask)
+				return cli.Exit("", 1)
+			}
+
+			logger.Debugf("printing the schema for '%s'", c.Args().Get(0))
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(schema); err != nil {
+				errorPrinter.Printf("An error occurred while printing the schema: %v\n", err)
+				return cli.Exit("", 1)
+			}
+
+			return nil
+		},
+	}
+}
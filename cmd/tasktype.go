@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/pkg/errors"
+)
+
+// loadTaskTypes returns the built-in task types along with the custom ones from the project config, and the comment
+// syntaxes the tasks of the project can be defined with. The custom types add the syntaxes for the extensions that
+// do not have one, and become the default type of the extensions that are not used by any other type.
+func loadTaskTypes(cfg *config.Config) (*tasktype.Registry, pipeline.CommentSyntaxes, error) {
+	registry, err := tasktype.Load(cfg.TaskTypes)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to load the task types")
+	}
+
+	typesPerExtension := make(map[string]int)
+	for _, name := range registry.Names() {
+		if extension := registry.Get(name).Extension; extension != "" {
+			typesPerExtension[strings.ToLower(extension)]++
+		}
+	}
+
+	names := make([]string, 0, len(cfg.TaskTypes))
+	for name := range cfg.TaskTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	syntaxes := pipeline.BuiltinCommentSyntaxes()
+	for _, name := range names {
+		taskType := cfg.TaskTypes[name]
+		syntax, ok := syntaxes.For(taskType.Extension)
+		switch {
+		case !ok && taskType.CommentPrefix != "":
+			syntaxes.Set(taskType.Extension, pipeline.CommentSyntax{
+				LinePrefix:  taskType.CommentPrefix,
+				DefaultType: name,
+			})
+		case ok && syntax.DefaultType == "" && typesPerExtension[strings.ToLower(taskType.Extension)] == 1:
+			syntax.DefaultType = name
+			syntaxes.Set(taskType.Extension, syntax)
+		}
+	}
+
+	return registry, syntaxes, nil
+}
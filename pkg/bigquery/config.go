@@ -1,6 +1,14 @@
 package bigquery
 
-import "github.com/kelseyhightower/envconfig"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/kelseyhightower/envconfig"
+)
 
 type Config struct {
 	ProjectID           string `envconfig:"BIGQUERY_PROJECT"`
@@ -12,6 +20,31 @@ func (c Config) IsValid() bool {
 	return c.ProjectID != "" && c.CredentialsFilePath != ""
 }
 
+// Identity returns a string that identifies the environment the queries are validated against. The credentials are
+// part of it since a query that is valid for one account might not be for another one without access to the tables.
+func (c Config) Identity() string {
+	return fmt.Sprintf("bigquery:%s:%s:%s", c.ProjectID, c.Location, c.credentialsIdentity())
+}
+
+// credentialsIdentity returns the email of the service account, or a hash of the credentials file for the other types
+// of credentials, so that the secrets in the file are never used as they are.
+func (c Config) credentialsIdentity() string {
+	content, err := os.ReadFile(c.CredentialsFilePath)
+	if err != nil {
+		return c.CredentialsFilePath
+	}
+
+	var credentials struct {
+		ClientEmail string `json:"client_email"`
+	}
+	if err := json.Unmarshal(content, &credentials); err == nil && credentials.ClientEmail != "" {
+		return credentials.ClientEmail
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
 func LoadConfigFromEnv() (*Config, error) {
 	var cfg Config
 	err := envconfig.Process("", &cfg)
@@ -0,0 +1,56 @@
+package bigquery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Identity(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+		return file
+	}
+
+	userCredentials := `{"type": "authorized_user", "refresh_token": "secret"}`
+	userSum := sha256.Sum256([]byte(userCredentials))
+
+	tests := []struct {
+		name        string
+		credentials string
+		want        string
+	}{
+		{
+			name:        "service accounts are identified by their email",
+			credentials: write("service-account.json", `{"type": "service_account", "client_email": "validator@my-project.iam.gserviceaccount.com"}`),
+			want:        "bigquery:my-project:EU:validator@my-project.iam.gserviceaccount.com",
+		},
+		{
+			name:        "other credentials are identified by the hash of the file",
+			credentials: write("user.json", userCredentials),
+			want:        "bigquery:my-project:EU:" + hex.EncodeToString(userSum[:]),
+		},
+		{
+			name:        "missing files are identified by their path",
+			credentials: filepath.Join(dir, "missing.json"),
+			want:        "bigquery:my-project:EU:" + filepath.Join(dir, "missing.json"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := Config{ProjectID: "my-project", Location: "EU", CredentialsFilePath: tt.credentials}
+			require.Equal(t, tt.want, c.Identity())
+		})
+	}
+}
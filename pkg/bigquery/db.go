@@ -8,6 +8,7 @@ import (
 	"github.com/datablast-analytics/blast-cli/pkg/query"
 	"github.com/pkg/errors"
 	"google.golang.org/api/googleapi"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
@@ -42,6 +43,63 @@ func NewDB(c *Config) (*DB, error) {
 }
 
 func (d DB) IsValid(ctx context.Context, query *query.Query) (bool, error) {
+	_, err := d.dryRun(ctx, query)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// BytesProcessed returns the number of bytes the query would scan if it were executed, as reported by a dry run.
+func (d DB) BytesProcessed(ctx context.Context, query *query.Query) (int64, error) {
+	status, err := d.dryRun(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	if status.Statistics == nil {
+		return 0, errors.New("the dry run did not return any statistics")
+	}
+
+	return status.Statistics.TotalBytesProcessed, nil
+}
+
+// TableExists checks if the table exists, a missing dataset is reported the same way as a missing table.
+func (d DB) TableExists(ctx context.Context, projectID, datasetID, tableID string) (bool, error) {
+	_, err := d.client.DatasetInProject(projectID, datasetID).Table(tableID).Metadata(ctx)
+	if err == nil {
+		return true, nil
+	}
+
+	var googleError *googleapi.Error
+	if errors.As(err, &googleError) && googleError.Code == 404 {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// FirstValue runs the query and returns the first column of the first row, nil if the query does not return any rows.
+func (d DB) FirstValue(ctx context.Context, sql string) (interface{}, error) {
+	rows, err := d.client.Query(sql).Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var row []bigquery.Value
+	err = rows.Next(&row)
+	if errors.Is(err, iterator.Done) || len(row) == 0 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return row[0], nil
+}
+
+func (d DB) dryRun(ctx context.Context, query *query.Query) (*bigquery.JobStatus, error) {
 	q := d.client.Query(query.ToDryRunQuery())
 	q.DryRun = true
 
@@ -49,20 +107,20 @@ func (d DB) IsValid(ctx context.Context, query *query.Query) (bool, error) {
 	if err != nil {
 		var googleError *googleapi.Error
 		if !errors.As(err, &googleError) {
-			return false, err
+			return nil, err
 		}
 
 		if googleError.Code == 404 {
-			return false, fmt.Errorf("%s", googleError.Message)
+			return nil, fmt.Errorf("%s", googleError.Message)
 		}
 
-		return false, googleError
+		return nil, googleError
 	}
 
 	status := job.LastStatus()
 	if err := status.Err(); err != nil {
-		return false, err
+		return nil, err
 	}
 
-	return true, nil
+	return status, nil
 }
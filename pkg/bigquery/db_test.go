@@ -141,3 +141,186 @@ func TestDB_IsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestDB_BytesProcessed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		response   any
+		statusCode int
+		want       int64
+		err        error
+	}{
+		{
+			name: "validation errors are returned",
+			response: &bigquery2.Job{
+				JobReference: &bigquery2.JobReference{
+					JobId: "job-id",
+				},
+				Status: &bigquery2.JobStatus{
+					ErrorResult: &bigquery2.ErrorProto{
+						Location: "some location",
+						Message:  "some message",
+						Reason:   "some reason",
+					},
+					State: "DONE",
+				},
+			},
+			statusCode: http.StatusOK,
+			err: &bigquery.Error{
+				Location: "some location",
+				Message:  "some message",
+				Reason:   "some reason",
+			},
+		},
+		{
+			name: "missing statistics are reported",
+			response: &bigquery2.Job{
+				JobReference: &bigquery2.JobReference{
+					JobId: "job-id",
+				},
+				Status: &bigquery2.JobStatus{
+					State: "DONE",
+				},
+			},
+			statusCode: http.StatusOK,
+			err:        errors.New("the dry run did not return any statistics"),
+		},
+		{
+			name: "bytes processed are returned",
+			response: &bigquery2.Job{
+				JobReference: &bigquery2.JobReference{
+					JobId: "job-id",
+				},
+				Status: &bigquery2.JobStatus{
+					State: "DONE",
+				},
+				Statistics: &bigquery2.JobStatistics{
+					TotalBytesProcessed: 1073741824,
+				},
+			},
+			statusCode: http.StatusOK,
+			want:       1073741824,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response, err := json.Marshal(tt.response)
+				assert.NoError(t, err)
+
+				w.WriteHeader(tt.statusCode)
+				_, err = w.Write(response)
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client, err := bigquery.NewClient(
+				context.Background(),
+				"some-project-id",
+				option.WithEndpoint(server.URL),
+				option.WithCredentials(&google.Credentials{
+					ProjectID: "some-project-id",
+					TokenSource: oauth2.StaticTokenSource(&oauth2.Token{
+						AccessToken: "some-token",
+					}),
+				}),
+			)
+			assert.NoError(t, err)
+
+			d := DB{client: client}
+
+			got, err := d.BytesProcessed(context.Background(), &query.Query{Query: "select * from users"})
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err.Error())
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDB_TableExists(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		response   any
+		statusCode int
+		want       bool
+		err        string
+	}{
+		{
+			name: "existing table",
+			response: &bigquery2.Table{
+				TableReference: &bigquery2.TableReference{ProjectId: "some-project-id", DatasetId: "raw", TableId: "orders"},
+			},
+			statusCode: http.StatusOK,
+			want:       true,
+		},
+		{
+			name: "missing table",
+			response: map[string]interface{}{
+				"error": googleapi.Error{Code: 404, Message: "Not found: Table some-project-id:raw.orders"},
+			},
+			statusCode: http.StatusNotFound,
+			want:       false,
+		},
+		{
+			name: "other errors are returned",
+			response: map[string]interface{}{
+				"error": googleapi.Error{Code: 403, Message: "Access Denied"},
+			},
+			statusCode: http.StatusForbidden,
+			err:        "googleapi: Error 403: Access Denied",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/projects/some-project-id/datasets/raw/tables/orders", r.URL.Path)
+
+				response, err := json.Marshal(tt.response)
+				assert.NoError(t, err)
+
+				w.WriteHeader(tt.statusCode)
+				_, err = w.Write(response)
+				assert.NoError(t, err)
+			}))
+			defer server.Close()
+
+			client, err := bigquery.NewClient(
+				context.Background(),
+				"some-project-id",
+				option.WithEndpoint(server.URL),
+				option.WithCredentials(&google.Credentials{
+					ProjectID: "some-project-id",
+					TokenSource: oauth2.StaticTokenSource(&oauth2.Token{
+						AccessToken: "some-token",
+					}),
+				}),
+			)
+			assert.NoError(t, err)
+
+			d := DB{client: client}
+
+			got, err := d.TableExists(context.Background(), "some-project-id", "raw", "orders")
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package bigquery
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+)
+
+// transientReasons are the error reasons BigQuery uses for the failures that are not caused by the query itself.
+// See https://cloud.google.com/bigquery/docs/error-messages for the full list.
+var transientReasons = map[string]bool{
+	"backendError":      true,
+	"internalError":     true,
+	"rateLimitExceeded": true,
+}
+
+// IsTransientError reports whether the error is caused by a temporary problem such as rate limits, server errors or
+// network failures, meaning that the same request may succeed if it is retried.
+func IsTransientError(err error) bool {
+	// the context errors satisfy net.Error as well, but retrying them would not help
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var googleError *googleapi.Error
+	if errors.As(err, &googleError) {
+		if googleError.Code == http.StatusTooManyRequests || googleError.Code >= http.StatusInternalServerError {
+			return true
+		}
+
+		for _, item := range googleError.Errors {
+			if transientReasons[item.Reason] {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var bqError *bigquery.Error
+	if errors.As(err, &bqError) {
+		return transientReasons[bqError.Reason]
+	}
+
+	var netError net.Error
+	if errors.As(err, &netError) {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,99 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"cloud.google.com/go/bigquery"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsTransientError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil is not transient",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "rate limits are transient",
+			err:  &googleapi.Error{Code: 429, Message: "too many requests"},
+			want: true,
+		},
+		{
+			name: "server errors are transient",
+			err:  &googleapi.Error{Code: 503, Message: "service unavailable"},
+			want: true,
+		},
+		{
+			name: "wrapped server errors are transient",
+			err:  pkgerrors.Wrap(&googleapi.Error{Code: 500}, "failed to run the query"),
+			want: true,
+		},
+		{
+			name: "rate limit reasons are transient even with a 403",
+			err: &googleapi.Error{
+				Code:   403,
+				Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}},
+			},
+			want: true,
+		},
+		{
+			name: "invalid queries are not transient",
+			err: &googleapi.Error{
+				Code:   400,
+				Errors: []googleapi.ErrorItem{{Reason: "invalidQuery"}},
+			},
+			want: false,
+		},
+		{
+			name: "job backend errors are transient",
+			err:  &bigquery.Error{Reason: "backendError"},
+			want: true,
+		},
+		{
+			name: "job query errors are not transient",
+			err:  &bigquery.Error{Reason: "invalidQuery"},
+			want: false,
+		},
+		{
+			name: "network errors are transient",
+			err:  &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			want: true,
+		},
+		{
+			name: "unexpected EOF is transient",
+			err:  io.ErrUnexpectedEOF,
+			want: true,
+		},
+		{
+			name: "context errors are not transient",
+			err:  context.DeadlineExceeded,
+			want: false,
+		},
+		{
+			name: "other errors are not transient",
+			err:  errors.New("Table not found"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, IsTransientError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+type entry struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FileCache is a persistent cache that keeps a single file per key on disk, which allows reusing the results across
+// separate runs of the CLI, e.g. in CI pipelines that restore the cache directory.
+type FileCache struct {
+	fs  afero.Fs
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+func NewFileCache(fs afero.Fs, dir string, ttl time.Duration) *FileCache {
+	return &FileCache{
+		fs:  fs,
+		dir: dir,
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+// Key builds a cache key out of the given parts, the parts are hashed so that the key can be safely used as a file name.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Has returns true if the key exists in the cache and it has not expired yet.
+func (c *FileCache) Has(key string) bool {
+	contents, err := afero.ReadFile(c.fs, c.pathFor(key))
+	if err != nil {
+		return false
+	}
+
+	var e entry
+	if err := json.Unmarshal(contents, &e); err != nil {
+		return false
+	}
+
+	return c.now().Sub(e.CreatedAt) < c.ttl
+}
+
+func (c *FileCache) Set(key string) error {
+	target := c.pathFor(key)
+	if err := c.fs.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return errors.Wrapf(err, "failed to create the cache directory for '%s'", target)
+	}
+
+	contents, err := json.Marshal(entry{CreatedAt: c.now()})
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize the cache entry")
+	}
+
+	// writing to a temporary file first ensures concurrent readers never see a partially written entry, the name is
+	// unique so that the workers caching the same query do not write to the same file
+	tmpFile, err := afero.TempFile(c.fs, filepath.Dir(target), filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create a temporary file for '%s'", target)
+	}
+
+	_, err = tmpFile.Write(contents)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = c.fs.Remove(tmpFile.Name())
+		return errors.Wrapf(err, "failed to write the cache entry to '%s'", tmpFile.Name())
+	}
+
+	if err := c.fs.Rename(tmpFile.Name(), target); err != nil {
+		_ = c.fs.Remove(tmpFile.Name())
+		return errors.Wrap(err, "failed to move the cache entry into place")
+	}
+
+	return nil
+}
+
+func (c *FileCache) pathFor(key string) string {
+	if len(key) < 2 {
+		return filepath.Join(c.dir, key)
+	}
+
+	return filepath.Join(c.dir, key[:2], key)
+}
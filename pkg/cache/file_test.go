@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCache(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewFileCache(afero.NewMemMapFs(), "/cache", time.Hour)
+	c.now = func() time.Time { return now }
+
+	key := Key("bigquery", "project:US", "select 1")
+	require.False(t, c.Has(key))
+
+	require.NoError(t, c.Set(key))
+	require.True(t, c.Has(key))
+	require.False(t, c.Has(Key("bigquery", "project:US", "select 2")))
+
+	now = now.Add(59 * time.Minute)
+	require.True(t, c.Has(key))
+
+	now = now.Add(2 * time.Minute)
+	require.False(t, c.Has(key))
+}
+
+func TestKey(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, Key("a", "b"), Key("a", "b"))
+	require.NotEqual(t, Key("ab", ""), Key("a", "b"))
+	require.Len(t, Key("some", "parts"), 64)
+}
+
+func TestFileCache_ConcurrentSet(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	c := NewFileCache(fs, "/cache", time.Hour)
+	key := Key("bigquery", "project:US", "select 1")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, c.Set(key))
+		}()
+	}
+	wg.Wait()
+
+	require.True(t, c.Has(key))
+
+	// the temporary files are all moved into place
+	files, err := afero.Glob(fs, "/cache/*/*")
+	require.NoError(t, err)
+	for _, file := range files {
+		require.NotContains(t, file, ".tmp")
+	}
+}
@@ -0,0 +1,362 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/path"
+	"github.com/pkg/errors"
+)
+
+// FileName is the name of the project-level configuration file. It is looked up in the given directory and its
+// parents, which allows placing a single file at the root of a repository that contains multiple pipelines.
+const FileName = ".blast.yml"
+
+const (
+	defaultCacheDirName = "blast"
+	defaultCacheTTL     = 24 * time.Hour
+
+	defaultPluginTimeout = time.Minute
+
+	// DefaultTemplatesDir is where the scaffolding templates are looked up, relative to the config file.
+	DefaultTemplatesDir = ".blast/templates"
+)
+
+type Config struct {
+	// Path is the absolute path of the file the config was loaded from, it is empty for the default config.
+	Path string `yaml:"-"`
+
+	Cost       Cost       `yaml:"cost"`
+	Cache      Cache      `yaml:"cache"`
+	Validation Validation `yaml:"validation"`
+	Scaffold   Scaffold   `yaml:"scaffold"`
+	Metadata   Metadata   `yaml:"metadata"`
+	Run        Run        `yaml:"run"`
+
+	// TaskTypes declares the custom task types by their names, they are validated, scaffolded and run the same way as
+	// the built-in ones.
+	TaskTypes map[string]TaskType `yaml:"taskTypes"`
+}
+
+// TaskType declares a custom task type, e.g. a dbt model run with a command.
+type TaskType struct {
+	Parameters map[string]TaskTypeParameter `yaml:"parameters"`
+
+	// AcceptsAnyParameter allows the parameters that are not declared, e.g. when they are template variables.
+	AcceptsAnyParameter bool `yaml:"acceptsAnyParameter"`
+
+	// RequiresExecutableFile makes the `run` file of the tasks mandatory.
+	RequiresExecutableFile bool `yaml:"requiresExecutableFile"`
+
+	// Extension is the extension of the files the tasks run, e.g. `.sql`. The tasks can be scaffolded only when it is
+	// set, and they can be defined with comments when the extension supports them.
+	Extension string `yaml:"extension"`
+
+	// CommentPrefix starts the comment lines in the files with the extension, e.g. `--`. It is needed only for the
+	// extensions that do not support comments yet, the tasks in those files default to this type.
+	CommentPrefix string `yaml:"commentPrefix"`
+
+	// Command is a Go template for the shell command that runs the tasks locally, e.g.
+	// `dbt run --select {{ .Parameters.model }}`. The tasks cannot run locally without it.
+	Command string `yaml:"command"`
+}
+
+type TaskTypeParameter struct {
+	// Type is one of string, int, bool, duration, gcs-uri or s3-uri, it defaults to string.
+	Type     string `yaml:"type"`
+	Required bool   `yaml:"required"`
+	Default  string `yaml:"default"`
+}
+
+// Run configures the local runner used by the `run` command.
+type Run struct {
+	// Jobs is the maximum number of tasks running at the same time, defaults to the number of CPUs.
+	Jobs int `yaml:"jobs"`
+
+	// Pools limits the number of tasks running at the same time in each pool, e.g. `bigquery: 4`. The tasks in the
+	// pools that are not listed are limited only by Jobs.
+	Pools map[string]int `yaml:"pools"`
+
+	Sensors Sensors `yaml:"sensors"`
+}
+
+// Sensors configures where the S3 and GCS sensors look for the objects when the pipelines run locally, by default
+// they use the real services.
+type Sensors struct {
+	// StorageDir serves the buckets of both S3 and GCS from a local directory, the object `key` in the bucket `bucket`
+	// is the file `<StorageDir>/<bucket>/<key>`. It takes precedence over the endpoints.
+	StorageDir string `yaml:"storageDir"`
+
+	// S3Endpoint and GCSEndpoint replace the endpoints of the services, e.g. to use MinIO or a fake GCS server.
+	S3Endpoint  string `yaml:"s3Endpoint"`
+	GCSEndpoint string `yaml:"gcsEndpoint"`
+}
+
+// Metadata configures the lint rules about the owners and the tags of the tasks, the values inherited from the
+// pipelines count as well. All the rules are disabled by default.
+type Metadata struct {
+	RequireOwner bool `yaml:"requireOwner"`
+
+	// OwnerPattern is a regular expression the owners must match, e.g. `^[a-z.]+@example\.com$`.
+	OwnerPattern string `yaml:"ownerPattern"`
+
+	// AllowedTags is the list of the tags the tasks can use, any tag is allowed if it is empty.
+	AllowedTags []string `yaml:"allowedTags"`
+}
+
+// Scaffold configures the templates used by the `init` and `new task` commands, the files in the templates directory
+// override the built-in templates with the same path.
+type Scaffold struct {
+	TemplatesDir string `yaml:"templatesDir"`
+}
+
+type Validation struct {
+	// Jobs is the number of pipelines built and rules executed in parallel, defaults to the number of CPUs.
+	Jobs int `yaml:"jobs"`
+
+	// WarehouseConcurrency is the maximum number of queries validated at the same time across all the warehouses.
+	WarehouseConcurrency int `yaml:"warehouseConcurrency"`
+
+	// QueryTimeout is the maximum duration to wait for a single query to be validated, e.g. "30s".
+	QueryTimeout string `yaml:"queryTimeout"`
+
+	// MaxAttempts is the number of times a query is sent to the warehouse when it keeps failing with transient errors
+	// such as rate limits or network failures, including the first attempt.
+	MaxAttempts int `yaml:"maxAttempts"`
+
+	// Timeout is the maximum duration for the whole validation, the queries still running after it are reported as
+	// timed out.
+	Timeout string `yaml:"timeout"`
+
+	// Plugins are the rules implemented by external executables, they run along with the built-in rules.
+	Plugins []LintPlugin `yaml:"plugins"`
+
+	SQLStyle SQLStyle `yaml:"sqlStyle"`
+}
+
+// SQLStyle enables the lint rules about the style of the BigQuery and Snowflake queries, they read the queries without
+// rendering the template expressions. All the rules are disabled by default.
+type SQLStyle struct {
+	// NoSelectStar reports the queries that select * in their final output, the CTEs and the subqueries can still use it.
+	NoSelectStar bool `yaml:"noSelectStar"`
+
+	// QualifiedTableNames reports the tables referenced without their project and dataset in BigQuery, or without their
+	// database and schema in Snowflake.
+	QualifiedTableNames bool `yaml:"qualifiedTableNames"`
+
+	// NoHardcodedDates reports the date literals, which should be template variables such as {{ ds }} instead.
+	NoHardcodedDates bool `yaml:"noHardcodedDates"`
+
+	// NoCTASOrderBy reports the tables created from a query with ORDER BY but without LIMIT, the order is not kept.
+	NoCTASOrderBy bool `yaml:"noCtasOrderBy"`
+
+	// NoUnboundedDeletes reports the DELETE statements without a WHERE clause and the TRUNCATE statements.
+	NoUnboundedDeletes bool `yaml:"noUnboundedDeletes"`
+}
+
+// LintPlugin is an external rule: the executable receives every pipeline as a JSON document on stdin and writes the
+// issues it finds as JSON to stdout.
+type LintPlugin struct {
+	// Name identifies the rule in the output, it must be unique.
+	Name string `yaml:"name"`
+
+	// Command is the executable to run, the relative paths are resolved against the config file and the names without
+	// a path are looked up in PATH. Args are passed to it as they are.
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+
+	// Timeout is the maximum duration to wait for the plugin for a single pipeline, e.g. "30s", defaults to a minute.
+	Timeout string `yaml:"timeout"`
+
+	// TaskScoped limits the tasks sent to the plugin to the changed and the filtered ones, the same way as the
+	// built-in task rules. Otherwise the plugin always receives all the tasks of the pipelines.
+	TaskScoped bool `yaml:"taskScoped"`
+}
+
+// Cache configures the persistent cache for the query validation results.
+type Cache struct {
+	Disabled bool   `yaml:"disabled"`
+	Dir      string `yaml:"dir"`
+	TTL      string `yaml:"ttl"`
+}
+
+type Cost struct {
+	PricePerTiB float64     `yaml:"pricePerTiB"`
+	Budgets     CostBudgets `yaml:"budgets"`
+}
+
+// CostBudgets contains the maximum amount of bytes the queries are allowed to scan, written in a human-readable
+// format such as "10GB" or "1.5TiB".
+type CostBudgets struct {
+	DefaultTask     string                        `yaml:"defaultTask"`
+	DefaultPipeline string                        `yaml:"defaultPipeline"`
+	Pipelines       map[string]PipelineCostBudget `yaml:"pipelines"`
+}
+
+type PipelineCostBudget struct {
+	Total string            `yaml:"total"`
+	Tasks map[string]string `yaml:"tasks"`
+}
+
+// LoadOrDefault looks for the config file in the given path and its parents, and returns an empty config if there is
+// none.
+func LoadOrDefault(startPath string) (*Config, error) {
+	configPath, err := find(startPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if configPath == "" {
+		return &Config{}, nil
+	}
+
+	var config Config
+	err = path.ReadYaml(configPath, &config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the project config")
+	}
+	config.Path = configPath
+
+	return &config, nil
+}
+
+func find(startPath string) (string, error) {
+	dir, err := filepath.Abs(startPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get absolute path for '%s'", startPath)
+	}
+
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	for {
+		candidate := filepath.Join(dir, FileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
+
+// CacheDir returns the directory to keep the cache files in, relative paths are resolved against the config file.
+func (c *Config) CacheDir() (string, error) {
+	if c.Cache.Dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to find the user cache directory")
+		}
+
+		return filepath.Join(userCacheDir, defaultCacheDirName), nil
+	}
+
+	if filepath.IsAbs(c.Cache.Dir) || c.Path == "" {
+		return c.Cache.Dir, nil
+	}
+
+	return filepath.Join(filepath.Dir(c.Path), c.Cache.Dir), nil
+}
+
+// TemplatesDir returns the directory to look for the scaffolding templates in, relative paths are resolved against the
+// config file. It is empty when there is no config file and no directory is configured.
+func (c *Config) TemplatesDir() string {
+	dir := c.Scaffold.TemplatesDir
+	if dir == "" {
+		if c.Path == "" {
+			return ""
+		}
+
+		dir = DefaultTemplatesDir
+	}
+
+	if filepath.IsAbs(dir) || c.Path == "" {
+		return dir
+	}
+
+	return filepath.Join(filepath.Dir(c.Path), dir)
+}
+
+// SensorStorageDir returns the directory the sensors look for the objects in, relative paths are resolved against the
+// config file. It is empty when the sensors use the real services.
+func (c *Config) SensorStorageDir() string {
+	dir := c.Run.Sensors.StorageDir
+	if dir == "" || filepath.IsAbs(dir) || c.Path == "" {
+		return dir
+	}
+
+	return filepath.Join(filepath.Dir(c.Path), dir)
+}
+
+func (c *Config) CacheTTL() (time.Duration, error) {
+	if c.Cache.TTL == "" {
+		return defaultCacheTTL, nil
+	}
+
+	return parseDuration(c.Cache.TTL, "cache TTL")
+}
+
+// QueryTimeout returns the timeout for validating a single query, zero means there is no timeout.
+func (c *Config) QueryTimeout() (time.Duration, error) {
+	return parseDuration(c.Validation.QueryTimeout, "query timeout")
+}
+
+// ValidationTimeout returns the timeout for the whole validation, zero means there is no timeout.
+func (c *Config) ValidationTimeout() (time.Duration, error) {
+	return parseDuration(c.Validation.Timeout, "validation timeout")
+}
+
+// PluginCommand returns the executable to run for the given plugin, relative paths are resolved against the config
+// file while the names without a path are left to be looked up in PATH.
+func (c *Config) PluginCommand(plugin LintPlugin) string {
+	if c.Path == "" || filepath.IsAbs(plugin.Command) || !strings.ContainsAny(plugin.Command, `/\`) {
+		return plugin.Command
+	}
+
+	return filepath.Join(filepath.Dir(c.Path), plugin.Command)
+}
+
+// PluginTimeout returns the timeout for a single run of the given plugin.
+func (c *Config) PluginTimeout(plugin LintPlugin) (time.Duration, error) {
+	if plugin.Timeout == "" {
+		return defaultPluginTimeout, nil
+	}
+
+	return parseDuration(plugin.Timeout, fmt.Sprintf("timeout for the plugin '%s'", plugin.Name))
+}
+
+// OwnerPattern returns the compiled pattern for the owners, nil means the owners are not checked.
+func (c *Config) OwnerPattern() (*regexp.Regexp, error) {
+	if c.Metadata.OwnerPattern == "" {
+		return nil, nil
+	}
+
+	pattern, err := regexp.Compile(c.Metadata.OwnerPattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid owner pattern '%s'", c.Metadata.OwnerPattern)
+	}
+
+	return pattern, nil
+}
+
+func parseDuration(value, name string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s '%s'", name, value)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOrDefault(t *testing.T) {
+	t.Parallel()
+
+	absPath := func(path string) string {
+		absolutePath, _ := filepath.Abs(path)
+		return absolutePath
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    *Config
+		wantErr bool
+	}{
+		{
+			name: "config is found in the parent directory",
+			path: "testdata/project/pipelines/first-pipeline",
+			want: &Config{
+				Path: absPath("testdata/project/.blast.yml"),
+				Cost: Cost{
+					PricePerTiB: 5,
+					Budgets: CostBudgets{
+						DefaultTask: "10GB",
+						Pipelines: map[string]PipelineCostBudget{
+							"first-pipeline": {
+								Total: "1TB",
+								Tasks: map[string]string{
+									"some-task": "100GB",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "config is found in the same directory",
+			path: "testdata/project",
+			want: &Config{
+				Path: absPath("testdata/project/.blast.yml"),
+				Cost: Cost{
+					PricePerTiB: 5,
+					Budgets: CostBudgets{
+						DefaultTask: "10GB",
+						Pipelines: map[string]PipelineCostBudget{
+							"first-pipeline": {
+								Total: "1TB",
+								Tasks: map[string]string{
+									"some-task": "100GB",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "invalid config returns an error",
+			path:    "testdata/invalid",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := LoadOrDefault(tt.path)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfig_CacheDir(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{Path: "/repo/.blast.yml", Cache: Cache{Dir: ".blast/cache"}}
+	dir, err := c.CacheDir()
+	require.NoError(t, err)
+	require.Equal(t, "/repo/.blast/cache", dir)
+
+	c = &Config{Path: "/repo/.blast.yml", Cache: Cache{Dir: "/tmp/cache"}}
+	dir, err = c.CacheDir()
+	require.NoError(t, err)
+	require.Equal(t, "/tmp/cache", dir)
+}
+
+func TestConfig_TemplatesDir(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, (&Config{}).TemplatesDir())
+	require.Equal(t, "/repo/.blast/templates", (&Config{Path: "/repo/.blast.yml"}).TemplatesDir())
+	require.Equal(t, "/repo/scaffolds", (&Config{Path: "/repo/.blast.yml", Scaffold: Scaffold{TemplatesDir: "scaffolds"}}).TemplatesDir())
+	require.Equal(t, "/shared/templates", (&Config{Path: "/repo/.blast.yml", Scaffold: Scaffold{TemplatesDir: "/shared/templates"}}).TemplatesDir())
+}
+
+func TestConfig_SensorStorageDir(t *testing.T) {
+	t.Parallel()
+
+	require.Empty(t, (&Config{Path: "/repo/.blast.yml"}).SensorStorageDir())
+	require.Equal(t, "storage", (&Config{Run: Run{Sensors: Sensors{StorageDir: "storage"}}}).SensorStorageDir())
+	require.Equal(t, "/repo/.blast/storage", (&Config{Path: "/repo/.blast.yml", Run: Run{Sensors: Sensors{StorageDir: ".blast/storage"}}}).SensorStorageDir())
+	require.Equal(t, "/shared/storage", (&Config{Path: "/repo/.blast.yml", Run: Run{Sensors: Sensors{StorageDir: "/shared/storage"}}}).SensorStorageDir())
+}
+
+func TestConfig_CacheTTL(t *testing.T) {
+	t.Parallel()
+
+	ttl, err := (&Config{}).CacheTTL()
+	require.NoError(t, err)
+	require.Equal(t, defaultCacheTTL, ttl)
+
+	ttl, err = (&Config{Cache: Cache{TTL: "2h"}}).CacheTTL()
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Hour, ttl)
+
+	_, err = (&Config{Cache: Cache{TTL: "two hours"}}).CacheTTL()
+	require.Error(t, err)
+}
+
+func TestConfig_ValidationTimeouts(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{}
+	queryTimeout, err := c.QueryTimeout()
+	require.NoError(t, err)
+	require.Zero(t, queryTimeout)
+
+	c = &Config{Validation: Validation{QueryTimeout: "30s", Timeout: "10m"}}
+	queryTimeout, err = c.QueryTimeout()
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, queryTimeout)
+
+	timeout, err := c.ValidationTimeout()
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Minute, timeout)
+
+	_, err = (&Config{Validation: Validation{Timeout: "forever"}}).ValidationTimeout()
+	require.Error(t, err)
+}
+
+func TestConfig_Plugins(t *testing.T) {
+	t.Parallel()
+
+	c := &Config{Path: "/repo/.blast.yml"}
+	require.Equal(t, "/repo/tools/lint-naming", c.PluginCommand(LintPlugin{Command: "./tools/lint-naming"}))
+	require.Equal(t, "/opt/lint-naming", c.PluginCommand(LintPlugin{Command: "/opt/lint-naming"}))
+	require.Equal(t, "lint-naming", c.PluginCommand(LintPlugin{Command: "lint-naming"}))
+	require.Equal(t, "./lint-naming", (&Config{}).PluginCommand(LintPlugin{Command: "./lint-naming"}))
+
+	timeout, err := c.PluginTimeout(LintPlugin{Name: "naming"})
+	require.NoError(t, err)
+	require.Equal(t, defaultPluginTimeout, timeout)
+
+	timeout, err = c.PluginTimeout(LintPlugin{Name: "naming", Timeout: "30s"})
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, timeout)
+
+	_, err = c.PluginTimeout(LintPlugin{Name: "naming", Timeout: "soon"})
+	require.Error(t, err)
+}
+
+func TestConfig_OwnerPattern(t *testing.T) {
+	t.Parallel()
+
+	pattern, err := (&Config{}).OwnerPattern()
+	require.NoError(t, err)
+	require.Nil(t, pattern)
+
+	pattern, err = (&Config{Metadata: Metadata{OwnerPattern: `@example\.com$`}}).OwnerPattern()
+	require.NoError(t, err)
+	require.Equal(t, regexp.MustCompile(`@example\.com$`), pattern)
+
+	_, err = (&Config{Metadata: Metadata{OwnerPattern: "(unclosed"}}).OwnerPattern()
+	require.Error(t, err)
+}
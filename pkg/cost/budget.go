@@ -0,0 +1,98 @@
+package cost
+
+import (
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/pkg/errors"
+)
+
+// Budgets holds the parsed byte limits from the project config, a zero value means there is no limit.
+type Budgets struct {
+	defaultTask     int64
+	defaultPipeline int64
+	pipelines       map[string]int64
+	tasks           map[string]map[string]int64
+}
+
+func NewBudgets(c config.CostBudgets) (*Budgets, error) {
+	b := &Budgets{
+		pipelines: make(map[string]int64),
+		tasks:     make(map[string]map[string]int64),
+	}
+
+	var err error
+	b.defaultTask, err = parseBudget(c.DefaultTask)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid default task budget")
+	}
+
+	b.defaultPipeline, err = parseBudget(c.DefaultPipeline)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid default pipeline budget")
+	}
+
+	for pipelineName, pipelineBudget := range c.Pipelines {
+		b.pipelines[pipelineName], err = parseBudget(pipelineBudget.Total)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid budget for pipeline '%s'", pipelineName)
+		}
+
+		b.tasks[pipelineName] = make(map[string]int64, len(pipelineBudget.Tasks))
+		for taskName, taskBudget := range pipelineBudget.Tasks {
+			b.tasks[pipelineName][taskName], err = parseBudget(taskBudget)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid budget for task '%s' in pipeline '%s'", taskName, pipelineName)
+			}
+		}
+	}
+
+	return b, nil
+}
+
+// IsEmpty returns true if there are no budgets defined at all.
+func (b *Budgets) IsEmpty() bool {
+	if b.defaultTask != 0 || b.defaultPipeline != 0 {
+		return false
+	}
+
+	for name, budget := range b.pipelines {
+		if budget != 0 {
+			return false
+		}
+
+		for _, taskBudget := range b.tasks[name] {
+			if taskBudget != 0 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ForTask returns the budget of the task, the tasks without a budget of their own, or with an empty or zero one, use
+// the default task budget.
+func (b *Budgets) ForTask(pipelineName, taskName string) int64 {
+	if budget, ok := b.tasks[pipelineName][taskName]; ok && budget != 0 {
+		return budget
+	}
+
+	return b.defaultTask
+}
+
+// ForPipeline returns the total budget of the pipeline, the pipelines without a budget of their own, or with an empty
+// or zero one, use the default pipeline budget.
+func (b *Budgets) ForPipeline(pipelineName string) int64 {
+	if budget, ok := b.pipelines[pipelineName]; ok && budget != 0 {
+		return budget
+	}
+
+	return b.defaultPipeline
+}
+
+func parseBudget(budget string) (int64, error) {
+	if budget == "" {
+		return 0, nil
+	}
+
+	return ParseBytes(budget)
+}
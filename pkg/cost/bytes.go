@@ -0,0 +1,67 @@
+package cost
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const tebibyte = 1 << 40
+
+var byteUnits = map[string]float64{
+	"":    1,
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"PB":  1e15,
+	"KIB": 1 << 10,
+	"MIB": 1 << 20,
+	"GIB": 1 << 30,
+	"TIB": 1 << 40,
+	"PIB": 1 << 50,
+}
+
+var byteSizeRegex = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)$`)
+
+// ParseBytes parses human-readable sizes such as "500MB", "10 GB" or "1.5TiB" into bytes.
+func ParseBytes(size string) (int64, error) {
+	matches := byteSizeRegex.FindStringSubmatch(strings.TrimSpace(size))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid size '%s', it must be a number followed by a unit such as 'GB' or 'TiB'", size)
+	}
+
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid size '%s'", size)
+	}
+
+	multiplier, ok := byteUnits[strings.ToUpper(matches[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid unit '%s' in size '%s'", matches[2], size)
+	}
+
+	return int64(value * multiplier), nil
+}
+
+// FormatBytes renders the given amount of bytes using binary units, which is what BigQuery uses for billing.
+func FormatBytes(bytes int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+
+	value := float64(bytes)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d %s", bytes, units[unit])
+	}
+
+	return fmt.Sprintf("%.2f %s", value, units[unit])
+}
@@ -0,0 +1,64 @@
+package cost
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{name: "plain number", size: "1024", want: 1024},
+		{name: "decimal units", size: "10GB", want: 10_000_000_000},
+		{name: "binary units", size: "1.5TiB", want: 1_649_267_441_664},
+		{name: "lowercase units with a space", size: "500 mb", want: 500_000_000},
+		{name: "unknown unit", size: "10XB", wantErr: true},
+		{name: "invalid number", size: "ten GB", wantErr: true},
+		{name: "empty string", size: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseBytes(tt.size)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		bytes int64
+		want  string
+	}{
+		{name: "zero", bytes: 0, want: "0 B"},
+		{name: "bytes", bytes: 512, want: "512 B"},
+		{name: "mebibytes", bytes: 10 * 1024 * 1024, want: "10.00 MiB"},
+		{name: "tebibytes", bytes: 3 * tebibyte / 2, want: "1.50 TiB"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, FormatBytes(tt.bytes))
+		})
+	}
+}
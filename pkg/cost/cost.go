@@ -0,0 +1,107 @@
+package cost
+
+import (
+	"context"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/query"
+	"github.com/pkg/errors"
+)
+
+// DefaultPricePerTiB is the BigQuery on-demand price in USD for every TiB scanned.
+const DefaultPricePerTiB = 6.25
+
+type dryRunner interface {
+	BytesProcessed(ctx context.Context, query *query.Query) (int64, error)
+}
+
+type queryExtractor interface {
+	ExtractQueriesFromFile(filepath string) ([]*query.Query, error)
+}
+
+type TaskEstimate struct {
+	Task           *pipeline.Task
+	BytesProcessed int64
+	Cost           float64
+	Err            error
+}
+
+type PipelineEstimate struct {
+	Pipeline       *pipeline.Pipeline
+	Tasks          []*TaskEstimate
+	BytesProcessed int64
+	Cost           float64
+}
+
+// HasErrors returns true if the estimation failed for any of the tasks.
+func (p *PipelineEstimate) HasErrors() bool {
+	for _, task := range p.Tasks {
+		if task.Err != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Estimator calculates the on-demand cost of the tasks by dry-running their queries.
+type Estimator struct {
+	TaskType    string
+	Extractor   queryExtractor
+	DryRunner   dryRunner
+	PricePerTiB float64
+}
+
+func (e *Estimator) EstimatePipeline(ctx context.Context, p *pipeline.Pipeline) *PipelineEstimate {
+	result := &PipelineEstimate{
+		Pipeline: p,
+		Tasks:    make([]*TaskEstimate, 0),
+	}
+
+	for _, task := range p.Tasks {
+		if task.Type != e.TaskType {
+			continue
+		}
+
+		estimate := e.EstimateTask(ctx, task)
+		result.Tasks = append(result.Tasks, estimate)
+		result.BytesProcessed += estimate.BytesProcessed
+		result.Cost += estimate.Cost
+	}
+
+	return result
+}
+
+func (e *Estimator) EstimateTask(ctx context.Context, task *pipeline.Task) *TaskEstimate {
+	estimate := &TaskEstimate{Task: task}
+
+	queries, err := e.Extractor.ExtractQueriesFromFile(task.ExecutableFile.Path)
+	if err != nil {
+		estimate.Err = errors.Wrapf(err, "cannot read executable file '%s'", task.ExecutableFile.Path)
+		return estimate
+	}
+
+	for _, q := range queries {
+		bytes, err := e.DryRunner.BytesProcessed(ctx, q)
+		if err != nil {
+			estimate.Err = errors.Wrap(err, "failed to dry-run the query")
+			return estimate
+		}
+
+		estimate.BytesProcessed += bytes
+	}
+
+	estimate.Cost = e.Price(estimate.BytesProcessed)
+
+	return estimate
+}
+
+// Price returns the on-demand cost of scanning the given amount of bytes.
+func (e *Estimator) Price(bytes int64) float64 {
+	pricePerTiB := e.PricePerTiB
+	if pricePerTiB == 0 {
+		pricePerTiB = DefaultPricePerTiB
+	}
+
+	return float64(bytes) / tebibyte * pricePerTiB
+}
@@ -0,0 +1,118 @@
+package cost
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/query"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDryRunner struct {
+	mock.Mock
+}
+
+func (m *mockDryRunner) BytesProcessed(ctx context.Context, query *query.Query) (int64, error) {
+	res := m.Called(ctx, query)
+	return res.Get(0).(int64), res.Error(1)
+}
+
+type mockExtractor struct {
+	mock.Mock
+}
+
+func (m *mockExtractor) ExtractQueriesFromFile(filepath string) ([]*query.Query, error) {
+	res := m.Called(filepath)
+	return res.Get(0).([]*query.Query), res.Error(1)
+}
+
+func TestEstimator_EstimatePipeline(t *testing.T) {
+	t.Parallel()
+
+	taskType := "bq.sql"
+	task1 := &pipeline.Task{Name: "task1", Type: taskType, ExecutableFile: pipeline.ExecutableFile{Path: "file1.sql"}}
+	task2 := &pipeline.Task{Name: "task2", Type: taskType, ExecutableFile: pipeline.ExecutableFile{Path: "file2.sql"}}
+	otherTask := &pipeline.Task{Name: "task3", Type: "python"}
+
+	tests := []struct {
+		name           string
+		setupExtractor func(m *mockExtractor)
+		setupDryRunner func(m *mockDryRunner)
+		wantBytes      int64
+		wantCost       float64
+		wantErrors     bool
+	}{
+		{
+			name: "bytes are summed up across queries and tasks",
+			setupExtractor: func(m *mockExtractor) {
+				m.On("ExtractQueriesFromFile", "file1.sql").
+					Return([]*query.Query{{Query: "query11"}, {Query: "query12"}}, nil)
+				m.On("ExtractQueriesFromFile", "file2.sql").
+					Return([]*query.Query{{Query: "query21"}}, nil)
+			},
+			setupDryRunner: func(m *mockDryRunner) {
+				m.On("BytesProcessed", mock.Anything, &query.Query{Query: "query11"}).Return(int64(tebibyte), nil)
+				m.On("BytesProcessed", mock.Anything, &query.Query{Query: "query12"}).Return(int64(tebibyte), nil)
+				m.On("BytesProcessed", mock.Anything, &query.Query{Query: "query21"}).Return(int64(tebibyte/2), nil)
+			},
+			wantBytes: tebibyte * 5 / 2,
+			wantCost:  12.5,
+		},
+		{
+			name: "failing tasks are reported but do not stop the others",
+			setupExtractor: func(m *mockExtractor) {
+				m.On("ExtractQueriesFromFile", "file1.sql").
+					Return([]*query.Query{}, errors.New("cannot read file"))
+				m.On("ExtractQueriesFromFile", "file2.sql").
+					Return([]*query.Query{{Query: "query21"}}, nil)
+			},
+			setupDryRunner: func(m *mockDryRunner) {
+				m.On("BytesProcessed", mock.Anything, &query.Query{Query: "query21"}).Return(int64(tebibyte), nil)
+			},
+			wantBytes:  tebibyte,
+			wantCost:   5,
+			wantErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			extractor := new(mockExtractor)
+			tt.setupExtractor(extractor)
+
+			dryRunner := new(mockDryRunner)
+			tt.setupDryRunner(dryRunner)
+
+			e := &Estimator{
+				TaskType:    taskType,
+				Extractor:   extractor,
+				DryRunner:   dryRunner,
+				PricePerTiB: 5,
+			}
+
+			got := e.EstimatePipeline(context.Background(), &pipeline.Pipeline{
+				Tasks: []*pipeline.Task{task1, otherTask, task2},
+			})
+
+			require.Len(t, got.Tasks, 2)
+			require.Equal(t, tt.wantBytes, got.BytesProcessed)
+			require.InDelta(t, tt.wantCost, got.Cost, 0.0001)
+			require.Equal(t, tt.wantErrors, got.HasErrors())
+
+			extractor.AssertExpectations(t)
+			dryRunner.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEstimator_Price(t *testing.T) {
+	t.Parallel()
+
+	require.InDelta(t, DefaultPricePerTiB, (&Estimator{}).Price(tebibyte), 0.0001)
+	require.InDelta(t, 2.5, (&Estimator{PricePerTiB: 5}).Price(tebibyte/2), 0.0001)
+}
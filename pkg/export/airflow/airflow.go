@@ -0,0 +1,438 @@
+package airflow
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+//go:embed dag.py.tmpl
+var dagTemplateContent string
+
+var (
+	dagTemplate      = template.Must(template.New("dag").Parse(dagTemplateContent))
+	pythonIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	nonIdentifier    = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+)
+
+// DefaultStartDate is used for the DAGs unless another date is given, the DAGs are generated with `catchup=False`
+// therefore the date does not trigger any backfills.
+var DefaultStartDate = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Exporter turns the pipelines into Airflow DAG files, one file per pipeline named after it.
+type Exporter struct {
+	Fs        afero.Fs
+	OutputDir string
+	StartDate time.Time
+}
+
+type dagOperator struct {
+	Variable  string
+	Class     string
+	TaskID    string
+	Arguments []argument
+	Notes     []string
+}
+
+type dagEdge struct {
+	From string
+	To   string
+}
+
+type dagImport struct {
+	Module string
+	Class  string
+}
+
+type dag struct {
+	PipelineName string
+	PipelineRoot string
+	DagID        string
+	Schedule     string
+	StartDate    string
+	Imports      []dagImport
+	Operators    []*dagOperator
+	Edges        []dagEdge
+}
+
+// Export writes the DAG file for the given pipeline into the output directory and returns its path.
+func (e *Exporter) Export(p *pipeline.Pipeline) (string, error) {
+	var buf bytes.Buffer
+	if err := e.Render(&buf, p); err != nil {
+		return "", err
+	}
+
+	if err := e.Fs.MkdirAll(e.OutputDir, 0o755); err != nil {
+		return "", errors.Wrapf(err, "failed to create the output directory '%s'", e.OutputDir)
+	}
+
+	dagPath := filepath.Join(e.OutputDir, fileName(p.Name))
+	if err := afero.WriteFile(e.Fs, dagPath, buf.Bytes(), 0o644); err != nil {
+		return "", errors.Wrapf(err, "failed to write the DAG file '%s'", dagPath)
+	}
+
+	return dagPath, nil
+}
+
+// Render writes the DAG file content for the given pipeline. The task files are referenced relative to the output
+// directory, which means the DAG files keep working as long as they are deployed together with the pipelines.
+func (e *Exporter) Render(w io.Writer, p *pipeline.Pipeline) error {
+	d, err := e.buildDag(p)
+	if err != nil {
+		return err
+	}
+
+	return dagTemplate.Execute(w, d)
+}
+
+func (e *Exporter) buildDag(p *pipeline.Pipeline) (*dag, error) {
+	pipelineRoot := filepath.Dir(p.DefinitionFile.Path)
+	relativeRoot, err := filepath.Rel(e.OutputDir, pipelineRoot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the pipeline path relative to the output directory '%s'", e.OutputDir)
+	}
+
+	startDate := e.StartDate
+	if startDate.IsZero() {
+		startDate = DefaultStartDate
+	}
+
+	d := &dag{
+		PipelineName: pyString(p.Name),
+		PipelineRoot: pyString(filepath.ToSlash(relativeRoot)),
+		DagID:        pyString(p.Name),
+		Schedule:     "None",
+		StartDate:    fmt.Sprintf(`pendulum.datetime(%d, %d, %d, tz="UTC")`, startDate.Year(), startDate.Month(), startDate.Day()),
+	}
+	if p.Schedule != "" {
+		d.Schedule = pyString(string(p.Schedule))
+	}
+
+	variables := newVariableNames()
+	taskVariables := make(map[string]string, len(p.Tasks))
+	usedOperators := make(map[*operator]bool)
+	for _, task := range p.Tasks {
+		op, ok := operators[task.Type]
+		if !ok {
+			return nil, errors.Errorf("the task '%s' has the type '%s' which cannot be exported to Airflow, the supported types are: %s", task.Name, task.Type, strings.Join(SupportedTaskTypes(), ", "))
+		}
+
+		dagOp, err := newDagOperator(p, task, op)
+		if err != nil {
+			return nil, err
+		}
+
+		dagOp.Variable = variables.next(task.Name)
+		taskVariables[task.Name] = dagOp.Variable
+		usedOperators[op] = true
+		d.Operators = append(d.Operators, dagOp)
+	}
+
+	sensors := make(map[string]string)
+	for _, task := range p.Tasks {
+		dependencies := make([]string, len(task.DependsOn))
+		copy(dependencies, task.DependsOn)
+		sort.Strings(dependencies)
+
+		for _, dep := range dependencies {
+			dependency := pipeline.ParseDependency(dep)
+			if !dependency.IsCrossPipeline() || dependency.Pipeline == p.Name {
+				upstream, ok := taskVariables[dependency.Task]
+				if !ok {
+					continue
+				}
+
+				d.Edges = append(d.Edges, dagEdge{From: upstream, To: taskVariables[task.Name]})
+				continue
+			}
+
+			sensor, ok := sensors[dependency.String()]
+			if !ok {
+				sensor = variables.next("wait_for_" + dependency.Pipeline + "_" + dependency.Task)
+				sensors[dependency.String()] = sensor
+				usedOperators[externalTaskSensor] = true
+				d.Operators = append(d.Operators, &dagOperator{
+					Variable: sensor,
+					Class:    externalTaskSensor.class,
+					TaskID:   pyString(fmt.Sprintf("wait_for_%s.%s", dependency.Pipeline, dependency.Task)),
+					Arguments: []argument{
+						{Name: "external_dag_id", Value: pyString(dependency.Pipeline)},
+						{Name: "external_task_id", Value: pyString(dependency.Task)},
+						{Name: "mode", Value: pyString("reschedule")},
+					},
+				})
+			}
+
+			d.Edges = append(d.Edges, dagEdge{From: sensor, To: taskVariables[task.Name]})
+		}
+	}
+
+	for op := range usedOperators {
+		d.Imports = append(d.Imports, dagImport{Module: op.module, Class: op.class})
+	}
+	sort.Slice(d.Imports, func(i, j int) bool {
+		if d.Imports[i].Module != d.Imports[j].Module {
+			return d.Imports[i].Module < d.Imports[j].Module
+		}
+
+		return d.Imports[i].Class < d.Imports[j].Class
+	})
+
+	return d, nil
+}
+
+func newDagOperator(p *pipeline.Pipeline, task *pipeline.Task, op *operator) (*dagOperator, error) {
+	dagOp := &dagOperator{
+		Class:  op.class,
+		TaskID: pyString(task.Name),
+	}
+
+	if op.note != "" {
+		dagOp.Notes = append(dagOp.Notes, pyComment(op.note))
+	}
+
+	if op.arguments != nil {
+		executablePath, err := filepath.Rel(filepath.Dir(p.DefinitionFile.Path), task.ExecutableFile.Path)
+		if err != nil || task.ExecutableFile.Path == "" {
+			return nil, errors.Errorf("the task '%s' must have an executable file to be exported to Airflow", task.Name)
+		}
+
+		dagOp.Arguments = append(dagOp.Arguments, op.arguments(executablePath)...)
+	}
+
+	switch op.parameters {
+	case parametersAsTemplateParams:
+		if parameters := p.EffectiveParameters(task, taskTypes); len(parameters) > 0 {
+			dagOp.Arguments = append(dagOp.Arguments, argument{Name: "params", Value: pyStringDict(parameters)})
+		}
+	case parametersAsEnv:
+		if parameters := p.EffectiveParameters(task, taskTypes); len(parameters) > 0 {
+			dagOp.Arguments = append(dagOp.Arguments,
+				argument{Name: "env", Value: pyStringDict(parameters)},
+				argument{Name: "append_env", Value: "True"},
+			)
+		}
+	case parametersAsArguments:
+		// the types passed as arguments only take the pipeline defaults they know, the others would end up as unknown
+		// arguments of the operators
+		parameters := p.EffectiveParameters(task, taskTypes)
+		for _, name := range sortedKeys(parameters) {
+			if !pythonIdentifier.MatchString(name) || isPythonKeyword(name) {
+				return nil, errors.Errorf("the parameter '%s' of the task '%s' cannot be used as an argument of %s, it must be a valid Python identifier", name, task.Name, op.class)
+			}
+
+			value, err := argumentValue(taskTypes.Get(task.Type), name, parameters[name])
+			if err != nil {
+				return nil, errors.Wrapf(err, "the task '%s' has an invalid parameter", task.Name)
+			}
+
+			dagOp.Arguments = append(dagOp.Arguments, argument{Name: name, Value: value})
+		}
+	case parametersIgnored:
+	}
+
+	connections := p.EffectiveConnections(task)
+	unusedConnections := make([]string, 0)
+	for _, name := range sortedKeys(connections) {
+		argumentName, ok := op.connections[name]
+		if !ok {
+			unusedConnections = append(unusedConnections, fmt.Sprintf("%s=%s", name, connections[name]))
+			continue
+		}
+
+		dagOp.Arguments = append(dagOp.Arguments, argument{Name: argumentName, Value: pyString(connections[name])})
+	}
+
+	if len(unusedConnections) > 0 {
+		dagOp.Notes = append(dagOp.Notes, pyComment(fmt.Sprintf("connections not used by %s: %s", op.class, strings.Join(unusedConnections, ", "))))
+	}
+
+	policy, err := policyArguments(p.EffectivePolicy(task))
+	if err != nil {
+		return nil, errors.Wrapf(err, "the task '%s' has an invalid execution policy", task.Name)
+	}
+
+	dagOp.Arguments = append(dagOp.Arguments, policy...)
+
+	return dagOp, nil
+}
+
+// argumentValue turns the parameter into a Python value based on its type in the task type, e.g. the durations are
+// passed as seconds. The parameters without a type are passed as strings.
+func argumentValue(taskType *tasktype.TaskType, name, value string) (string, error) {
+	if taskType == nil || taskType.Parameter(name) == nil {
+		return pyString(value), nil
+	}
+
+	parameterType := taskType.Parameter(name).Type
+	if err := parameterType.Check(value); err != nil {
+		return "", errors.Errorf("the parameter '%s' %v", name, err)
+	}
+
+	switch parameterType {
+	case tasktype.Duration:
+		duration, _ := time.ParseDuration(value)
+		return strconv.FormatFloat(duration.Seconds(), 'f', -1, 64), nil
+	case tasktype.Int:
+		number, _ := strconv.Atoi(value)
+		return strconv.Itoa(number), nil
+	case tasktype.Bool:
+		boolean, _ := strconv.ParseBool(value)
+		return pyBool(boolean), nil
+	default:
+		return pyString(value), nil
+	}
+}
+
+// policyArguments maps the execution policy to the arguments shared by all the Airflow operators, the fields that are
+// not set are left to the Airflow defaults.
+func policyArguments(e pipeline.ExecutionPolicy) ([]argument, error) {
+	policy, err := e.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make([]argument, 0)
+	if e.Retries != nil {
+		arguments = append(arguments, argument{Name: "retries", Value: strconv.Itoa(policy.Retries)})
+	}
+	if e.RetryDelay != "" {
+		arguments = append(arguments, argument{Name: "retry_delay", Value: pyDuration(policy.RetryDelay)})
+	}
+	if e.Timeout != "" {
+		arguments = append(arguments, argument{Name: "execution_timeout", Value: pyDuration(policy.Timeout)})
+	}
+	if e.SLA != "" {
+		arguments = append(arguments, argument{Name: "sla", Value: pyDuration(policy.SLA)})
+	}
+	if e.Pool != "" {
+		arguments = append(arguments, argument{Name: "pool", Value: pyString(policy.Pool)})
+	}
+	if e.Priority != nil {
+		arguments = append(arguments, argument{Name: "priority_weight", Value: strconv.Itoa(policy.Priority)})
+	}
+
+	return arguments, nil
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+var (
+	pythonKeywords = []string{
+		"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif", "else", "except",
+		"finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "nonlocal", "not", "or", "pass",
+		"raise", "return", "try", "while", "with", "yield",
+	}
+
+	// reservedNames are the names used by the template, the tasks must not shadow them
+	reservedNames = []string{"dag", "os", "pendulum"}
+)
+
+// variableNames turns the task names into unique Python variable names.
+type variableNames struct {
+	used map[string]bool
+}
+
+func newVariableNames() *variableNames {
+	used := make(map[string]bool, len(pythonKeywords)+len(reservedNames))
+	for _, name := range append(pythonKeywords, reservedNames...) {
+		used[name] = true
+	}
+
+	return &variableNames{used: used}
+}
+
+func (v *variableNames) next(name string) string {
+	base := strings.Trim(nonIdentifier.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if base == "" || (base[0] >= '0' && base[0] <= '9') {
+		base = "task_" + base
+	}
+
+	variable := base
+	for i := 2; v.used[variable]; i++ {
+		variable = fmt.Sprintf("%s_%d", base, i)
+	}
+	v.used[variable] = true
+
+	return variable
+}
+
+func isPythonKeyword(name string) bool {
+	for _, keyword := range pythonKeywords {
+		if name == keyword {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fileName(pipelineName string) string {
+	return nonIdentifier.ReplaceAllString(pipelineName, "_") + ".py"
+}
+
+// pyString renders the value as a Python string literal, the Go escape sequences are valid in Python as well.
+func pyString(value string) string {
+	return strconv.Quote(value)
+}
+
+// commentEscaper escapes the line breaks, a value with a line break would otherwise end the comment and leave the rest
+// of it as code in the DAG.
+var commentEscaper = strings.NewReplacer("\r", `\r`, "\n", `\n`)
+
+// pyComment makes the value safe to be rendered after a `#`, on a single line.
+func pyComment(value string) string {
+	return commentEscaper.Replace(value)
+}
+
+// pyDuration renders the duration as a pendulum duration, which Airflow accepts wherever it expects a timedelta.
+func pyDuration(d time.Duration) string {
+	return fmt.Sprintf("pendulum.duration(seconds=%s)", strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+}
+
+func pyBool(value bool) string {
+	if value {
+		return "True"
+	}
+
+	return "False"
+}
+
+func pyDict(items []argument) string {
+	entries := make([]string, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, fmt.Sprintf("%s: %s", pyString(item.Name), item.Value))
+	}
+
+	return "{" + strings.Join(entries, ", ") + "}"
+}
+
+func pyStringDict(values map[string]string) string {
+	items := make([]argument, 0, len(values))
+	for _, key := range sortedKeys(values) {
+		items = append(items, argument{Name: key, Value: pyString(values[key])})
+	}
+
+	return pyDict(items)
+}
@@ -0,0 +1,272 @@
+package airflow
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+const (
+	pipelineRoot = "/repo/pipelines/sales"
+	outputDir    = "/repo/dags"
+)
+
+func assertGolden(t *testing.T, goldenFile string, got []byte) {
+	t.Helper()
+
+	goldenPath := filepath.Join("testdata", goldenFile)
+	if *update {
+		require.NoError(t, os.WriteFile(goldenPath, got, 0o600))
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func newPipeline(tasks ...*pipeline.Task) *pipeline.Pipeline {
+	return &pipeline.Pipeline{
+		Name:     "sales",
+		Schedule: "0 5 * * *",
+		DefinitionFile: pipeline.DefinitionFile{
+			Path: pipelineRoot + "/pipeline.yml",
+		},
+		Tasks: tasks,
+	}
+}
+
+// taskTypeFixtures contains a task for every supported type, each of them is rendered into its own golden file.
+var taskTypeFixtures = map[string]*pipeline.Task{
+	"bq.sql": {
+		Name:           "orders_clean",
+		ExecutableFile: pipeline.ExecutableFile{Path: pipelineRoot + "/tasks/orders_clean.sql"},
+		Parameters:     map[string]string{"country": "NL"},
+		Connections:    map[string]string{"gcpConnectionId": "gcp-sales"},
+	},
+	"bq.sensor.table": {
+		Name:        "wait_for_orders",
+		Parameters:  map[string]string{"project_id": "my-project", "dataset_id": "raw", "table_id": "orders"},
+		Connections: map[string]string{"gcpConnectionId": "gcp-sales"},
+	},
+	"bq.sensor.query": {
+		Name:        "wait_for_today",
+		Parameters:  map[string]string{"sql": "SELECT COUNT(*) FROM raw.orders WHERE dt = '{{ ds }}'"},
+		Connections: map[string]string{"gcpConnectionId": "gcp-sales"},
+	},
+	"bq.cost_tracker": {
+		Name:       "track_costs",
+		Parameters: map[string]string{"threshold": "100"},
+	},
+	"bash": {
+		Name:           "hello-world",
+		ExecutableFile: pipeline.ExecutableFile{Path: pipelineRoot + "/tasks/hello/hello.sh"},
+		Parameters:     map[string]string{"GREETING": "hello \"world\""},
+	},
+	"gcs.from.s3": {
+		Name:        "copy_exports",
+		Parameters:  map[string]string{"bucket": "exports", "prefix": "orders/", "dest_gcs": "gs://landing/orders/"},
+		Connections: map[string]string{"gcpConnectionId": "gcp-sales", "awsConnectionId": "aws-exports", "slack": "alerts"},
+	},
+	"gcs.sensor.object_sensor_with_prefix": {
+		Name:        "wait_for_landing",
+		Parameters:  map[string]string{"bucket": "landing", "prefix": "orders/"},
+		Connections: map[string]string{"gcpConnectionId": "gcp-sales"},
+	},
+	"python": {
+		Name:           "train.model",
+		ExecutableFile: pipeline.ExecutableFile{Path: pipelineRoot + "/tasks/train.py"},
+	},
+	"s3.sensor.key_sensor": {
+		Name:        "wait_for_export",
+		Parameters:  map[string]string{"bucket_name": "exports", "bucket_key": "orders/_SUCCESS", "poke_interval": "5m", "timeout": "1h30m"},
+		Connections: map[string]string{"awsConnectionId": "aws-exports"},
+	},
+	"sf.sql": {
+		Name:           "customers",
+		ExecutableFile: pipeline.ExecutableFile{Path: pipelineRoot + "/tasks/customers.sql"},
+		Connections:    map[string]string{"snowflakeConnectionId": "snowflake-sales"},
+	},
+}
+
+func TestExporter_RenderEveryTaskType(t *testing.T) {
+	t.Parallel()
+
+	require.ElementsMatch(t, SupportedTaskTypes(), keys(taskTypeFixtures), "every supported task type must have a fixture")
+
+	for taskType, task := range taskTypeFixtures {
+		taskType := taskType
+		task := *task
+		task.Type = taskType
+
+		t.Run(taskType, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Exporter{OutputDir: outputDir}
+
+			var buf bytes.Buffer
+			err := e.Render(&buf, newPipeline(&task))
+			require.NoError(t, err)
+
+			assertGolden(t, taskType+".py", buf.Bytes())
+		})
+	}
+}
+
+func TestExporter_RenderPipeline(t *testing.T) {
+	t.Parallel()
+
+	priority := 5
+	p := newPipeline(
+		&pipeline.Task{
+			Name:           "raw.orders",
+			Type:           "bq.sql",
+			ExecutableFile: pipeline.ExecutableFile{Path: pipelineRoot + "/tasks/raw/orders.sql"},
+			DependsOn:      []string{"crm:orders_export"},
+		},
+		&pipeline.Task{
+			Name:           "orders_clean",
+			Type:           "bq.sql",
+			ExecutableFile: pipeline.ExecutableFile{Path: pipelineRoot + "/tasks/orders_clean.sql"},
+			DependsOn:      []string{"raw.orders", "sales:import"},
+			Parameters:     map[string]string{"country": "DE"},
+			Policy:         pipeline.ExecutionPolicy{Timeout: "30m", SLA: "1h30m", Pool: "bigquery", Priority: &priority},
+		},
+		&pipeline.Task{
+			Name:           "import",
+			Type:           "python",
+			ExecutableFile: pipeline.ExecutableFile{Path: pipelineRoot + "/tasks/import.py"},
+			DependsOn:      []string{"crm:orders_export", "marketing:campaigns"},
+		},
+	)
+	p.Schedule = ""
+	p.DefaultParameters = map[string]string{"country": "NL", "env": "production"}
+	p.DefaultConnections = map[string]string{"gcpConnectionId": "gcp-default"}
+	retries := 2
+	p.DefaultPolicy = pipeline.ExecutionPolicy{Retries: &retries, RetryDelay: "5m"}
+
+	e := &Exporter{
+		OutputDir: outputDir,
+		StartDate: time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	err := e.Render(&buf, p)
+	require.NoError(t, err)
+
+	assertGolden(t, "pipeline.py", buf.Bytes())
+}
+
+func TestExporter_RenderUnsupportedTasks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		task    *pipeline.Task
+		wantErr string
+	}{
+		{
+			name:    "unknown task types are rejected",
+			task:    &pipeline.Task{Name: "transfer", Type: "bq.transfer"},
+			wantErr: "the task 'transfer' has the type 'bq.transfer' which cannot be exported to Airflow",
+		},
+		{
+			name:    "the tasks that run a file must have one",
+			task:    &pipeline.Task{Name: "query", Type: "bq.sql"},
+			wantErr: "the task 'query' must have an executable file to be exported to Airflow",
+		},
+		{
+			name: "the parameters passed as arguments must be valid identifiers",
+			task: &pipeline.Task{
+				Name:       "sensor",
+				Type:       "s3.sensor.key_sensor",
+				Parameters: map[string]string{"bucket-key": "orders/_SUCCESS"},
+			},
+			wantErr: "the parameter 'bucket-key' of the task 'sensor' cannot be used as an argument of S3KeySensor",
+		},
+		{
+			name: "the durations of the sensors must be valid",
+			task: &pipeline.Task{
+				Name:       "sensor",
+				Type:       "gcs.sensor.object_sensor_with_prefix",
+				Parameters: map[string]string{"bucket": "landing", "prefix": "orders/", "poke_interval": "60"},
+			},
+			wantErr: "the task 'sensor' has an invalid parameter: the parameter 'poke_interval' must be a positive duration such as 30s, 5m or 1h, got '60'",
+		},
+		{
+			name: "the durations of the execution policy must be valid",
+			task: &pipeline.Task{
+				Name:   "track_costs",
+				Type:   "bq.cost_tracker",
+				Policy: pipeline.ExecutionPolicy{Timeout: "1 hour"},
+			},
+			wantErr: "the task 'track_costs' has an invalid execution policy: invalid timeout '1 hour'",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := &Exporter{OutputDir: outputDir}
+			err := e.Render(&bytes.Buffer{}, newPipeline(tt.task))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestExporter_RenderEscapesTheNotes(t *testing.T) {
+	t.Parallel()
+
+	task := &pipeline.Task{
+		Name:           "copy_exports",
+		Type:           "gcs.from.s3",
+		Parameters:     map[string]string{"bucket": "exports", "prefix": "orders/", "dest_gcs": "gs://landing/orders/"},
+		Connections:    map[string]string{"slack": "alerts\nimport os; os.system('rm -rf /')\r\n"},
+		ExecutableFile: pipeline.ExecutableFile{Path: pipelineRoot + "/tasks/copy.sh"},
+	}
+
+	var buf bytes.Buffer
+	e := &Exporter{OutputDir: outputDir}
+	require.NoError(t, e.Render(&buf, newPipeline(task)))
+
+	assert.Contains(t, buf.String(), `    # connections not used by S3ToGCSOperator: slack=alerts\nimport os; os.system('rm -rf /')\r\n`+"\n")
+	assert.NotContains(t, buf.String(), "\nimport os;")
+}
+
+func TestExporter_Export(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	e := &Exporter{Fs: fs, OutputDir: outputDir}
+
+	p := newPipeline(&pipeline.Task{Name: "track_costs", Type: "bq.cost_tracker"})
+	p.Name = "sales.daily"
+
+	dagPath, err := e.Export(p)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(outputDir, "sales_daily.py"), dagPath)
+
+	content, err := afero.ReadFile(fs, dagPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `dag_id="sales.daily"`)
+}
+
+func keys(m map[string]*pipeline.Task) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+
+	return result
+}
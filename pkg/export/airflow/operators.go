@@ -0,0 +1,171 @@
+package airflow
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+)
+
+// parameterStyle decides how the task parameters are passed to the operator.
+type parameterStyle int
+
+const (
+	// parametersAsTemplateParams passes the parameters as `params`, which makes them available in the Jinja templates
+	// of the queries, e.g. `{{ params.param1 }}`.
+	parametersAsTemplateParams parameterStyle = iota
+
+	// parametersAsEnv passes the parameters as environment variables to the scripts.
+	parametersAsEnv
+
+	// parametersAsArguments passes the parameters directly as the keyword arguments of the operator, which means their
+	// names must match the arguments of the operator, e.g. `bucket_key` for the S3 key sensor.
+	parametersAsArguments
+
+	// parametersIgnored is used for the operators that cannot make use of the parameters.
+	parametersIgnored
+)
+
+// argument is a keyword argument of an operator, Value is already a Python expression.
+type argument struct {
+	Name  string
+	Value string
+}
+
+type operator struct {
+	module string
+	class  string
+
+	// arguments returns the arguments that are derived from the task itself, the path is the executable file relative
+	// to the pipeline root.
+	arguments func(executablePath string) []argument
+
+	// connections maps the Blast connection names to the connection arguments of the operator.
+	connections map[string]string
+	parameters  parameterStyle
+
+	// note is added as a comment above the operator, e.g. to explain why a task type has no real equivalent.
+	note string
+}
+
+// taskTypes is used to pass the parameters as arguments of the right Python type.
+var taskTypes = tasktype.Builtin()
+
+const (
+	gcpConnection       = "gcpConnectionId"
+	awsConnection       = "awsConnectionId"
+	snowflakeConnection = "snowflakeConnectionId"
+)
+
+var operators = map[string]*operator{
+	"bq.sql": {
+		module: "airflow.providers.google.cloud.operators.bigquery",
+		class:  "BigQueryInsertJobOperator",
+		arguments: func(executablePath string) []argument {
+			query := pyDict([]argument{
+				{Name: "query", Value: pyString("{% include '" + filepath.ToSlash(executablePath) + "' %}")},
+				{Name: "useLegacySql", Value: "False"},
+			})
+
+			return []argument{{Name: "configuration", Value: pyDict([]argument{{Name: "query", Value: query}})}}
+		},
+		connections: map[string]string{gcpConnection: "gcp_conn_id"},
+		parameters:  parametersAsTemplateParams,
+	},
+	"bq.sensor.table": {
+		module:      "airflow.providers.google.cloud.sensors.bigquery",
+		class:       "BigQueryTableExistenceSensor",
+		connections: map[string]string{gcpConnection: "gcp_conn_id"},
+		parameters:  parametersAsArguments,
+	},
+	"bq.sensor.query": {
+		module:      "airflow.providers.common.sql.sensors.sql",
+		class:       "SqlSensor",
+		connections: map[string]string{gcpConnection: "conn_id"},
+		parameters:  parametersAsArguments,
+	},
+	"bq.cost_tracker": {
+		module:     "airflow.operators.empty",
+		class:      "EmptyOperator",
+		parameters: parametersIgnored,
+		note:       "bq.cost_tracker is specific to Blast and has no Airflow equivalent, the task only keeps the dependencies in place",
+	},
+	"bash": {
+		module: "airflow.operators.bash",
+		class:  "BashOperator",
+		arguments: func(executablePath string) []argument {
+			return scriptArguments("bash", executablePath)
+		},
+		parameters: parametersAsEnv,
+	},
+	"gcs.from.s3": {
+		module:      "airflow.providers.google.cloud.transfers.s3_to_gcs",
+		class:       "S3ToGCSOperator",
+		connections: map[string]string{gcpConnection: "gcp_conn_id", awsConnection: "aws_conn_id"},
+		parameters:  parametersAsArguments,
+	},
+	"gcs.sensor.object_sensor_with_prefix": {
+		module:      "airflow.providers.google.cloud.sensors.gcs",
+		class:       "GCSObjectsWithPrefixExistenceSensor",
+		connections: map[string]string{gcpConnection: "google_cloud_conn_id"},
+		parameters:  parametersAsArguments,
+	},
+	"python": {
+		module: "airflow.operators.bash",
+		class:  "BashOperator",
+		arguments: func(executablePath string) []argument {
+			return scriptArguments("python3", executablePath)
+		},
+		parameters: parametersAsEnv,
+	},
+	"s3.sensor.key_sensor": {
+		module:      "airflow.providers.amazon.aws.sensors.s3",
+		class:       "S3KeySensor",
+		connections: map[string]string{awsConnection: "aws_conn_id"},
+		parameters:  parametersAsArguments,
+	},
+	"sf.sql": {
+		module: "airflow.providers.snowflake.operators.snowflake",
+		class:  "SnowflakeOperator",
+		arguments: func(executablePath string) []argument {
+			return []argument{{Name: "sql", Value: pyString(filepath.ToSlash(executablePath))}}
+		},
+		connections: map[string]string{snowflakeConnection: "snowflake_conn_id"},
+		parameters:  parametersAsTemplateParams,
+	},
+}
+
+// the sensor for the cross-pipeline dependencies, the upstream pipelines are expected to be exported as DAGs too
+var externalTaskSensor = &operator{
+	module: "airflow.sensors.external_task",
+	class:  "ExternalTaskSensor",
+}
+
+// scriptArguments runs the script from the pipeline directory, the path is resolved when the DAG is loaded since the
+// scripts are not templates.
+func scriptArguments(interpreter, executablePath string) []argument {
+	// the trailing space stops Airflow from treating the commands ending with `.sh` as template files
+	command := pyString(interpreter+" ") + " + os.path.join(PIPELINE_ROOT, " + pyString(filepath.ToSlash(executablePath)) + ") + " + pyString(" ")
+
+	return []argument{
+		{Name: "bash_command", Value: command},
+		{Name: "cwd", Value: "PIPELINE_ROOT"},
+	}
+}
+
+// IsSupported checks if the tasks of the given type can be exported to Airflow.
+func IsSupported(taskType string) bool {
+	_, ok := operators[taskType]
+	return ok
+}
+
+// SupportedTaskTypes returns the task types that can be exported to Airflow.
+func SupportedTaskTypes() []string {
+	taskTypes := make([]string, 0, len(operators))
+	for taskType := range operators {
+		taskTypes = append(taskTypes, taskType)
+	}
+	sort.Strings(taskTypes)
+
+	return taskTypes
+}
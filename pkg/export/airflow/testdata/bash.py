@@ -0,0 +1,23 @@
+# This file is generated by `blast export airflow` from the pipeline "sales", do not edit it by hand.
+import os
+
+import pendulum
+from airflow import DAG
+from airflow.operators.bash import BashOperator
+
+PIPELINE_ROOT = os.path.normpath(os.path.join(os.path.dirname(os.path.abspath(__file__)), "../pipelines/sales"))
+
+with DAG(
+    dag_id="sales",
+    schedule_interval="0 5 * * *",
+    start_date=pendulum.datetime(2022, 1, 1, tz="UTC"),
+    catchup=False,
+    template_searchpath=[PIPELINE_ROOT],
+) as dag:
+    hello_world = BashOperator(
+        task_id="hello-world",
+        bash_command="bash " + os.path.join(PIPELINE_ROOT, "tasks/hello/hello.sh") + " ",
+        cwd=PIPELINE_ROOT,
+        env={"GREETING": "hello \"world\""},
+        append_env=True,
+    )
@@ -0,0 +1,20 @@
+# This file is generated by `blast export airflow` from the pipeline "sales", do not edit it by hand.
+import os
+
+import pendulum
+from airflow import DAG
+from airflow.operators.empty import EmptyOperator
+
+PIPELINE_ROOT = os.path.normpath(os.path.join(os.path.dirname(os.path.abspath(__file__)), "../pipelines/sales"))
+
+with DAG(
+    dag_id="sales",
+    schedule_interval="0 5 * * *",
+    start_date=pendulum.datetime(2022, 1, 1, tz="UTC"),
+    catchup=False,
+    template_searchpath=[PIPELINE_ROOT],
+) as dag:
+    # bq.cost_tracker is specific to Blast and has no Airflow equivalent, the task only keeps the dependencies in place
+    track_costs = EmptyOperator(
+        task_id="track_costs",
+    )
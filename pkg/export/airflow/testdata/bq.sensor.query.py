@@ -0,0 +1,22 @@
+# This file is generated by `blast export airflow` from the pipeline "sales", do not edit it by hand.
+import os
+
+import pendulum
+from airflow import DAG
+from airflow.providers.common.sql.sensors.sql import SqlSensor
+
+PIPELINE_ROOT = os.path.normpath(os.path.join(os.path.dirname(os.path.abspath(__file__)), "../pipelines/sales"))
+
+with DAG(
+    dag_id="sales",
+    schedule_interval="0 5 * * *",
+    start_date=pendulum.datetime(2022, 1, 1, tz="UTC"),
+    catchup=False,
+    template_searchpath=[PIPELINE_ROOT],
+) as dag:
+    wait_for_today = SqlSensor(
+        task_id="wait_for_today",
+        poke_interval=60,
+        sql="SELECT COUNT(*) FROM raw.orders WHERE dt = '{{ ds }}'",
+        conn_id="gcp-sales",
+    )
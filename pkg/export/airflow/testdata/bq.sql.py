@@ -0,0 +1,22 @@
+# This file is generated by `blast export airflow` from the pipeline "sales", do not edit it by hand.
+import os
+
+import pendulum
+from airflow import DAG
+from airflow.providers.google.cloud.operators.bigquery import BigQueryInsertJobOperator
+
+PIPELINE_ROOT = os.path.normpath(os.path.join(os.path.dirname(os.path.abspath(__file__)), "../pipelines/sales"))
+
+with DAG(
+    dag_id="sales",
+    schedule_interval="0 5 * * *",
+    start_date=pendulum.datetime(2022, 1, 1, tz="UTC"),
+    catchup=False,
+    template_searchpath=[PIPELINE_ROOT],
+) as dag:
+    orders_clean = BigQueryInsertJobOperator(
+        task_id="orders_clean",
+        configuration={"query": {"query": "{% include 'tasks/orders_clean.sql' %}", "useLegacySql": False}},
+        params={"country": "NL"},
+        gcp_conn_id="gcp-sales",
+    )
@@ -0,0 +1,25 @@
+# This file is generated by `blast export airflow` from the pipeline "sales", do not edit it by hand.
+import os
+
+import pendulum
+from airflow import DAG
+from airflow.providers.google.cloud.transfers.s3_to_gcs import S3ToGCSOperator
+
+PIPELINE_ROOT = os.path.normpath(os.path.join(os.path.dirname(os.path.abspath(__file__)), "../pipelines/sales"))
+
+with DAG(
+    dag_id="sales",
+    schedule_interval="0 5 * * *",
+    start_date=pendulum.datetime(2022, 1, 1, tz="UTC"),
+    catchup=False,
+    template_searchpath=[PIPELINE_ROOT],
+) as dag:
+    # connections not used by S3ToGCSOperator: slack=alerts
+    copy_exports = S3ToGCSOperator(
+        task_id="copy_exports",
+        bucket="exports",
+        dest_gcs="gs://landing/orders/",
+        prefix="orders/",
+        aws_conn_id="aws-exports",
+        gcp_conn_id="gcp-sales",
+    )
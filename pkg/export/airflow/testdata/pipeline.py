@@ -0,0 +1,70 @@
+# This file is generated by `blast export airflow` from the pipeline "sales", do not edit it by hand.
+import os
+
+import pendulum
+from airflow import DAG
+from airflow.operators.bash import BashOperator
+from airflow.providers.google.cloud.operators.bigquery import BigQueryInsertJobOperator
+from airflow.sensors.external_task import ExternalTaskSensor
+
+PIPELINE_ROOT = os.path.normpath(os.path.join(os.path.dirname(os.path.abspath(__file__)), "../pipelines/sales"))
+
+with DAG(
+    dag_id="sales",
+    schedule_interval=None,
+    start_date=pendulum.datetime(2023, 3, 15, tz="UTC"),
+    catchup=False,
+    template_searchpath=[PIPELINE_ROOT],
+) as dag:
+    raw_orders = BigQueryInsertJobOperator(
+        task_id="raw.orders",
+        configuration={"query": {"query": "{% include 'tasks/raw/orders.sql' %}", "useLegacySql": False}},
+        params={"country": "NL", "env": "production"},
+        gcp_conn_id="gcp-default",
+        retries=2,
+        retry_delay=pendulum.duration(seconds=300),
+    )
+
+    orders_clean = BigQueryInsertJobOperator(
+        task_id="orders_clean",
+        configuration={"query": {"query": "{% include 'tasks/orders_clean.sql' %}", "useLegacySql": False}},
+        params={"country": "DE", "env": "production"},
+        gcp_conn_id="gcp-default",
+        retries=2,
+        retry_delay=pendulum.duration(seconds=300),
+        execution_timeout=pendulum.duration(seconds=1800),
+        sla=pendulum.duration(seconds=5400),
+        pool="bigquery",
+        priority_weight=5,
+    )
+
+    # connections not used by BashOperator: gcpConnectionId=gcp-default
+    import_2 = BashOperator(
+        task_id="import",
+        bash_command="python3 " + os.path.join(PIPELINE_ROOT, "tasks/import.py") + " ",
+        cwd=PIPELINE_ROOT,
+        env={"country": "NL", "env": "production"},
+        append_env=True,
+        retries=2,
+        retry_delay=pendulum.duration(seconds=300),
+    )
+
+    wait_for_crm_orders_export = ExternalTaskSensor(
+        task_id="wait_for_crm.orders_export",
+        external_dag_id="crm",
+        external_task_id="orders_export",
+        mode="reschedule",
+    )
+
+    wait_for_marketing_campaigns = ExternalTaskSensor(
+        task_id="wait_for_marketing.campaigns",
+        external_dag_id="marketing",
+        external_task_id="campaigns",
+        mode="reschedule",
+    )
+
+    wait_for_crm_orders_export >> raw_orders
+    raw_orders >> orders_clean
+    import_2 >> orders_clean
+    wait_for_crm_orders_export >> import_2
+    wait_for_marketing_campaigns >> import_2
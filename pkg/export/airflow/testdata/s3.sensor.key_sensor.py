@@ -0,0 +1,24 @@
+# This file is generated by `blast export airflow` from the pipeline "sales", do not edit it by hand.
+import os
+
+import pendulum
+from airflow import DAG
+from airflow.providers.amazon.aws.sensors.s3 import S3KeySensor
+
+PIPELINE_ROOT = os.path.normpath(os.path.join(os.path.dirname(os.path.abspath(__file__)), "../pipelines/sales"))
+
+with DAG(
+    dag_id="sales",
+    schedule_interval="0 5 * * *",
+    start_date=pendulum.datetime(2022, 1, 1, tz="UTC"),
+    catchup=False,
+    template_searchpath=[PIPELINE_ROOT],
+) as dag:
+    wait_for_export = S3KeySensor(
+        task_id="wait_for_export",
+        bucket_key="orders/_SUCCESS",
+        bucket_name="exports",
+        poke_interval=300,
+        timeout=5400,
+        aws_conn_id="aws-exports",
+    )
@@ -0,0 +1,84 @@
+package format
+
+import (
+	"os"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/sqlparser"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// dialects maps the task types to the dialect of their queries, the files of the other tasks are not formatted.
+var dialects = map[string]sqlparser.Dialect{
+	"bq.sql": sqlparser.BigQuery,
+	"sf.sql": sqlparser.Snowflake,
+}
+
+// Change is the formatted content of a task file, nothing is touched until it is applied.
+type Change struct {
+	Task    *pipeline.Task
+	Path    string
+	Content []byte
+	Mode    os.FileMode
+}
+
+// Formatter rewrites the SQL files of the tasks in the style of sqlparser.Format.
+type Formatter struct {
+	fs afero.Fs
+}
+
+func NewFormatter(fs afero.Fs) *Formatter {
+	return &Formatter{fs: fs}
+}
+
+// Supports reports whether the files of the given task type can be formatted.
+func Supports(taskType string) bool {
+	_, ok := dialects[taskType]
+	return ok
+}
+
+// Plan returns the change that formats the file of the task, or nil if the file is formatted already or the task is
+// not a SQL task.
+func (f *Formatter) Plan(t *pipeline.Task) (*Change, error) {
+	dialect, ok := dialects[t.Type]
+	if !ok || t.ExecutableFile.Path == "" {
+		return nil, nil
+	}
+
+	path := t.ExecutableFile.Path
+	info, err := f.fs.Stat(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the file '%s'", path)
+	}
+
+	content, err := afero.ReadFile(f.fs, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the file '%s'", path)
+	}
+
+	formatted, err := sqlparser.Format(string(content), dialect)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to format the file '%s'", path)
+	}
+
+	if formatted == string(content) {
+		return nil, nil
+	}
+
+	return &Change{
+		Task:    t,
+		Path:    path,
+		Content: []byte(formatted),
+		Mode:    info.Mode(),
+	}, nil
+}
+
+// Apply writes the formatted content, keeping the permissions of the file.
+func (f *Formatter) Apply(change *Change) error {
+	if err := afero.WriteFile(f.fs, change.Path, change.Content, change.Mode); err != nil {
+		return errors.Wrapf(err, "failed to write the file '%s'", change.Path)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package format
+
+import (
+	"os"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func task(taskType, path string) *pipeline.Task {
+	return &pipeline.Task{
+		Name:           "orders",
+		Type:           taskType,
+		ExecutableFile: pipeline.ExecutableFile{Name: "orders.sql", Path: path},
+	}
+}
+
+func TestFormatter_PlanAndApply(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/repo/tasks/orders.sql", []byte("-- @blast.name: orders\n\nselect id from p.d.orders where dt = '{{ ds }}'"), 0o600))
+
+	f := NewFormatter(fs)
+	change, err := f.Plan(task("bq.sql", "/repo/tasks/orders.sql"))
+	require.NoError(t, err)
+	require.NotNil(t, change)
+	require.NoError(t, f.Apply(change))
+
+	content, err := afero.ReadFile(fs, "/repo/tasks/orders.sql")
+	require.NoError(t, err)
+	assert.Equal(t, "-- @blast.name: orders\n\nSELECT\n    id\nFROM p.d.orders\nWHERE dt = '{{ ds }}'\n", string(content))
+
+	info, err := fs.Stat("/repo/tasks/orders.sql")
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	change, err = f.Plan(task("bq.sql", "/repo/tasks/orders.sql"))
+	require.NoError(t, err)
+	assert.Nil(t, change, "a formatted file should not be changed again")
+}
+
+func TestFormatter_Plan(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/repo/tasks/orders.sql", []byte("select 1"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/repo/tasks/broken.sql", []byte("select 'abc"), 0o644))
+
+	tests := []struct {
+		name       string
+		task       *pipeline.Task
+		wantChange bool
+		wantErr    string
+	}{
+		{
+			name:       "snowflake tasks are formatted",
+			task:       task("sf.sql", "/repo/tasks/orders.sql"),
+			wantChange: true,
+		},
+		{
+			name: "other task types are skipped",
+			task: task("python", "/repo/tasks/orders.sql"),
+		},
+		{
+			name: "tasks without a file are skipped",
+			task: task("bq.sql", ""),
+		},
+		{
+			name:    "files that cannot be read",
+			task:    task("bq.sql", "/repo/tasks/missing.sql"),
+			wantErr: "failed to read the file '/repo/tasks/missing.sql'",
+		},
+		{
+			name:    "files that cannot be tokenized",
+			task:    task("bq.sql", "/repo/tasks/broken.sql"),
+			wantErr: "failed to format the file '/repo/tasks/broken.sql': line 1: the string is not closed",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			change, err := NewFormatter(fs).Plan(tt.task)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChange, change != nil)
+		})
+	}
+}
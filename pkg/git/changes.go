@@ -0,0 +1,71 @@
+package git
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ChangedFilesSince returns the absolute paths of the files that changed since the given ref, including the
+// uncommitted and untracked changes in the working tree. The changes are calculated against the merge base of the ref
+// and HEAD, which means the commits that landed on the ref after the current branch was created are not included.
+func ChangedFilesSince(dir, ref string) ([]string, error) {
+	repoRoot, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find the git repository root")
+	}
+	// git resolves the symlinks in the repository root, e.g. /tmp to /private/tmp on macOS, therefore the root is
+	// resolved here as well to make sure the paths are consistent regardless of how the checkout is reached.
+	repoRoot, err = filepath.EvalSymlinks(strings.TrimSpace(repoRoot))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve the git repository root")
+	}
+
+	mergeBase, err := run(dir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the merge base for '%s'", ref)
+	}
+
+	changed, err := run(repoRoot, "diff", "--name-only", "--no-renames", "-z", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the changes since '%s'", ref)
+	}
+
+	untracked, err := run(repoRoot, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the untracked files")
+	}
+
+	seen := make(map[string]bool)
+	files := make([]string, 0)
+	// the NUL-separated output keeps the paths as they are, otherwise git quotes the paths with special characters
+	// based on the core.quotePath setting.
+	for _, file := range strings.Split(changed+"\x00"+untracked, "\x00") {
+		if file == "" || seen[file] {
+			continue
+		}
+
+		seen[file] = true
+		files = append(files, filepath.Join(repoRoot, file))
+	}
+
+	return files, nil
+}
+
+func run(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
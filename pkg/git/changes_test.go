@@ -0,0 +1,104 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangedFilesSince(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	git := func(args ...string) {
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, content string) {
+		target := filepath.Join(repo, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(target), 0o755))
+		require.NoError(t, os.WriteFile(target, []byte(content), 0o644))
+	}
+
+	git("init", "-q", "-b", "main")
+	write("pipeline1/pipeline.yml", "name: pipeline1")
+	write("pipeline1/tasks/task1.sql", "select 1")
+	write("pipeline1/tasks/task2.sql", "select 2")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	git("checkout", "-q", "-b", "feature")
+	write("pipeline1/tasks/task1.sql", "select 11")
+	git("commit", "-q", "-am", "change task1")
+
+	write("pipeline1/pipeline.yml", "name: pipeline1-renamed")
+	write("pipeline2/pipeline.yml", "name: pipeline2")
+
+	got, err := ChangedFilesSince(filepath.Join(repo, "pipeline1"), "main")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(repo, "pipeline1/tasks/task1.sql"),
+		filepath.Join(repo, "pipeline1/pipeline.yml"),
+		filepath.Join(repo, "pipeline2/pipeline.yml"),
+	}, got)
+
+	_, err = ChangedFilesSince(repo, "some-ref-that-does-not-exist")
+	require.Error(t, err)
+}
+
+func TestChangedFilesSince_SymlinkedCheckoutWithNonASCIINames(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	repo := filepath.Join(dir, "real")
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.MkdirAll(repo, 0o755))
+	require.NoError(t, os.Symlink(repo, link))
+
+	git := func(args ...string) {
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, content string) {
+		target := filepath.Join(repo, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(target), 0o755))
+		require.NoError(t, os.WriteFile(target, []byte(content), 0o644))
+	}
+
+	git("init", "-q", "-b", "main")
+	write("pipeline1/pipeline.yml", "name: pipeline1")
+	write("pipeline1/tasks/ü.sql", "select 1")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	write("pipeline1/tasks/ü.sql", "select 2")
+	write("pipeline1/tasks/ç a.sql", "select 3")
+
+	got, err := ChangedFilesSince(filepath.Join(link, "pipeline1"), "main")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(repo, "pipeline1/tasks/ü.sql"),
+		filepath.Join(repo, "pipeline1/tasks/ç a.sql"),
+	}, got)
+}
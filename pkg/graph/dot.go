@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var dotNodeStyles = map[Highlight]string{
+	HighlightSelected:   `style="rounded,filled,bold", fillcolor="#fde68a"`,
+	HighlightUpstream:   `style="rounded,filled", fillcolor="#bfdbfe"`,
+	HighlightDownstream: `style="rounded,filled", fillcolor="#bbf7d0"`,
+	HighlightUnrelated:  `color="#9ca3af", fontcolor="#9ca3af"`,
+}
+
+// WriteDOT renders the graph in the Graphviz DOT language, e.g. to be converted to an image with `dot -Tsvg`.
+func WriteDOT(w io.Writer, g *Graph) error {
+	buf := bufio.NewWriter(w)
+	hasSelection := g.hasSelection()
+
+	fmt.Fprintf(buf, "digraph \"%s\" {\n", dotQuote(g.Pipeline))
+	fmt.Fprintln(buf, "  rankdir=LR;")
+	fmt.Fprintln(buf, `  node [shape=box, style="rounded"];`)
+
+	for _, node := range g.Nodes {
+		label := dotQuote(node.Name) + `\n` + dotQuote(node.Type) + `\n` + dotQuote(node.File)
+		if node.IsExternal() {
+			label = dotQuote(node.QualifiedName())
+		}
+
+		attributes := []string{fmt.Sprintf(`label="%s"`, label)}
+		if node.IsExternal() {
+			attributes = append(attributes, "shape=note")
+		}
+		if style, ok := dotNodeStyles[node.Highlight]; ok {
+			attributes = append(attributes, style)
+		}
+
+		fmt.Fprintf(buf, "  %s [%s];\n", node.ID, strings.Join(attributes, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		attributes := ""
+		switch {
+		case edge.Highlighted:
+			attributes = ` [penwidth=2, color="#2563eb"]`
+		case hasSelection:
+			attributes = ` [color="#9ca3af"]`
+		}
+
+		fmt.Fprintf(buf, "  %s -> %s%s;\n", edge.From, edge.To, attributes)
+	}
+
+	fmt.Fprintln(buf, "}")
+
+	return buf.Flush()
+}
+
+// dotQuote escapes the value to be used inside a double-quoted DOT string, without the surrounding quotes.
+func dotQuote(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/pkg/errors"
+)
+
+// Highlight describes the relation of a node to the selected task, it is empty when there is no selection.
+type Highlight string
+
+const (
+	HighlightNone       Highlight = ""
+	HighlightSelected   Highlight = "selected"
+	HighlightUpstream   Highlight = "upstream"
+	HighlightDownstream Highlight = "downstream"
+	HighlightUnrelated  Highlight = "unrelated"
+)
+
+type Node struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	File      string    `json:"file"`
+	Highlight Highlight `json:"highlight,omitempty"`
+
+	// Pipeline is only set for the tasks in other pipelines that are referenced with cross-pipeline dependencies, their
+	// type and file are unknown since the other pipelines are not built.
+	Pipeline string `json:"pipeline,omitempty"`
+}
+
+// IsExternal returns true if the node is a task in another pipeline.
+func (n *Node) IsExternal() bool {
+	return n.Pipeline != ""
+}
+
+// QualifiedName returns the name to be displayed for the node, including the pipeline for the external tasks.
+func (n *Node) QualifiedName() string {
+	return pipeline.Dependency{Pipeline: n.Pipeline, Task: n.Name}.String()
+}
+
+// Edge points from the upstream task to the task that depends on it, which is the direction the data flows in.
+type Edge struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Highlighted bool   `json:"highlighted,omitempty"`
+}
+
+type Graph struct {
+	Pipeline string  `json:"pipeline"`
+	Nodes    []*Node `json:"nodes"`
+	Edges    []*Edge `json:"edges"`
+}
+
+// New creates the graph of the tasks in the given pipeline. When a task is selected, the nodes are marked with their
+// relation to it and the edges on the paths leading to and from it are highlighted. The dependencies that do not exist
+// in the pipeline are left out, they are reported by the `dependency-exists` rule instead.
+func New(p *pipeline.Pipeline, selected *pipeline.Task) *Graph {
+	g := &Graph{
+		Pipeline: p.Name,
+		Nodes:    make([]*Node, 0, len(p.Tasks)),
+		Edges:    make([]*Edge, 0),
+	}
+
+	highlights := make(map[*pipeline.Task]Highlight)
+	if selected != nil {
+		for _, task := range p.Tasks {
+			highlights[task] = HighlightUnrelated
+		}
+		for _, task := range p.GetUpstreamTasks(selected) {
+			highlights[task] = HighlightUpstream
+		}
+		for _, task := range p.GetDownstreamTasks(selected) {
+			highlights[task] = HighlightDownstream
+		}
+		highlights[selected] = HighlightSelected
+	}
+
+	ids := make(map[string]string, len(p.Tasks))
+	for i, task := range p.Tasks {
+		id := fmt.Sprintf("t%d", i)
+		if _, ok := ids[task.Name]; !ok {
+			ids[task.Name] = id
+		}
+
+		g.Nodes = append(g.Nodes, &Node{
+			ID:        id,
+			Name:      task.Name,
+			Type:      task.Type,
+			File:      p.RelativeTaskPath(task),
+			Highlight: highlights[task],
+		})
+	}
+
+	externalNodes := make(map[string]*Node)
+	for _, task := range p.Tasks {
+		dependencies := make([]string, len(task.DependsOn))
+		copy(dependencies, task.DependsOn)
+		sort.Strings(dependencies)
+
+		for _, dep := range dependencies {
+			dependency := pipeline.ParseDependency(dep)
+			if dependency.IsCrossPipeline() && dependency.Pipeline != p.Name {
+				node := g.externalNode(externalNodes, dependency, selected != nil)
+				if highlights[task] == HighlightSelected || highlights[task] == HighlightUpstream {
+					node.Highlight = HighlightUpstream
+				}
+
+				g.Edges = append(g.Edges, &Edge{
+					From: node.ID,
+					To:   ids[task.Name],
+				})
+				continue
+			}
+
+			upstream := p.GetTaskByName(dependency.Task)
+			if upstream == nil {
+				continue
+			}
+
+			g.Edges = append(g.Edges, &Edge{
+				From:        ids[upstream.Name],
+				To:          ids[task.Name],
+				Highlighted: isOnSelectedPath(highlights[upstream], highlights[task]),
+			})
+		}
+	}
+
+	// the external nodes are highlighted only after all their dependents are known
+	nodesByID := make(map[string]*Node, len(g.Nodes))
+	for _, node := range g.Nodes {
+		nodesByID[node.ID] = node
+	}
+	for _, edge := range g.Edges {
+		if from := nodesByID[edge.From]; from.IsExternal() {
+			edge.Highlighted = isOnSelectedPath(from.Highlight, nodesByID[edge.To].Highlight)
+		}
+	}
+
+	return g
+}
+
+// externalNode returns the node for the task in another pipeline, creating it the first time it is referenced.
+func (g *Graph) externalNode(externalNodes map[string]*Node, dependency pipeline.Dependency, hasSelection bool) *Node {
+	if node, ok := externalNodes[dependency.String()]; ok {
+		return node
+	}
+
+	node := &Node{
+		ID:       fmt.Sprintf("e%d", len(externalNodes)),
+		Name:     dependency.Task,
+		Pipeline: dependency.Pipeline,
+	}
+	if hasSelection {
+		node.Highlight = HighlightUnrelated
+	}
+
+	externalNodes[dependency.String()] = node
+	g.Nodes = append(g.Nodes, node)
+
+	return node
+}
+
+// isOnSelectedPath checks if the edge connects two tasks on the same side of the selected task, the edges between an
+// upstream and a downstream task are not on the path since they bypass the selection.
+func isOnSelectedPath(from, to Highlight) bool {
+	switch {
+	case from == HighlightUpstream:
+		return to == HighlightUpstream || to == HighlightSelected
+	case from == HighlightSelected:
+		return to == HighlightDownstream
+	case from == HighlightDownstream:
+		return to == HighlightDownstream
+	}
+
+	return false
+}
+
+type writer func(w io.Writer, g *Graph) error
+
+var writers = map[string]writer{
+	"dot":     WriteDOT,
+	"mermaid": WriteMermaid,
+	"json":    WriteJSON,
+}
+
+// Formats returns the names of the supported output formats.
+func Formats() []string {
+	formats := make([]string, 0, len(writers))
+	for format := range writers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+
+	return formats
+}
+
+// Write renders the graph in the given format.
+func Write(w io.Writer, g *Graph, format string) error {
+	write, ok := writers[format]
+	if !ok {
+		return errors.Errorf("unknown graph format '%s', the supported formats are %v", format, Formats())
+	}
+
+	return write(w, g)
+}
+
+func (g *Graph) hasSelection() bool {
+	for _, node := range g.Nodes {
+		if node.Highlight != HighlightNone {
+			return true
+		}
+	}
+
+	return false
+}
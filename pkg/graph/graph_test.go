@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func testPipeline() *pipeline.Pipeline {
+	task := func(name, taskType, file string, dependsOn ...string) *pipeline.Task {
+		return &pipeline.Task{
+			Name: name,
+			Type: taskType,
+			DefinitionFile: pipeline.DefinitionFile{
+				Path: "/pipelines/sales/tasks/" + file,
+			},
+			DependsOn: dependsOn,
+		}
+	}
+
+	return &pipeline.Pipeline{
+		Name: "sales",
+		DefinitionFile: pipeline.DefinitionFile{
+			Path: "/pipelines/sales/pipeline.yml",
+		},
+		Tasks: []*pipeline.Task{
+			task("raw.orders", "bq.sql", "raw/orders.sql"),
+			task("raw.customers", "bq.sql", "raw/customers.sql"),
+			task("orders_clean", "bq.sql", "orders_clean.sql", "raw.orders", "missing-task", "crm:orders_export"),
+			task("customer_orders", "bq.sql", "customer_orders.sql", "raw.customers", "orders_clean"),
+			task("export \"report\"", "python", "export/task.yml", "customer_orders", "raw.orders"),
+			task("notify", "bash", "notify.sh", "marketing:campaigns"),
+			task("refunds", "bq.sql", "refunds.sql", "finance:refunds", "sales:raw.orders"),
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	p := testPipeline()
+	g := New(p, p.GetTaskByName("orders_clean"))
+
+	externalNodes := make([]string, 0)
+	for _, node := range g.Nodes {
+		if node.IsExternal() {
+			externalNodes = append(externalNodes, node.QualifiedName())
+		}
+	}
+	assert.Equal(t, []string{"crm:orders_export", "marketing:campaigns", "finance:refunds"}, externalNodes)
+
+	highlights := make(map[string]Highlight)
+	for _, node := range g.Nodes {
+		highlights[node.Name] = node.Highlight
+	}
+
+	assert.Equal(t, map[string]Highlight{
+		"raw.orders":        HighlightUpstream,
+		"raw.customers":     HighlightUnrelated,
+		"orders_clean":      HighlightSelected,
+		"customer_orders":   HighlightDownstream,
+		"export \"report\"": HighlightDownstream,
+		"notify":            HighlightUnrelated,
+		"refunds":           HighlightUnrelated,
+		"campaigns":         HighlightUnrelated,
+		"orders_export":     HighlightUpstream,
+	}, highlights)
+
+	assert.Equal(t, []*Edge{
+		{From: "e0", To: "t2", Highlighted: true},
+		{From: "t0", To: "t2", Highlighted: true},
+		{From: "t2", To: "t3", Highlighted: true},
+		{From: "t1", To: "t3"},
+		{From: "t3", To: "t4", Highlighted: true},
+		{From: "t0", To: "t4"},
+		{From: "e1", To: "t5"},
+		{From: "e2", To: "t6"},
+		{From: "t0", To: "t6"},
+	}, g.Edges)
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	p := testPipeline()
+
+	tests := []struct {
+		name       string
+		format     string
+		selected   string
+		goldenFile string
+	}{
+		{name: "dot", format: "dot", goldenFile: "sales.dot"},
+		{name: "dot with a selected task", format: "dot", selected: "orders_clean", goldenFile: "sales-selected.dot"},
+		{name: "mermaid", format: "mermaid", goldenFile: "sales.mmd"},
+		{name: "mermaid with a selected task", format: "mermaid", selected: "orders_clean", goldenFile: "sales-selected.mmd"},
+		{name: "json", format: "json", goldenFile: "sales.json"},
+		{name: "json with a selected task", format: "json", selected: "orders_clean", goldenFile: "sales-selected.json"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var selected *pipeline.Task
+			if tt.selected != "" {
+				selected = p.GetTaskByName(tt.selected)
+			}
+
+			var buf bytes.Buffer
+			err := Write(&buf, New(p, selected), tt.format)
+			require.NoError(t, err)
+
+			goldenPath := filepath.Join("testdata", tt.goldenFile)
+			if *update {
+				require.NoError(t, os.WriteFile(goldenPath, buf.Bytes(), 0o600))
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), buf.String())
+		})
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	err := Write(&bytes.Buffer{}, New(testPipeline(), nil), "svg")
+	require.EqualError(t, err, "unknown graph format 'svg', the supported formats are [dot json mermaid]")
+}
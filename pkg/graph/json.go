@@ -0,0 +1,14 @@
+package graph
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriteJSON renders the graph as an indented JSON document, to be consumed by other tools.
+func WriteJSON(w io.Writer, g *Graph) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(g)
+}
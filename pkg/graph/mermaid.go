@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var mermaidClassDefinitions = []struct {
+	highlight Highlight
+	style     string
+}{
+	{HighlightSelected, "fill:#fde68a,stroke:#b45309,stroke-width:2px"},
+	{HighlightUpstream, "fill:#bfdbfe,stroke:#1d4ed8"},
+	{HighlightDownstream, "fill:#bbf7d0,stroke:#15803d"},
+	{HighlightUnrelated, "fill:#f3f4f6,stroke:#9ca3af,color:#9ca3af"},
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart, which can be embedded in Markdown files and pull requests.
+func WriteMermaid(w io.Writer, g *Graph) error {
+	buf := bufio.NewWriter(w)
+
+	fmt.Fprintln(buf, "flowchart LR")
+
+	nodesByHighlight := make(map[Highlight][]string)
+	for _, node := range g.Nodes {
+		if node.IsExternal() {
+			// the subroutine shape sets the tasks from the other pipelines apart
+			fmt.Fprintf(buf, "    %s[[\"%s\"]]\n", node.ID, mermaidEscape(node.QualifiedName()))
+		} else {
+			label := strings.Join([]string{mermaidEscape(node.Name), mermaidEscape(node.Type), mermaidEscape(node.File)}, "<br/>")
+			fmt.Fprintf(buf, "    %s[\"%s\"]\n", node.ID, label)
+		}
+
+		nodesByHighlight[node.Highlight] = append(nodesByHighlight[node.Highlight], node.ID)
+	}
+
+	highlightedEdges := make([]string, 0)
+	for i, edge := range g.Edges {
+		fmt.Fprintf(buf, "    %s --> %s\n", edge.From, edge.To)
+
+		if edge.Highlighted {
+			highlightedEdges = append(highlightedEdges, strconv.Itoa(i))
+		}
+	}
+
+	for _, definition := range mermaidClassDefinitions {
+		nodes := nodesByHighlight[definition.highlight]
+		if len(nodes) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(buf, "    classDef %s %s\n", definition.highlight, definition.style)
+		fmt.Fprintf(buf, "    class %s %s\n", strings.Join(nodes, ","), definition.highlight)
+	}
+
+	if len(highlightedEdges) > 0 {
+		fmt.Fprintf(buf, "    linkStyle %s stroke:#2563eb,stroke-width:2px\n", strings.Join(highlightedEdges, ","))
+	}
+
+	return buf.Flush()
+}
+
+// mermaidEscape replaces the characters that would break a quoted Mermaid label with their entity codes.
+func mermaidEscape(value string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(value)
+}
@@ -0,0 +1,255 @@
+package inspect
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+)
+
+// SchemaVersion is increased whenever a field is removed, renamed or changes its meaning. The new fields are added
+// without changing the version, therefore the consumers must ignore the fields they do not know.
+const SchemaVersion = 1
+
+type Kind string
+
+const (
+	KindPipeline     Kind = "pipeline"
+	KindTask         Kind = "task"
+	KindPipelineList Kind = "pipelineList"
+	KindTaskList     Kind = "taskList"
+)
+
+// Header starts all the JSON documents, which allows the consumers to check the version before reading the rest.
+type Header struct {
+	SchemaVersion int  `json:"schemaVersion"`
+	Kind          Kind `json:"kind"`
+}
+
+type PipelineDocument struct {
+	Header
+	Pipeline *Pipeline `json:"pipeline"`
+}
+
+type TaskDocument struct {
+	Header
+	Task *Task `json:"task"`
+}
+
+type PipelineListDocument struct {
+	Header
+	Pipelines []*Pipeline `json:"pipelines"`
+}
+
+type TaskListDocument struct {
+	Header
+	Tasks []*Task `json:"tasks"`
+}
+
+type Pipeline struct {
+	Name               string            `json:"name"`
+	Schedule           string            `json:"schedule"`
+	DefinitionFile     string            `json:"definitionFile"`
+	DefaultParameters  map[string]string `json:"defaultParameters"`
+	DefaultConnections map[string]string `json:"defaultConnections"`
+	Owner              string            `json:"owner"`
+	Team               string            `json:"team"`
+	Tags               []string          `json:"tags"`
+	Meta               map[string]string `json:"meta"`
+	DefaultPolicy      *Policy           `json:"defaultPolicy"`
+	Tasks              []*Task           `json:"tasks"`
+}
+
+// Policy is the execution policy as written in the definition files, the fields that are not set are null.
+type Policy struct {
+	Retries    *int    `json:"retries"`
+	RetryDelay *string `json:"retryDelay"`
+	Timeout    *string `json:"timeout"`
+	SLA        *string `json:"sla"`
+	Pool       *string `json:"pool"`
+	Priority   *int    `json:"priority"`
+}
+
+type Definition struct {
+	Type pipeline.TaskDefinitionType `json:"type"`
+	Path string                      `json:"path"`
+}
+
+type Task struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Type        string     `json:"type"`
+	Pipeline    string     `json:"pipeline"`
+	Definition  Definition `json:"definition"`
+
+	// ExecutableFile is the absolute path of the file to run, it is empty for the tasks that do not run a file.
+	ExecutableFile string   `json:"executableFile"`
+	DependsOn      []string `json:"dependsOn"`
+
+	// Parameters and Connections are the effective values, with the defaults of the pipeline merged in. The parameters
+	// include the defaults of the task type as well, the same values the `valid-task-parameters` rule checks.
+	Parameters  map[string]string `json:"parameters"`
+	Connections map[string]string `json:"connections"`
+
+	// Owner, Team, Tags and Meta are the effective values as well, the ones of the pipeline are used as the defaults.
+	Owner string            `json:"owner"`
+	Team  string            `json:"team"`
+	Tags  []string          `json:"tags"`
+	Meta  map[string]string `json:"meta"`
+
+	// Policy is the effective execution policy, with the default policy of the pipeline applied.
+	Policy *Policy `json:"policy"`
+}
+
+func NewPipeline(p *pipeline.Pipeline, registry *tasktype.Registry) *Pipeline {
+	tasks := make([]*Task, 0, len(p.Tasks))
+	for _, t := range p.Tasks {
+		tasks = append(tasks, NewTask(p, t, registry))
+	}
+
+	return &Pipeline{
+		Name:               p.Name,
+		Schedule:           string(p.Schedule),
+		DefinitionFile:     p.DefinitionFile.Path,
+		DefaultParameters:  copyMap(p.DefaultParameters),
+		DefaultConnections: copyMap(p.DefaultConnections),
+		Owner:              p.Owner,
+		Team:               p.Team,
+		Tags:               copySlice(p.Tags),
+		Meta:               copyMap(p.Meta),
+		DefaultPolicy:      NewPolicy(p.DefaultPolicy),
+		Tasks:              tasks,
+	}
+}
+
+func NewTask(p *pipeline.Pipeline, t *pipeline.Task, registry *tasktype.Registry) *Task {
+	return &Task{
+		Name:        t.Name,
+		Description: t.Description,
+		Type:        t.Type,
+		Pipeline:    p.Name,
+		Definition: Definition{
+			Type: t.DefinitionFile.Type,
+			Path: t.DefinitionFile.Path,
+		},
+		ExecutableFile: t.ExecutableFile.Path,
+		DependsOn:      copySlice(t.DependsOn),
+		Parameters:     p.EffectiveParameters(t, registry),
+		Connections:    p.EffectiveConnections(t),
+		Owner:          p.EffectiveOwner(t),
+		Team:           p.EffectiveTeam(t),
+		Tags:           p.EffectiveTags(t),
+		Meta:           p.EffectiveMeta(t),
+		Policy:         NewPolicy(p.EffectivePolicy(t)),
+	}
+}
+
+func NewPolicy(e pipeline.ExecutionPolicy) *Policy {
+	optional := func(value string) *string {
+		if value == "" {
+			return nil
+		}
+
+		return &value
+	}
+
+	return &Policy{
+		Retries:    copyInt(e.Retries),
+		RetryDelay: optional(e.RetryDelay),
+		Timeout:    optional(e.Timeout),
+		SLA:        optional(e.SLA),
+		Pool:       optional(e.Pool),
+		Priority:   copyInt(e.Priority),
+	}
+}
+
+func NewPipelineDocument(p *pipeline.Pipeline, registry *tasktype.Registry) *PipelineDocument {
+	return &PipelineDocument{Header: newHeader(KindPipeline), Pipeline: NewPipeline(p, registry)}
+}
+
+func NewTaskDocument(p *pipeline.Pipeline, t *pipeline.Task, registry *tasktype.Registry) *TaskDocument {
+	return &TaskDocument{Header: newHeader(KindTask), Task: NewTask(p, t, registry)}
+}
+
+// NewPipelineListDocument sorts the pipelines by name, so that the output does not depend on the file system.
+func NewPipelineListDocument(pipelines []*pipeline.Pipeline, registry *tasktype.Registry) *PipelineListDocument {
+	doc := &PipelineListDocument{Header: newHeader(KindPipelineList), Pipelines: make([]*Pipeline, 0, len(pipelines))}
+	for _, p := range sortedPipelines(pipelines) {
+		doc.Pipelines = append(doc.Pipelines, NewPipeline(p, registry))
+	}
+
+	return doc
+}
+
+// NewTaskListDocument lists the tasks of all the given pipelines, sorted by the pipeline and the task names.
+func NewTaskListDocument(pipelines []*pipeline.Pipeline, registry *tasktype.Registry) *TaskListDocument {
+	doc := &TaskListDocument{Header: newHeader(KindTaskList), Tasks: make([]*Task, 0)}
+	for _, p := range sortedPipelines(pipelines) {
+		for _, t := range sortedTasks(p) {
+			doc.Tasks = append(doc.Tasks, NewTask(p, t, registry))
+		}
+	}
+
+	return doc
+}
+
+func newHeader(kind Kind) Header {
+	return Header{SchemaVersion: SchemaVersion, Kind: kind}
+}
+
+// Write renders any of the documents as indented JSON.
+func Write(w io.Writer, doc interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(doc)
+}
+
+func sortedPipelines(pipelines []*pipeline.Pipeline) []*pipeline.Pipeline {
+	sorted := make([]*pipeline.Pipeline, len(pipelines))
+	copy(sorted, pipelines)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return sorted
+}
+
+func sortedTasks(p *pipeline.Pipeline) []*pipeline.Task {
+	sorted := make([]*pipeline.Task, len(p.Tasks))
+	copy(sorted, p.Tasks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	return sorted
+}
+
+// copyMap never returns nil, which keeps the empty maps as `{}` in the output instead of `null`.
+func copyMap(values map[string]string) map[string]string {
+	copied := make(map[string]string, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+
+	return copied
+}
+
+// copySlice never returns nil, which keeps the empty lists as `[]` in the output instead of `null`.
+func copySlice(values []string) []string {
+	copied := make([]string, len(values))
+	copy(copied, values)
+
+	return copied
+}
+
+func copyInt(value *int) *int {
+	if value == nil {
+		return nil
+	}
+
+	copied := *value
+	return &copied
+}
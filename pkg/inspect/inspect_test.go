@@ -0,0 +1,135 @@
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func assertGolden(t *testing.T, goldenFile string, doc interface{}) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, doc))
+
+	goldenPath := filepath.Join("testdata", goldenFile)
+	if *update {
+		require.NoError(t, os.WriteFile(goldenPath, buf.Bytes(), 0o600))
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), buf.String())
+}
+
+func newPipelines() []*pipeline.Pipeline {
+	defaultRetries := 2
+	sales := &pipeline.Pipeline{
+		Name:               "sales",
+		Schedule:           "0 5 * * *",
+		DefinitionFile:     pipeline.DefinitionFile{Name: "pipeline.yml", Path: "/repo/sales/pipeline.yml"},
+		DefaultParameters:  map[string]string{"dataset": "sales", "location": "EU"},
+		DefaultConnections: map[string]string{"gcpConnectionId": "gcp-default"},
+		Owner:              "sales@example.com",
+		Tags:               []string{"daily"},
+		Meta:               map[string]string{"tier": "silver"},
+		DefaultPolicy:      pipeline.ExecutionPolicy{Retries: &defaultRetries, Timeout: "1h"},
+		Tasks: []*pipeline.Task{
+			{
+				Name:           "orders",
+				Description:    "Builds the daily orders.",
+				Type:           "bq.sql",
+				ExecutableFile: pipeline.ExecutableFile{Name: "orders.sql", Path: "/repo/sales/tasks/orders.sql"},
+				DefinitionFile: pipeline.DefinitionFile{Name: "orders.sql", Path: "/repo/sales/tasks/orders.sql", Type: pipeline.CommentTask},
+				DependsOn:      []string{"customers", "raw:orders"},
+				Parameters:     map[string]string{"dataset": "sales_eu"},
+				Connections:    map[string]string{},
+				Owner:          "jane@example.com",
+				Team:           "analytics",
+				Tags:           []string{"finance", "daily"},
+				Meta:           map[string]string{"tier": "gold"},
+				Policy:         pipeline.ExecutionPolicy{Pool: "bigquery", SLA: "2h"},
+			},
+			{
+				Name:           "customers",
+				Type:           "bq.sensor.table",
+				DefinitionFile: pipeline.DefinitionFile{Name: "task.yml", Path: "/repo/sales/tasks/customers/task.yml", Type: pipeline.YamlTask},
+				Parameters:     map[string]string{"table": "project.sales.customers"},
+				Connections:    map[string]string{"gcpConnectionId": "gcp-sensors"},
+			},
+		},
+	}
+
+	raw := &pipeline.Pipeline{
+		Name:           "raw",
+		DefinitionFile: pipeline.DefinitionFile{Name: "pipeline.yml", Path: "/repo/raw/pipeline.yml"},
+		Tasks: []*pipeline.Task{
+			{
+				Name:           "orders",
+				Type:           "python",
+				ExecutableFile: pipeline.ExecutableFile{Name: "main.py", Path: "/repo/raw/tasks/orders/main.py"},
+				DefinitionFile: pipeline.DefinitionFile{Name: "task.yml", Path: "/repo/raw/tasks/orders/task.yml", Type: pipeline.YamlTask},
+			},
+		},
+	}
+
+	return []*pipeline.Pipeline{sales, raw}
+}
+
+func TestNewPipelineDocument(t *testing.T) {
+	t.Parallel()
+
+	assertGolden(t, "pipeline.json", NewPipelineDocument(newPipelines()[0], tasktype.Builtin()))
+}
+
+func TestNewTaskDocument(t *testing.T) {
+	t.Parallel()
+
+	p := newPipelines()[0]
+	assertGolden(t, "task.json", NewTaskDocument(p, p.Tasks[0], tasktype.Builtin()))
+}
+
+func TestNewPipelineListDocument(t *testing.T) {
+	t.Parallel()
+
+	assertGolden(t, "pipelines.json", NewPipelineListDocument(newPipelines(), tasktype.Builtin()))
+}
+
+func TestNewTaskListDocument(t *testing.T) {
+	t.Parallel()
+
+	assertGolden(t, "tasks.json", NewTaskListDocument(newPipelines(), tasktype.Builtin()))
+}
+
+func TestDocumentsDoNotShareTheTaskValues(t *testing.T) {
+	t.Parallel()
+
+	p := newPipelines()[0]
+	doc := NewTaskDocument(p, p.Tasks[0], tasktype.Builtin())
+	doc.Task.Parameters["dataset"] = "changed"
+	doc.Task.DependsOn[0] = "changed"
+
+	assert.Equal(t, "sales_eu", p.Tasks[0].Parameters["dataset"])
+	assert.Equal(t, "customers", p.Tasks[0].DependsOn[0])
+}
+
+func TestEmptyListsAreKeptInTheDocuments(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, NewTaskListDocument(nil, tasktype.Builtin())))
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, map[string]interface{}{"schemaVersion": float64(SchemaVersion), "kind": "taskList", "tasks": []interface{}{}}, decoded)
+}
@@ -0,0 +1,138 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Draft is the version of the JSON Schema specification the generated schemas follow.
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+// Schema is the subset of JSON Schema that is needed to describe the YAML files, the schemas with an empty type accept
+// any value.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	ID          string             `json:"$id,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+
+	// AdditionalProperties is the schema of the values of a map. The objects without it do not accept any keys other
+	// than the ones in Properties.
+	AdditionalProperties *Schema `json:"-"`
+}
+
+// MarshalJSON writes `additionalProperties: false` for the objects that do not accept unknown keys, which is what
+// allows the editors to report the typos.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	out := struct {
+		*plain
+		AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+	}{plain: (*plain)(s)}
+
+	if s.Type == TypeObject {
+		if s.AdditionalProperties != nil {
+			out.AdditionalProperties = s.AdditionalProperties
+		} else {
+			out.AdditionalProperties = false
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+// Reflect generates the schema of the YAML representation of the given value, following the same rules as yaml.v3:
+// the keys are taken from the `yaml` tags, or the lowercase field names if there is no tag, and the fields tagged with
+// `yaml:"-"` are skipped. The `description` tag of the fields is used as their description, and the fields that have
+// `required` in their `validate` tag are marked as required.
+func Reflect(v interface{}) *Schema {
+	return reflectType(reflect.TypeOf(v))
+}
+
+func reflectType(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: TypeString}
+	case reflect.Bool:
+		return &Schema{Type: TypeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: TypeInteger}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TypeNumber}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: TypeArray, Items: reflectType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: TypeObject, AdditionalProperties: reflectType(t.Elem())}
+	case reflect.Struct:
+		schema := &Schema{Type: TypeObject, Properties: make(map[string]*Schema)}
+		reflectFields(t, schema)
+		return schema
+	default:
+		return &Schema{}
+	}
+}
+
+func reflectFields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if strings.Contains(options, "inline") {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				reflectFields(fieldType, schema)
+			}
+
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		fieldSchema := reflectType(field.Type)
+		fieldSchema.Description = field.Tag.Get("description")
+		schema.Properties[name] = fieldSchema
+
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			if rule == "required" {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City string `yaml:"city" validate:"required"`
+}
+
+type base struct {
+	ID string `yaml:"id"`
+}
+
+type person struct {
+	base      `yaml:",inline"`
+	Name      string            `yaml:"name" description:"The full name." validate:"required"`
+	Age       int               `yaml:"age,omitempty"`
+	Height    float64           `yaml:"height"`
+	Active    bool              `yaml:"active"`
+	Nickname  *string           `yaml:"nickname"`
+	Skills    []string          `yaml:"skills"`
+	Labels    map[string]string `yaml:"labels"`
+	Addresses []address         `yaml:"addresses"`
+	Extra     interface{}       `yaml:"extra"`
+	Untagged  string
+	Internal  string `yaml:"-"`
+}
+
+func TestReflect(t *testing.T) {
+	t.Parallel()
+
+	want := &Schema{
+		Type: TypeObject,
+		Properties: map[string]*Schema{
+			"id":       {Type: TypeString},
+			"name":     {Type: TypeString, Description: "The full name."},
+			"age":      {Type: TypeInteger},
+			"height":   {Type: TypeNumber},
+			"active":   {Type: TypeBoolean},
+			"nickname": {Type: TypeString},
+			"skills":   {Type: TypeArray, Items: &Schema{Type: TypeString}},
+			"labels":   {Type: TypeObject, AdditionalProperties: &Schema{Type: TypeString}},
+			"addresses": {
+				Type: TypeArray,
+				Items: &Schema{
+					Type:       TypeObject,
+					Properties: map[string]*Schema{"city": {Type: TypeString}},
+					Required:   []string{"city"},
+				},
+			},
+			"extra":    {},
+			"untagged": {Type: TypeString},
+		},
+		Required: []string{"name"},
+	}
+
+	assert.Equal(t, want, Reflect(&person{}))
+}
+
+func TestSchema_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	schema := &Schema{
+		Schema: Draft,
+		Type:   TypeObject,
+		Properties: map[string]*Schema{
+			"type":   {Type: TypeString, Enum: []string{"bash", "python"}},
+			"labels": {Type: TypeObject, AdditionalProperties: &Schema{Type: TypeString}},
+			"extra":  {},
+		},
+	}
+
+	got, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"type": {"type": "string", "enum": ["bash", "python"]},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"extra": {}
+		},
+		"additionalProperties": false
+	}`, string(got))
+}
@@ -0,0 +1,317 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type ViolationKind string
+
+const (
+	// ViolationUnknownKey and ViolationDuplicateKey do not prevent decoding the document, the unknown keys are ignored
+	// and the last value of the duplicate keys is used.
+	ViolationUnknownKey   ViolationKind = "unknown-key"
+	ViolationDuplicateKey ViolationKind = "duplicate-key"
+
+	ViolationMissingKey   ViolationKind = "missing-key"
+	ViolationInvalidValue ViolationKind = "invalid-value"
+)
+
+// Violation is a value in a YAML document that does not match the schema, the line and the column are 1-based.
+type Violation struct {
+	Kind    ViolationKind
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// IsRecoverable reports whether the document can still be decoded despite the violation.
+func (v *Violation) IsRecoverable() bool {
+	return v.Kind == ViolationUnknownKey || v.Kind == ViolationDuplicateKey
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("line %d: %s", v.Line, v.Message)
+}
+
+// Violations is the list of all the problems in a document, it is returned as a single error.
+type Violations []*Violation
+
+func (v Violations) Error() string {
+	messages := make([]string, len(v))
+	for i, violation := range v {
+		messages[i] = violation.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Validate checks the YAML document against the schema and returns all the violations in the order they appear in the
+// document. The scalars are only checked for the types that yaml.v3 cannot convert, e.g. a number is a valid string.
+func Validate(schema *Schema, node *yaml.Node) Violations {
+	v := &validator{}
+	v.validate(schema, node, "")
+
+	return v.violations
+}
+
+type validator struct {
+	violations Violations
+}
+
+func (v *validator) report(kind ViolationKind, node *yaml.Node, path string, format string, args ...interface{}) {
+	v.violations = append(v.violations, &Violation{
+		Kind:    kind,
+		Path:    path,
+		Line:    node.Line,
+		Column:  node.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (v *validator) validate(schema *Schema, node *yaml.Node, path string) {
+	switch node.Kind {
+	case 0:
+		// the empty documents do not have any nodes
+		return
+	case yaml.DocumentNode:
+		for _, content := range node.Content {
+			v.validate(schema, content, path)
+		}
+		return
+	case yaml.AliasNode:
+		v.validate(schema, node.Alias, path)
+		return
+	}
+
+	// yaml.v3 leaves the zero value for the nulls, which is valid for all the types
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+
+	switch schema.Type {
+	case "":
+		return
+	case TypeObject:
+		v.validateObject(schema, node, path)
+	case TypeArray:
+		if node.Kind != yaml.SequenceNode {
+			v.report(ViolationInvalidValue, node, path, "%s must be %s", describePath(path), describeType(schema.Type))
+			return
+		}
+
+		for i, item := range node.Content {
+			v.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	default:
+		v.validateScalar(schema, node, path)
+	}
+}
+
+func (v *validator) validateObject(schema *Schema, node *yaml.Node, path string) {
+	if node.Kind != yaml.MappingNode {
+		v.report(ViolationInvalidValue, node, path, "%s must be %s", describePath(path), describeType(schema.Type))
+		return
+	}
+
+	seen := make(map[string]bool, len(node.Content)/2)
+	definedOn := make(map[string]int, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		// the merge keys bring the keys of other mappings, which are checked against the same schema, except the
+		// required keys, which only need to be in one of them
+		if key.Tag == "!!merge" {
+			merged := *schema
+			merged.Required = nil
+			v.validate(&merged, value, path)
+			for _, mergedKey := range mergedKeys(value) {
+				seen[mergedKey] = true
+			}
+
+			continue
+		}
+
+		seen[key.Value] = true
+		keyPath := joinPath(path, key.Value)
+		if line, ok := definedOn[key.Value]; ok {
+			v.report(ViolationDuplicateKey, key, keyPath, "duplicate key `%s`, it is already defined on line %d and only the last value is used", keyPath, line)
+		}
+		definedOn[key.Value] = key.Line
+
+		if propertySchema, ok := schema.Properties[key.Value]; ok {
+			v.validate(propertySchema, value, keyPath)
+			continue
+		}
+
+		if schema.AdditionalProperties != nil {
+			v.validate(schema.AdditionalProperties, value, keyPath)
+			continue
+		}
+
+		message := fmt.Sprintf("unknown key `%s`", keyPath)
+		if suggestion := ClosestKey(key.Value, sortedKeys(schema.Properties)); suggestion != "" {
+			message += fmt.Sprintf(", did you mean `%s`?", joinPath(path, suggestion))
+		} else {
+			message += fmt.Sprintf(", the allowed keys are: %s", strings.Join(sortedKeys(schema.Properties), ", "))
+		}
+		v.report(ViolationUnknownKey, key, keyPath, "%s", message)
+	}
+
+	for _, required := range schema.Required {
+		if !seen[required] {
+			v.report(ViolationMissingKey, node, path, "the key `%s` is required", joinPath(path, required))
+		}
+	}
+}
+
+func (v *validator) validateScalar(schema *Schema, node *yaml.Node, path string) {
+	if node.Kind != yaml.ScalarNode {
+		v.report(ViolationInvalidValue, node, path, "%s must be %s", describePath(path), describeType(schema.Type))
+		return
+	}
+
+	valid := true
+	switch schema.Type {
+	case TypeInteger:
+		valid = node.Tag == "!!int"
+	case TypeNumber:
+		valid = node.Tag == "!!int" || node.Tag == "!!float"
+	case TypeBoolean:
+		valid = node.Tag == "!!bool"
+	}
+	if !valid {
+		v.report(ViolationInvalidValue, node, path, "%s must be %s, got `%s`", describePath(path), describeType(schema.Type), node.Value)
+		return
+	}
+
+	if len(schema.Enum) == 0 {
+		return
+	}
+
+	for _, allowed := range schema.Enum {
+		if node.Value == allowed {
+			return
+		}
+	}
+
+	v.report(ViolationInvalidValue, node, path, "`%s` is not a valid value for %s, the allowed values are: %s", node.Value, describePath(path), strings.Join(schema.Enum, ", "))
+}
+
+// mergedKeys returns the keys brought by a merge key, whose value is either a mapping or a list of mappings.
+func mergedKeys(node *yaml.Node) []string {
+	if node.Kind == yaml.AliasNode {
+		return mergedKeys(node.Alias)
+	}
+
+	keys := make([]string, 0)
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Tag == "!!merge" {
+				keys = append(keys, mergedKeys(node.Content[i+1])...)
+				continue
+			}
+
+			keys = append(keys, node.Content[i].Value)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			keys = append(keys, mergedKeys(item)...)
+		}
+	}
+
+	return keys
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func describePath(path string) string {
+	if path == "" {
+		return "the document"
+	}
+
+	return fmt.Sprintf("`%s`", path)
+}
+
+func describeType(schemaType string) string {
+	switch schemaType {
+	case TypeObject:
+		return "a map"
+	case TypeArray:
+		return "a list"
+	case TypeInteger:
+		return "an integer"
+	default:
+		return "a " + schemaType
+	}
+}
+
+func sortedKeys(properties map[string]*Schema) []string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ClosestKey returns the known key that is at most two edits away from the given one, which catches the typical typos
+// such as `depend` instead of `depends`. The first one is returned if multiple keys are equally close.
+func ClosestKey(key string, known []string) string {
+	const maxDistance = 2
+
+	closest := ""
+	closestDistance := maxDistance + 1
+	for _, candidate := range known {
+		distance := editDistance(strings.ToLower(key), strings.ToLower(candidate))
+		if distance < closestDistance && distance < len(key) {
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+
+	return closest
+}
+
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
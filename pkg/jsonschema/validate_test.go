@@ -0,0 +1,132 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	schema := Reflect(person{})
+	schema.Properties["extra"] = &Schema{Type: TypeString, Enum: []string{"a", "b"}}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "valid document",
+			content: "id: 1\nname: jane\nage: 30\nheight: 1.65\nactive: true\nskills: [go]\nlabels:\n  team: data\naddresses:\n  - city: berlin\nextra: a\nuntagged: x\n",
+		},
+		{
+			name:    "empty document",
+			content: "",
+		},
+		{
+			name:    "nulls are accepted for all the types",
+			content: "name: jane\nage: ~\nskills:\nlabels: null\n",
+		},
+		{
+			name:    "unknown keys are reported with a suggestion",
+			content: "name: jane\nskill:\n  - go\n",
+			want:    []string{"line 2: unknown key `skill`, did you mean `skills`?"},
+		},
+		{
+			name:    "unknown keys without a close match list the allowed keys",
+			content: "name: jane\nfavoriteColor: blue\n",
+			want:    []string{"line 2: unknown key `favoriteColor`, the allowed keys are: active, addresses, age, extra, height, id, labels, name, nickname, skills, untagged"},
+		},
+		{
+			name:    "nested keys are reported with their path",
+			content: "name: jane\naddresses:\n  - city: berlin\n  - town: paris\n",
+			want: []string{
+				"line 4: unknown key `addresses[1].town`, the allowed keys are: city",
+				"line 4: the key `addresses[1].city` is required",
+			},
+		},
+		{
+			name:    "required keys are reported",
+			content: "age: 30\n",
+			want:    []string{"line 1: the key `name` is required"},
+		},
+		{
+			name:    "wrong types are reported",
+			content: "name: jane\nage: thirty\nactive: yes please\nskills: go\nlabels: [a]\nheight: tall\n",
+			want: []string{
+				"line 2: `age` must be an integer, got `thirty`",
+				"line 3: `active` must be a boolean, got `yes please`",
+				"line 4: `skills` must be a list",
+				"line 5: `labels` must be a map",
+				"line 6: `height` must be a number, got `tall`",
+			},
+		},
+		{
+			name:    "scalars that can be converted are valid strings",
+			content: "name: 123\nskills: [true, 1.5]\n",
+		},
+		{
+			name:    "values outside the enum are reported",
+			content: "name: jane\nextra: c\n",
+			want:    []string{"line 2: `c` is not a valid value for `extra`, the allowed values are: a, b"},
+		},
+		{
+			name:    "the document must be a map",
+			content: "- name: jane\n",
+			want:    []string{"line 1: the document must be a map"},
+		},
+		{
+			name:    "merged keys count for the required keys",
+			content: "addresses:\n  - &home\n    city: berlin\n  - <<: *home\nname: jane\n",
+		},
+		{
+			name:    "aliases are followed",
+			content: "name: jane\nskills: &skills [go]\nlabels:\n  <<: {colour: blue}\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var node yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tt.content), &node))
+
+			got := make([]string, 0)
+			for _, violation := range Validate(schema, &node) {
+				got = append(got, violation.Error())
+			}
+
+			if len(tt.want) == 0 {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidate_MergeKeys(t *testing.T) {
+	t.Parallel()
+
+	schema := &Schema{
+		Type: TypeObject,
+		Properties: map[string]*Schema{
+			"name":  {Type: TypeString},
+			"extra": {Type: TypeObject, Properties: map[string]*Schema{"name": {Type: TypeString}}},
+		},
+	}
+
+	var node yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("extra: &base\n  name: jane\n  nam: typo\n<<: *base\n"), &node))
+
+	violations := Validate(schema, &node)
+	require.Len(t, violations, 2, "the typo must be reported both in the anchor and where it is merged")
+	for _, violation := range violations {
+		assert.Contains(t, violation.Message, "did you mean")
+	}
+}
@@ -0,0 +1,44 @@
+package lint
+
+import (
+	"context"
+	"strings"
+
+	"github.com/datablast-analytics/blast-cli/pkg/cache"
+	"github.com/datablast-analytics/blast-cli/pkg/query"
+	"go.uber.org/zap"
+)
+
+type validationCache interface {
+	Has(key string) bool
+	Set(key string) error
+}
+
+// CachedValidator remembers the queries that were found to be valid, and skips validating them again until the cache
+// entry expires. Invalid queries are never cached so that they are reported on every run.
+type CachedValidator struct {
+	Validator     queryValidator
+	Cache         validationCache
+	ValidatorType string
+	Identity      string
+	Logger        *zap.SugaredLogger
+}
+
+func (c *CachedValidator) IsValid(ctx context.Context, q *query.Query) (bool, error) {
+	key := cache.Key(c.ValidatorType, c.Identity, strings.Join(q.VariableDefinitions, ";\n"), q.Query)
+	if c.Cache.Has(key) {
+		c.Logger.Debugw("Query validation result found in the cache", "validator", c.ValidatorType)
+		return true, nil
+	}
+
+	valid, err := c.Validator.IsValid(ctx, q)
+	if err != nil || !valid {
+		return valid, err
+	}
+
+	if err := c.Cache.Set(key); err != nil {
+		c.Logger.Debugf("failed to store the query validation result in the cache: %v", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,111 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/query"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockValidationCache struct {
+	mock.Mock
+}
+
+func (m *mockValidationCache) Has(key string) bool {
+	return m.Called(key).Bool(0)
+}
+
+func (m *mockValidationCache) Set(key string) error {
+	return m.Called(key).Error(0)
+}
+
+func TestCachedValidator_IsValid(t *testing.T) {
+	t.Parallel()
+
+	q := &query.Query{Query: "select 1"}
+
+	tests := []struct {
+		name           string
+		setupCache     func(m *mockValidationCache)
+		setupValidator func(m *mockValidator)
+		want           bool
+		wantErr        bool
+	}{
+		{
+			name: "cached queries are not validated again",
+			setupCache: func(m *mockValidationCache) {
+				m.On("Has", mock.Anything).Return(true)
+			},
+			setupValidator: func(m *mockValidator) {},
+			want:           true,
+		},
+		{
+			name: "valid queries are stored in the cache",
+			setupCache: func(m *mockValidationCache) {
+				m.On("Has", mock.Anything).Return(false)
+				m.On("Set", mock.Anything).Return(nil)
+			},
+			setupValidator: func(m *mockValidator) {
+				m.On("IsValid", mock.Anything, q).Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name: "cache write failures do not fail the validation",
+			setupCache: func(m *mockValidationCache) {
+				m.On("Has", mock.Anything).Return(false)
+				m.On("Set", mock.Anything).Return(errors.New("disk is full"))
+			},
+			setupValidator: func(m *mockValidator) {
+				m.On("IsValid", mock.Anything, q).Return(true, nil)
+			},
+			want: true,
+		},
+		{
+			name: "invalid queries are not cached",
+			setupCache: func(m *mockValidationCache) {
+				m.On("Has", mock.Anything).Return(false)
+			},
+			setupValidator: func(m *mockValidator) {
+				m.On("IsValid", mock.Anything, q).Return(false, errors.New("invalid query"))
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := new(mockValidationCache)
+			tt.setupCache(c)
+
+			v := new(mockValidator)
+			tt.setupValidator(v)
+
+			validator := &CachedValidator{
+				Validator:     v,
+				Cache:         c,
+				ValidatorType: "bigquery-validator",
+				Identity:      "bigquery:project:US",
+				Logger:        zap.NewNop().Sugar(),
+			}
+
+			got, err := validator.IsValid(context.Background(), q)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.want, got)
+			c.AssertExpectations(t)
+			v.AssertExpectations(t)
+		})
+	}
+}
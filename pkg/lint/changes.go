@@ -0,0 +1,77 @@
+package lint
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+)
+
+// taskScopedRule is implemented by the rules that check every task on its own, which means they can be executed only
+// for a subset of the tasks in a pipeline. The rest of the rules always receive the whole pipeline.
+type taskScopedRule interface {
+	IsTaskScoped() bool
+}
+
+func isTaskScoped(rule Rule) bool {
+	scoped, ok := rule.(taskScopedRule)
+	return ok && scoped.IsTaskScoped()
+}
+
+// selectChangedTasks returns the tasks whose files were changed, together with all their downstream tasks. The
+// second return value reports whether the pipeline is affected by the changes at all.
+func selectChangedTasks(p *pipeline.Pipeline, changedFiles map[string]bool) ([]*pipeline.Task, bool) {
+	if changedFiles[resolvePath(p.DefinitionFile.Path)] {
+		return p.Tasks, true
+	}
+
+	pipelineRoot := filepath.Dir(resolvePath(p.DefinitionFile.Path)) + string(filepath.Separator)
+	affected := false
+	for file := range changedFiles {
+		if strings.HasPrefix(file, pipelineRoot) {
+			affected = true
+			break
+		}
+	}
+
+	if !affected {
+		return nil, false
+	}
+
+	selected := make(map[*pipeline.Task]bool)
+	for _, task := range p.Tasks {
+		if !changedFiles[resolvePath(task.DefinitionFile.Path)] && !changedFiles[resolvePath(task.ExecutableFile.Path)] {
+			continue
+		}
+
+		selected[task] = true
+		for _, downstream := range p.GetDownstreamTasks(task) {
+			selected[downstream] = true
+		}
+	}
+
+	tasks := make([]*pipeline.Task, 0, len(selected))
+	for _, task := range p.Tasks {
+		if selected[task] {
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, true
+}
+
+// resolvePath resolves the symlinks in the given path so that the pipeline paths can be compared with the paths
+// reported by git, which are always resolved. The path is returned as is if it cannot be resolved, e.g. when the file
+// was deleted.
+func resolvePath(path string) string {
+	if path == "" {
+		return path
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return path
+	}
+
+	return resolved
+}
@@ -0,0 +1,164 @@
+package lint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSelectChangedTasks(t *testing.T) {
+	t.Parallel()
+
+	upstream := &pipeline.Task{
+		Name:           "upstream",
+		DefinitionFile: pipeline.DefinitionFile{Path: "/pipelines/p1/tasks/upstream/task.yml"},
+		ExecutableFile: pipeline.ExecutableFile{Path: "/pipelines/p1/tasks/upstream/run.sh"},
+	}
+	changed := &pipeline.Task{
+		Name:           "changed",
+		DependsOn:      []string{"upstream"},
+		DefinitionFile: pipeline.DefinitionFile{Path: "/pipelines/p1/tasks/changed.sql"},
+		ExecutableFile: pipeline.ExecutableFile{Path: "/pipelines/p1/tasks/changed.sql"},
+	}
+	downstream := &pipeline.Task{
+		Name:           "downstream",
+		DependsOn:      []string{"changed"},
+		DefinitionFile: pipeline.DefinitionFile{Path: "/pipelines/p1/tasks/downstream.sql"},
+	}
+	p := &pipeline.Pipeline{
+		DefinitionFile: pipeline.DefinitionFile{Path: "/pipelines/p1/pipeline.yml"},
+		Tasks:          []*pipeline.Task{downstream, upstream, changed},
+	}
+
+	tests := []struct {
+		name         string
+		changedFiles []string
+		want         []*pipeline.Task
+		wantAffected bool
+	}{
+		{
+			name:         "changes in other pipelines are ignored",
+			changedFiles: []string{"/pipelines/p10/tasks/changed.sql", "/pipelines/p2/pipeline.yml"},
+		},
+		{
+			name:         "pipeline definition changes select all the tasks",
+			changedFiles: []string{"/pipelines/p1/pipeline.yml"},
+			want:         []*pipeline.Task{downstream, upstream, changed},
+			wantAffected: true,
+		},
+		{
+			name:         "changed tasks are selected with their downstream",
+			changedFiles: []string{"/pipelines/p1/tasks/changed.sql"},
+			want:         []*pipeline.Task{downstream, changed},
+			wantAffected: true,
+		},
+		{
+			name:         "run files of the yaml tasks are matched",
+			changedFiles: []string{"/pipelines/p1/tasks/upstream/run.sh"},
+			want:         []*pipeline.Task{downstream, upstream, changed},
+			wantAffected: true,
+		},
+		{
+			name:         "unrelated files in the pipeline mark it as affected without any tasks",
+			changedFiles: []string{"/pipelines/p1/tasks/deleted-task.sql"},
+			want:         []*pipeline.Task{},
+			wantAffected: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			changedFiles := make(map[string]bool)
+			for _, file := range tt.changedFiles {
+				changedFiles[file] = true
+			}
+
+			got, affected := selectChangedTasks(p, changedFiles)
+			require.Equal(t, tt.wantAffected, affected)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLinter_LimitToChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	changedTask := &pipeline.Task{Name: "changed", DefinitionFile: pipeline.DefinitionFile{Path: "/pipelines/p1/tasks/changed.sql"}}
+	otherTask := &pipeline.Task{Name: "other", DefinitionFile: pipeline.DefinitionFile{Path: "/pipelines/p1/tasks/other.sql"}}
+	affectedPipeline := &pipeline.Pipeline{
+		Name:           "p1",
+		DefinitionFile: pipeline.DefinitionFile{Path: "/pipelines/p1/pipeline.yml"},
+		Tasks:          []*pipeline.Task{changedTask, otherTask},
+	}
+	unaffectedPipeline := &pipeline.Pipeline{
+		Name:           "p2",
+		DefinitionFile: pipeline.DefinitionFile{Path: "/pipelines/p2/pipeline.yml"},
+		Tasks:          []*pipeline.Task{{Name: "task"}},
+	}
+
+	var mu sync.Mutex
+	seenTasks := make(map[string][]string)
+	recordingRule := func(name string, taskScoped bool) *SimpleRule {
+		return &SimpleRule{
+			Identifier: name,
+			TaskScoped: taskScoped,
+			Validator: func(p *pipeline.Pipeline) ([]*Issue, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, task := range p.Tasks {
+					seenTasks[name] = append(seenTasks[name], task.Name)
+				}
+				return nil, nil
+			},
+		}
+	}
+
+	l := &Linter{
+		rules:  []Rule{recordingRule("task-rule", true), recordingRule("pipeline-rule", false)},
+		logger: zap.NewNop().Sugar(),
+	}
+	l.LimitToChangedFiles([]string{"/pipelines/p1/tasks/changed.sql"})
+
+	result, err := l.lint(context.Background(), []*pipeline.Pipeline{affectedPipeline, unaffectedPipeline})
+	require.NoError(t, err)
+	require.Len(t, result.Pipelines, 1)
+	require.Equal(t, affectedPipeline, result.Pipelines[0].Pipeline)
+	require.Equal(t, map[string][]string{
+		"task-rule":     {"changed"},
+		"pipeline-rule": {"changed", "other"},
+	}, seenTasks)
+}
+
+func TestSelectChangedTasks_SymlinkedPipeline(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+
+	realDir := filepath.Join(dir, "real")
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.MkdirAll(filepath.Join(realDir, "tasks"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(realDir, "pipeline.yml"), []byte("name: p1"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(realDir, "tasks", "changed.sql"), []byte("select 1"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(realDir, "tasks", "other.sql"), []byte("select 2"), 0o644))
+	require.NoError(t, os.Symlink(realDir, link))
+
+	changedTask := &pipeline.Task{Name: "changed", ExecutableFile: pipeline.ExecutableFile{Path: filepath.Join(link, "tasks", "changed.sql")}}
+	otherTask := &pipeline.Task{Name: "other", ExecutableFile: pipeline.ExecutableFile{Path: filepath.Join(link, "tasks", "other.sql")}}
+	p := &pipeline.Pipeline{
+		DefinitionFile: pipeline.DefinitionFile{Path: filepath.Join(link, "pipeline.yml")},
+		Tasks:          []*pipeline.Task{changedTask, otherTask},
+	}
+
+	got, affected := selectChangedTasks(p, map[string]bool{filepath.Join(realDir, "tasks", "changed.sql"): true})
+	require.True(t, affected)
+	require.Equal(t, []*pipeline.Task{changedTask}, got)
+}
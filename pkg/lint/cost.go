@@ -0,0 +1,87 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/datablast-analytics/blast-cli/pkg/cost"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"go.uber.org/zap"
+)
+
+type costEstimator interface {
+	EstimatePipeline(ctx context.Context, p *pipeline.Pipeline) *cost.PipelineEstimate
+}
+
+// QueryCostRule dry-runs the queries and reports the tasks and pipelines that would scan more bytes than their budget.
+// The estimation failures are not reported here since the query validators already report invalid queries.
+type QueryCostRule struct {
+	Identifier string
+	Estimator  costEstimator
+	Budgets    *cost.Budgets
+	Logger     *zap.SugaredLogger
+}
+
+func (q *QueryCostRule) Name() string {
+	return q.Identifier
+}
+
+func (q *QueryCostRule) Validate(ctx context.Context, p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+
+	estimate := q.Estimator.EstimatePipeline(ctx, p)
+	for _, taskEstimate := range estimate.Tasks {
+		if taskEstimate.Err != nil {
+			q.Logger.Debugf("skipping the cost check for task '%s': %v", taskEstimate.Task.Name, taskEstimate.Err)
+			continue
+		}
+
+		budget := q.Budgets.ForTask(p.Name, taskEstimate.Task.Name)
+		if budget == 0 || taskEstimate.BytesProcessed <= budget {
+			continue
+		}
+
+		issues = append(issues, &Issue{
+			Task: taskEstimate.Task,
+			Description: fmt.Sprintf(
+				"The task is estimated to scan %s (≈ $%.2f), which is above its budget of %s",
+				cost.FormatBytes(taskEstimate.BytesProcessed),
+				taskEstimate.Cost,
+				cost.FormatBytes(budget),
+			),
+		})
+	}
+
+	budget := q.Budgets.ForPipeline(p.Name)
+	if budget == 0 || estimate.BytesProcessed <= budget {
+		return issues, nil
+	}
+
+	tasks := make([]*cost.TaskEstimate, len(estimate.Tasks))
+	copy(tasks, estimate.Tasks)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].BytesProcessed > tasks[j].BytesProcessed
+	})
+
+	breakdown := make([]string, 0, len(tasks))
+	for _, taskEstimate := range tasks {
+		if taskEstimate.BytesProcessed == 0 {
+			continue
+		}
+
+		breakdown = append(breakdown, fmt.Sprintf("%s: %s", taskEstimate.Task.Name, cost.FormatBytes(taskEstimate.BytesProcessed)))
+	}
+
+	issues = append(issues, &Issue{
+		Description: fmt.Sprintf(
+			"The pipeline is estimated to scan %s (≈ $%.2f) in total, which is above its budget of %s",
+			cost.FormatBytes(estimate.BytesProcessed),
+			estimate.Cost,
+			cost.FormatBytes(budget),
+		),
+		Context: breakdown,
+	})
+
+	return issues, nil
+}
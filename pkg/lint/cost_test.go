@@ -0,0 +1,157 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/datablast-analytics/blast-cli/pkg/cost"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockCostEstimator struct {
+	mock.Mock
+}
+
+func (m *mockCostEstimator) EstimatePipeline(ctx context.Context, p *pipeline.Pipeline) *cost.PipelineEstimate {
+	res := m.Called(ctx, p)
+	return res.Get(0).(*cost.PipelineEstimate)
+}
+
+func TestQueryCostRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	const gib = 1 << 30
+
+	cheapTask := &pipeline.Task{Name: "cheap-task"}
+	expensiveTask := &pipeline.Task{Name: "expensive-task"}
+	brokenTask := &pipeline.Task{Name: "broken-task"}
+	p := &pipeline.Pipeline{
+		Name:  "some-pipeline",
+		Tasks: []*pipeline.Task{cheapTask, expensiveTask, brokenTask},
+	}
+
+	estimate := &cost.PipelineEstimate{
+		Pipeline: p,
+		Tasks: []*cost.TaskEstimate{
+			{Task: cheapTask, BytesProcessed: 1 * gib, Cost: 0.01},
+			{Task: expensiveTask, BytesProcessed: 20 * gib, Cost: 0.12},
+			{Task: brokenTask, Err: errors.New("invalid query")},
+		},
+		BytesProcessed: 21 * gib,
+		Cost:           0.13,
+	}
+
+	tests := []struct {
+		name    string
+		budgets config.CostBudgets
+		want    []*Issue
+	}{
+		{
+			name: "everything is within the budget",
+			budgets: config.CostBudgets{
+				DefaultTask:     "100GiB",
+				DefaultPipeline: "1TiB",
+			},
+			want: []*Issue{},
+		},
+		{
+			name: "default task budget is exceeded",
+			budgets: config.CostBudgets{
+				DefaultTask: "10GiB",
+			},
+			want: []*Issue{
+				{
+					Task:        expensiveTask,
+					Description: "The task is estimated to scan 20.00 GiB (≈ $0.12), which is above its budget of 10.00 GiB",
+				},
+			},
+		},
+		{
+			name: "task specific budget overrides the default",
+			budgets: config.CostBudgets{
+				DefaultTask: "10GiB",
+				Pipelines: map[string]config.PipelineCostBudget{
+					"some-pipeline": {
+						Tasks: map[string]string{
+							"expensive-task": "50GiB",
+							"cheap-task":     "500MiB",
+						},
+					},
+				},
+			},
+			want: []*Issue{
+				{
+					Task:        cheapTask,
+					Description: "The task is estimated to scan 1.00 GiB (≈ $0.01), which is above its budget of 500.00 MiB",
+				},
+			},
+		},
+		{
+			name: "empty and zero task budgets fall back to the default",
+			budgets: config.CostBudgets{
+				DefaultTask: "10GiB",
+				Pipelines: map[string]config.PipelineCostBudget{
+					"some-pipeline": {
+						Tasks: map[string]string{
+							"expensive-task": "0",
+							"cheap-task":     "",
+						},
+					},
+				},
+			},
+			want: []*Issue{
+				{
+					Task:        expensiveTask,
+					Description: "The task is estimated to scan 20.00 GiB (≈ $0.12), which is above its budget of 10.00 GiB",
+				},
+			},
+		},
+		{
+			name: "pipeline budget is exceeded",
+			budgets: config.CostBudgets{
+				Pipelines: map[string]config.PipelineCostBudget{
+					"some-pipeline": {
+						Total: "15GiB",
+					},
+				},
+			},
+			want: []*Issue{
+				{
+					Description: "The pipeline is estimated to scan 21.00 GiB (≈ $0.13) in total, which is above its budget of 15.00 GiB",
+					Context: []string{
+						"expensive-task: 20.00 GiB",
+						"cheap-task: 1.00 GiB",
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			budgets, err := cost.NewBudgets(tt.budgets)
+			require.NoError(t, err)
+
+			estimator := new(mockCostEstimator)
+			estimator.On("EstimatePipeline", mock.Anything, p).Return(estimate)
+
+			rule := &QueryCostRule{
+				Identifier: "cost",
+				Estimator:  estimator,
+				Budgets:    budgets,
+				Logger:     zap.NewNop().Sugar(),
+			}
+
+			got, err := rule.Validate(context.Background(), p)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
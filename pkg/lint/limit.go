@@ -0,0 +1,72 @@
+package lint
+
+import (
+	"context"
+
+	"github.com/datablast-analytics/blast-cli/pkg/query"
+)
+
+const defaultWarehouseConcurrency = 32
+
+// Semaphore limits the amount of requests sent to the warehouses at the same time. A single instance is shared
+// across all the rules so that the limit applies globally, regardless of how many rules or pipelines run in parallel.
+type Semaphore struct {
+	slots chan struct{}
+}
+
+func NewSemaphore(size int) *Semaphore {
+	if size < 1 {
+		size = defaultWarehouseConcurrency
+	}
+
+	return &Semaphore{
+		slots: make(chan struct{}, size),
+	}
+}
+
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Semaphore) Release() {
+	<-s.slots
+}
+
+// LimitedValidator acquires a slot from the shared semaphore before validating each query.
+type LimitedValidator struct {
+	Validator queryValidator
+	Semaphore *Semaphore
+}
+
+func (l *LimitedValidator) IsValid(ctx context.Context, q *query.Query) (bool, error) {
+	if err := l.Semaphore.Acquire(ctx); err != nil {
+		return false, err
+	}
+	defer l.Semaphore.Release()
+
+	return l.Validator.IsValid(ctx, q)
+}
+
+type dryRunner interface {
+	BytesProcessed(ctx context.Context, query *query.Query) (int64, error)
+}
+
+// LimitedDryRunner acquires a slot from the shared semaphore before dry-running each query.
+type LimitedDryRunner struct {
+	DryRunner dryRunner
+	Semaphore *Semaphore
+}
+
+func (l *LimitedDryRunner) BytesProcessed(ctx context.Context, q *query.Query) (int64, error) {
+	if err := l.Semaphore.Acquire(ctx); err != nil {
+		return 0, err
+	}
+	defer l.Semaphore.Release()
+
+	return l.DryRunner.BytesProcessed(ctx, q)
+}
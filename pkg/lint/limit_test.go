@@ -0,0 +1,77 @@
+package lint
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/query"
+	"github.com/stretchr/testify/require"
+)
+
+type slowValidator struct {
+	mu         sync.Mutex
+	running    int
+	maxRunning int
+}
+
+func (s *slowValidator) IsValid(ctx context.Context, q *query.Query) (bool, error) {
+	s.mu.Lock()
+	s.running++
+	if s.running > s.maxRunning {
+		s.maxRunning = s.running
+	}
+	s.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	s.mu.Lock()
+	s.running--
+	s.mu.Unlock()
+
+	return true, nil
+}
+
+func TestLimitedValidator_IsValid(t *testing.T) {
+	t.Parallel()
+
+	semaphore := NewSemaphore(2)
+	counter := &slowValidator{}
+
+	// both validators share the same semaphore, the limit must apply to their total
+	validators := []*LimitedValidator{
+		{Validator: counter, Semaphore: semaphore},
+		{Validator: counter, Semaphore: semaphore},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			valid, err := validators[i%2].IsValid(context.Background(), &query.Query{Query: "select 1"})
+			require.NoError(t, err)
+			require.True(t, valid)
+		}(i)
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, counter.maxRunning, 2)
+}
+
+func TestLimitedValidator_IsValidRespectsTheContext(t *testing.T) {
+	t.Parallel()
+
+	semaphore := NewSemaphore(1)
+	require.NoError(t, semaphore.Acquire(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	v := &LimitedValidator{Validator: &slowValidator{}, Semaphore: semaphore}
+	valid, err := v.IsValid(ctx, &query.Query{Query: "select 1"})
+	require.ErrorIs(t, err, context.Canceled)
+	require.False(t, valid)
+}
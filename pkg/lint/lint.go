@@ -1,8 +1,10 @@
 package lint
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"runtime"
 	"sort"
 	"strings"
 
@@ -14,41 +16,99 @@ import (
 type (
 	pipelineFinder    func(root, pipelineDefinitionFile string) ([]string, error)
 	PipelineValidator func(pipeline *pipeline.Pipeline) ([]*Issue, error)
+	ProjectValidator  func(pipelines []*pipeline.Pipeline) (map[*pipeline.Pipeline][]*Issue, error)
 )
 
 type pipelineBuilder interface {
 	CreatePipelineFromPath(pathToPipeline string) (*pipeline.Pipeline, error)
 }
 
+type IssueType string
+
+const (
+	// IssueInvalid is the default type, used for the issues that point to an actual problem in the pipeline.
+	IssueInvalid IssueType = ""
+
+	// IssueTimeout is used when a check could not be completed in time, which means its result is unknown.
+	IssueTimeout IssueType = "timeout"
+
+	// IssueUnvalidated is used when a check kept failing because of transient errors, e.g. rate limits or network
+	// failures, or when a plugin failed, which means its result is unknown.
+	IssueUnvalidated IssueType = "could not validate"
+)
+
 type Issue struct {
 	Task        *pipeline.Task
 	Description string
 	Context     []string
+	Type        IssueType
 }
 
 type Rule interface {
 	Name() string
-	Validate(pipeline *pipeline.Pipeline) ([]*Issue, error)
+	Validate(ctx context.Context, pipeline *pipeline.Pipeline) ([]*Issue, error)
 }
 
 type SimpleRule struct {
 	Identifier string
 	Validator  PipelineValidator
+	TaskScoped bool
 }
 
-func (g *SimpleRule) Validate(pipeline *pipeline.Pipeline) ([]*Issue, error) {
+func (g *SimpleRule) Validate(ctx context.Context, pipeline *pipeline.Pipeline) ([]*Issue, error) {
 	return g.Validator(pipeline)
 }
 
+// ProjectRule is implemented by the rules that need to see all the pipelines at once, e.g. to resolve the
+// dependencies between them. The linter calls ValidateProject once instead of calling Validate for every pipeline.
+type ProjectRule interface {
+	Rule
+	ValidateProject(ctx context.Context, pipelines []*pipeline.Pipeline) (map[*pipeline.Pipeline][]*Issue, error)
+}
+
+type SimpleProjectRule struct {
+	Identifier string
+	Validator  ProjectValidator
+}
+
+func (g *SimpleProjectRule) Name() string {
+	return g.Identifier
+}
+
+// Validate checks the given pipeline on its own, which means only the references within the pipeline are resolved.
+func (g *SimpleProjectRule) Validate(ctx context.Context, p *pipeline.Pipeline) ([]*Issue, error) {
+	issues, err := g.ValidateProject(ctx, []*pipeline.Pipeline{p})
+	if err != nil {
+		return nil, err
+	}
+
+	if issues[p] == nil {
+		return make([]*Issue, 0), nil
+	}
+
+	return issues[p], nil
+}
+
+func (g *SimpleProjectRule) ValidateProject(ctx context.Context, pipelines []*pipeline.Pipeline) (map[*pipeline.Pipeline][]*Issue, error) {
+	return g.Validator(pipelines)
+}
+
 func (g *SimpleRule) Name() string {
 	return g.Identifier
 }
 
+func (g *SimpleRule) IsTaskScoped() bool {
+	return g.TaskScoped
+}
+
 type Linter struct {
 	findPipelines pipelineFinder
 	builder       pipelineBuilder
 	rules         []Rule
 	logger        *zap.SugaredLogger
+	changedFiles  map[string]bool
+	taskFilter    *pipeline.TaskFilter
+	jobs          int
 }
 
 func NewLinter(findPipelines pipelineFinder, builder pipelineBuilder, rules []Rule, logger *zap.SugaredLogger) *Linter {
@@ -57,10 +117,33 @@ func NewLinter(findPipelines pipelineFinder, builder pipelineBuilder, rules []Ru
 		builder:       builder,
 		rules:         rules,
 		logger:        logger,
+		jobs:          runtime.NumCPU(),
+	}
+}
+
+// SetJobs sets the number of pipelines built and rules executed in parallel.
+func (l *Linter) SetJobs(jobs int) {
+	l.jobs = jobs
+}
+
+// LimitToChangedFiles makes the linter skip the pipelines that are not affected by the given files, and run the
+// task-scoped rules only for the changed tasks and their downstream tasks.
+func (l *Linter) LimitToChangedFiles(files []string) {
+	l.changedFiles = make(map[string]bool, len(files))
+	for _, file := range files {
+		l.changedFiles[resolvePath(file)] = true
 	}
 }
 
-func (l *Linter) Lint(rootPath, pipelineDefinitionFileName string) (*PipelineAnalysisResult, error) {
+// FilterTasks makes the linter skip the pipelines without any matching tasks, and run the task-scoped rules only for
+// the matching tasks. It can be combined with LimitToChangedFiles, in which case the tasks must match both.
+func (l *Linter) FilterTasks(filter *pipeline.TaskFilter) {
+	l.taskFilter = filter
+}
+
+// Lint builds all the pipelines under the given path and runs the rules on them. If the context is done while the
+// rules are running, the results collected so far are returned and the result is marked as interrupted.
+func (l *Linter) Lint(ctx context.Context, rootPath, pipelineDefinitionFileName string) (*PipelineAnalysisResult, error) {
 	pipelinePaths, err := l.findPipelines(rootPath, pipelineDefinitionFileName)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
@@ -83,24 +166,34 @@ func (l *Linter) Lint(rootPath, pipelineDefinitionFileName string) (*PipelineAna
 	}
 
 	l.logger.Debug("no nested pipelines found, moving forward")
-	pipelines := make([]*pipeline.Pipeline, 0, len(pipelinePaths))
-	for _, pipelinePath := range pipelinePaths {
-		l.logger.Debugf("creating pipeline from path '%s'", pipelinePath)
+	pool := newWorkerPool(l.jobs)
+	pipelines := make([]*pipeline.Pipeline, len(pipelinePaths))
+	err = pool.run(ctx, len(pipelinePaths), func(i int) error {
+		l.logger.Debugf("creating pipeline from path '%s'", pipelinePaths[i])
 
-		p, err := l.builder.CreatePipelineFromPath(pipelinePath)
+		p, err := l.builder.CreatePipelineFromPath(pipelinePaths[i])
 		if err != nil {
-			return nil, errors.Wrapf(err, "error creating pipeline from path '%s'", pipelinePath)
+			return errors.Wrapf(err, "error creating pipeline from path '%s'", pipelinePaths[i])
 		}
-		pipelines = append(pipelines, p)
+
+		pipelines[i] = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	l.logger.Debugf("constructed %d pipelines", len(pipelines))
 
-	return l.lint(pipelines)
+	return l.lintWithPool(ctx, pool, pipelines)
 }
 
 type PipelineAnalysisResult struct {
 	Pipelines []*PipelineIssues
+
+	// Interrupted is true if the linting was stopped before all the rules were executed, e.g. due to a SIGINT or a
+	// timeout, which means the results are partial.
+	Interrupted bool
 }
 
 // HasErrors returns true if any of the pipelines has errors.
@@ -119,29 +212,134 @@ type PipelineIssues struct {
 	Issues   map[Rule][]*Issue
 }
 
-func (l *Linter) lint(pipelines []*pipeline.Pipeline) (*PipelineAnalysisResult, error) {
+func (l *Linter) lint(ctx context.Context, pipelines []*pipeline.Pipeline) (*PipelineAnalysisResult, error) {
+	return l.lintWithPool(ctx, newWorkerPool(l.jobs), pipelines)
+}
+
+type ruleJob struct {
+	pipelineIndex int
+	rule          Rule
+	target        *pipeline.Pipeline
+	issues        []*Issue
+
+	// projectIssues is set instead of issues for the project rules, which are executed once for all the pipelines.
+	projectIssues map[*pipeline.Pipeline][]*Issue
+}
+
+// lintWithPool runs every rule for every pipeline as a separate job in the pool, and then assembles the results in
+// the order of the pipelines and the rules, regardless of the order the jobs finished.
+func (l *Linter) lintWithPool(ctx context.Context, pool *workerPool, pipelines []*pipeline.Pipeline) (*PipelineAnalysisResult, error) {
 	result := &PipelineAnalysisResult{}
 
+	jobs := make([]*ruleJob, 0, len(pipelines)*len(l.rules))
 	for _, p := range pipelines {
-		pipelineResult := &PipelineIssues{
+		changedPipeline := p
+		if l.changedFiles != nil {
+			tasks, affected := selectChangedTasks(p, l.changedFiles)
+			if !affected {
+				l.logger.Debugf("skipping pipeline '%s', it is not affected by the changes", p.Name)
+				continue
+			}
+
+			l.logger.Debugf("found %d affected tasks in pipeline '%s'", len(tasks), p.Name)
+			subset := *p
+			subset.Tasks = tasks
+			changedPipeline = &subset
+		}
+
+		if !l.taskFilter.IsEmpty() {
+			filtered, matched := l.taskFilter.Apply(changedPipeline)
+			if !matched {
+				l.logger.Debugf("skipping pipeline '%s', none of its tasks match the filters", p.Name)
+				continue
+			}
+
+			changedPipeline = filtered
+		}
+
+		result.Pipelines = append(result.Pipelines, &PipelineIssues{
 			Pipeline: p,
 			Issues:   make(map[Rule][]*Issue),
-		}
+		})
 
 		for _, rule := range l.rules {
-			l.logger.Debugf("checking rule '%s' for pipeline '%s'", rule.Name(), p.Name)
+			if _, ok := rule.(ProjectRule); ok {
+				continue
+			}
 
-			issues, err := rule.Validate(p)
-			if err != nil {
-				return nil, err
+			target := p
+			if isTaskScoped(rule) {
+				target = changedPipeline
 			}
 
-			if len(issues) > 0 {
-				pipelineResult.Issues[rule] = issues
+			jobs = append(jobs, &ruleJob{
+				pipelineIndex: len(result.Pipelines) - 1,
+				rule:          rule,
+				target:        target,
+			})
+		}
+	}
+
+	// the project rules always see all the pipelines, even the ones that are not affected by the changes, since a
+	// change in one pipeline might break the references from the others
+	for _, rule := range l.rules {
+		if _, ok := rule.(ProjectRule); ok {
+			jobs = append(jobs, &ruleJob{pipelineIndex: -1, rule: rule})
+		}
+	}
+
+	ruleCtx := withWorkerPool(ctx, pool)
+	err := pool.run(ctx, len(jobs), func(i int) error {
+		job := jobs[i]
+		if projectRule, ok := job.rule.(ProjectRule); ok {
+			l.logger.Debugf("checking rule '%s' for %d pipelines", job.rule.Name(), len(pipelines))
+
+			issues, err := projectRule.ValidateProject(ruleCtx, pipelines)
+			if err != nil {
+				return err
 			}
+
+			job.projectIssues = issues
+			return nil
+		}
+
+		l.logger.Debugf("checking rule '%s' for pipeline '%s'", job.rule.Name(), job.target.Name)
+
+		issues, err := job.rule.Validate(ruleCtx, job.target)
+		if err != nil {
+			return err
+		}
+
+		job.issues = issues
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			return nil, err
 		}
 
-		result.Pipelines = append(result.Pipelines, pipelineResult)
+		l.logger.Debugf("linting is interrupted: %v", err)
+		result.Interrupted = true
+	}
+
+	resultIndex := make(map[*pipeline.Pipeline]int, len(result.Pipelines))
+	for i, pipelineIssues := range result.Pipelines {
+		resultIndex[pipelineIssues.Pipeline] = i
+	}
+
+	for _, job := range jobs {
+		if len(job.issues) > 0 {
+			result.Pipelines[job.pipelineIndex].Issues[job.rule] = job.issues
+		}
+
+		for p, issues := range job.projectIssues {
+			index, ok := resultIndex[p]
+			if !ok || len(issues) == 0 {
+				continue
+			}
+
+			result.Pipelines[index].Issues[job.rule] = issues
+		}
 	}
 
 	return result, nil
@@ -1,8 +1,11 @@
 package lint
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
@@ -197,7 +200,7 @@ func TestLinter_Lint(t *testing.T) {
 				logger:        logger.Sugar(),
 			}
 
-			_, err := l.Lint(tt.args.rootPath, tt.args.pipelineDefinitionFileName)
+			_, err := l.Lint(context.Background(), tt.args.rootPath, tt.args.pipelineDefinitionFileName)
 			if tt.wantErr {
 				require.Error(t, err)
 			} else {
@@ -208,3 +211,172 @@ func TestLinter_Lint(t *testing.T) {
 		})
 	}
 }
+
+func TestLinter_LintKeepsTheResultsInOrder(t *testing.T) {
+	t.Parallel()
+
+	pipelinePaths := make([]string, 0, 50)
+	m := new(mockPipelineBuilder)
+	for i := 0; i < 50; i++ {
+		path := fmt.Sprintf("path/to/pipeline%03d", i)
+		pipelinePaths = append(pipelinePaths, path)
+		m.On("CreatePipelineFromPath", path).Return(&pipeline.Pipeline{Name: path}, nil)
+	}
+
+	rules := make([]Rule, 0, 5)
+	for i := 0; i < 5; i++ {
+		rules = append(rules, &SimpleRule{
+			Identifier: fmt.Sprintf("rule%d", i),
+			Validator: func(p *pipeline.Pipeline) ([]*Issue, error) {
+				return []*Issue{{Description: p.Name}}, nil
+			},
+		})
+	}
+
+	l := NewLinter(
+		func(root, fileName string) ([]string, error) { return pipelinePaths, nil },
+		m,
+		rules,
+		zap.NewNop().Sugar(),
+	)
+	l.SetJobs(8)
+
+	result, err := l.Lint(context.Background(), "some-root-path", "some-file-name")
+	require.NoError(t, err)
+	require.Len(t, result.Pipelines, len(pipelinePaths))
+
+	for i, pipelineIssues := range result.Pipelines {
+		require.Equal(t, pipelinePaths[i], pipelineIssues.Pipeline.Name)
+		require.Len(t, pipelineIssues.Issues, len(rules))
+		for _, rule := range rules {
+			require.Equal(t, []*Issue{{Description: pipelinePaths[i]}}, pipelineIssues.Issues[rule])
+		}
+	}
+}
+
+func TestLinter_LintRunsProjectRulesOnceForAllPipelines(t *testing.T) {
+	t.Parallel()
+
+	first := &pipeline.Pipeline{Name: "first"}
+	second := &pipeline.Pipeline{Name: "second"}
+	third := &pipeline.Pipeline{Name: "third"}
+
+	m := new(mockPipelineBuilder)
+	m.On("CreatePipelineFromPath", "path/to/first").Return(first, nil)
+	m.On("CreatePipelineFromPath", "path/to/second").Return(second, nil)
+	m.On("CreatePipelineFromPath", "path/to/third").Return(third, nil)
+
+	calls := 0
+	projectRule := &SimpleProjectRule{
+		Identifier: "project-rule",
+		Validator: func(pipelines []*pipeline.Pipeline) (map[*pipeline.Pipeline][]*Issue, error) {
+			calls++
+			require.Equal(t, []*pipeline.Pipeline{first, second, third}, pipelines)
+
+			return map[*pipeline.Pipeline][]*Issue{
+				second: {{Description: "issue in the second pipeline"}},
+			}, nil
+		},
+	}
+
+	l := NewLinter(
+		func(root, fileName string) ([]string, error) {
+			return []string{"path/to/first", "path/to/second", "path/to/third"}, nil
+		},
+		m,
+		[]Rule{projectRule},
+		zap.NewNop().Sugar(),
+	)
+
+	result, err := l.Lint(context.Background(), "some-root-path", "some-file-name")
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+	require.Equal(t, []*PipelineIssues{
+		{Pipeline: first, Issues: map[Rule][]*Issue{}},
+		{Pipeline: second, Issues: map[Rule][]*Issue{projectRule: {{Description: "issue in the second pipeline"}}}},
+		{Pipeline: third, Issues: map[Rule][]*Issue{}},
+	}, result.Pipelines)
+}
+
+func TestLinter_LintReturnsPartialResultsWhenInterrupted(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := new(mockPipelineBuilder)
+	m.On("CreatePipelineFromPath", "path/to/pipeline1").Return(&pipeline.Pipeline{Name: "pipeline1"}, nil)
+	m.On("CreatePipelineFromPath", "path/to/pipeline2").Return(&pipeline.Pipeline{Name: "pipeline2"}, nil)
+
+	interruptingRule := &SimpleRule{
+		Identifier: "interruptingRule",
+		Validator: func(p *pipeline.Pipeline) ([]*Issue, error) {
+			cancel()
+			return []*Issue{{Description: "found an issue before the interruption"}}, nil
+		},
+	}
+
+	l := &Linter{
+		findPipelines: func(root, fileName string) ([]string, error) {
+			return []string{"path/to/pipeline1", "path/to/pipeline2"}, nil
+		},
+		builder: m,
+		rules:   []Rule{interruptingRule},
+		logger:  zap.NewNop().Sugar(),
+		jobs:    1,
+	}
+
+	result, err := l.Lint(ctx, "some-root-path", "some-file-name")
+	require.NoError(t, err)
+	require.True(t, result.Interrupted)
+	require.Len(t, result.Pipelines, 2)
+	require.Len(t, result.Pipelines[0].Issues[interruptingRule], 1)
+	require.Empty(t, result.Pipelines[1].Issues)
+}
+
+func TestLinter_FilterTasks(t *testing.T) {
+	t.Parallel()
+
+	taggedTask := &pipeline.Task{Name: "tagged", Tags: []string{"finance"}}
+	otherTask := &pipeline.Task{Name: "other"}
+	matchingPipeline := &pipeline.Pipeline{
+		Name:  "p1",
+		Tasks: []*pipeline.Task{taggedTask, otherTask},
+	}
+	otherPipeline := &pipeline.Pipeline{
+		Name:  "p2",
+		Tasks: []*pipeline.Task{{Name: "task"}},
+	}
+
+	var mu sync.Mutex
+	seenTasks := make(map[string][]string)
+	recordingRule := func(name string, taskScoped bool) *SimpleRule {
+		return &SimpleRule{
+			Identifier: name,
+			TaskScoped: taskScoped,
+			Validator: func(p *pipeline.Pipeline) ([]*Issue, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, task := range p.Tasks {
+					seenTasks[name] = append(seenTasks[name], task.Name)
+				}
+				return nil, nil
+			},
+		}
+	}
+
+	l := &Linter{
+		rules:  []Rule{recordingRule("task-rule", true), recordingRule("pipeline-rule", false)},
+		logger: zap.NewNop().Sugar(),
+	}
+	l.FilterTasks(&pipeline.TaskFilter{Tags: []string{"finance"}})
+
+	result, err := l.lint(context.Background(), []*pipeline.Pipeline{matchingPipeline, otherPipeline})
+	require.NoError(t, err)
+	require.Len(t, result.Pipelines, 1)
+	require.Equal(t, matchingPipeline, result.Pipelines[0].Pipeline)
+	require.Equal(t, map[string][]string{
+		"task-rule":     {"tagged"},
+		"pipeline-rule": {"tagged", "other"},
+	}, seenTasks)
+}
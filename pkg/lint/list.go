@@ -1,11 +1,18 @@
 package lint
 
 import (
+	"path/filepath"
 	"time"
 
 	"github.com/datablast-analytics/blast-cli/pkg/bigquery"
+	"github.com/datablast-analytics/blast-cli/pkg/cache"
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/datablast-analytics/blast-cli/pkg/cost"
 	"github.com/datablast-analytics/blast-cli/pkg/query"
+	"github.com/datablast-analytics/blast-cli/pkg/sensor"
 	"github.com/datablast-analytics/blast-cli/pkg/snowflake"
+	"github.com/datablast-analytics/blast-cli/pkg/sqlparser"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"go.uber.org/zap"
@@ -28,17 +35,33 @@ var (
 		Fs:       fs,
 		Renderer: renderer,
 	}
+
+	// the style rules read the queries without rendering them, the template expressions are what they expect to see
+	// instead of the hard-coded values
+	sqlStyleSources = map[string]SQLSource{
+		taskTypeBigqueryQuery: {
+			Extractor: &query.WholeFileExtractor{Fs: fs, Renderer: query.Renderer{}},
+			Dialect:   sqlparser.BigQuery,
+		},
+		taskTypeSnowflakeQuery: {
+			Extractor: &query.FileQuerySplitterExtractor{Fs: fs, Renderer: query.Renderer{}},
+			Dialect:   sqlparser.Snowflake,
+		},
+	}
 )
 
-func GetRules(logger *zap.SugaredLogger) ([]Rule, error) {
+// GetRules returns the rules for the project config, the registry is the one loaded from the same config.
+func GetRules(logger *zap.SugaredLogger, cfg *config.Config, registry *tasktype.Registry) ([]Rule, error) {
 	rules := []Rule{
 		&SimpleRule{
 			Identifier: "task-name-valid",
 			Validator:  EnsureTaskNameIsValid,
+			TaskScoped: true,
 		},
 		&SimpleRule{
 			Identifier: "task-name-valid",
 			Validator:  EnsureTaskNameIsValid,
+			TaskScoped: true,
 		},
 		&SimpleRule{
 			Identifier: "task-name-unique",
@@ -50,7 +73,8 @@ func GetRules(logger *zap.SugaredLogger) ([]Rule, error) {
 		},
 		&SimpleRule{
 			Identifier: "valid-executable-file",
-			Validator:  EnsureExecutableFileIsValid(fs),
+			Validator:  EnsureExecutableFileIsValid(fs, registry),
+			TaskScoped: true,
 		},
 		&SimpleRule{
 			Identifier: "valid-pipeline-schedule",
@@ -62,20 +86,88 @@ func GetRules(logger *zap.SugaredLogger) ([]Rule, error) {
 		},
 		&SimpleRule{
 			Identifier: "valid-task-type",
-			Validator:  EnsureOnlyAcceptedTaskTypesAreThere,
+			Validator:  EnsureOnlyAcceptedTaskTypesAreThere(registry),
+			TaskScoped: true,
+		},
+		&SimpleRule{
+			Identifier: "valid-task-parameters",
+			Validator:  EnsureTaskParametersAreValid(registry),
+			TaskScoped: true,
+		},
+		&SimpleRule{
+			Identifier: "valid-bq-table-sensor",
+			Validator:  EnsureSensorParametersAreValid(sensor.TypeBigqueryTable),
+			TaskScoped: true,
+		},
+		&SimpleRule{
+			Identifier: "valid-bq-query-sensor",
+			Validator:  EnsureSensorParametersAreValid(sensor.TypeBigqueryQuery),
+			TaskScoped: true,
+		},
+		&SimpleRule{
+			Identifier: "valid-s3-key-sensor",
+			Validator:  EnsureSensorParametersAreValid(sensor.TypeS3Key),
+			TaskScoped: true,
+		},
+		&SimpleRule{
+			Identifier: "valid-gcs-prefix-sensor",
+			Validator:  EnsureSensorParametersAreValid(sensor.TypeGCSPrefix),
+			TaskScoped: true,
+		},
+		&SimpleRule{
+			Identifier: "valid-definition-files",
+			Validator:  EnsureDefinitionFilesAreValid,
+		},
+		&SimpleRule{
+			Identifier: "valid-definition-keys",
+			Validator:  EnsureDefinitionKeysAreValid,
+		},
+		&SimpleRule{
+			Identifier: "valid-execution-policy",
+			Validator:  EnsureExecutionPoliciesAreValid,
 		},
 		&SimpleRule{
 			Identifier: "acyclic-pipeline",
 			Validator:  EnsurePipelineHasNoCycles,
 		},
+		&SimpleProjectRule{
+			Identifier: "pipeline-name-unique",
+			Validator:  EnsurePipelineNamesAreUnique,
+		},
+		&SimpleProjectRule{
+			Identifier: "cross-pipeline-dependency-exists",
+			Validator:  EnsureCrossPipelineDependenciesExist,
+		},
+		&SimpleProjectRule{
+			Identifier: "acyclic-pipelines",
+			Validator:  EnsurePipelinesHaveNoCycles,
+		},
 	}
 
-	rules, err := appendSnowflakeValidatorIfExists(logger, rules)
+	rules, err := appendConfiguredRules(cfg, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	rules = appendSQLStyleRules(logger, cfg, rules)
+
+	rules, err = appendPluginRules(logger, cfg, registry, rules)
 	if err != nil {
 		return nil, err
 	}
 
-	rules, err = appendBigqueryValidatorIfExists(logger, rules)
+	warehouseLimiter := NewSemaphore(cfg.Validation.WarehouseConcurrency)
+	queryTimeout, err := cfg.QueryTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err = appendSnowflakeValidatorIfExists(logger, cfg, warehouseLimiter, queryTimeout, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err = appendBigqueryValidatorIfExists(logger, cfg, warehouseLimiter, queryTimeout, rules)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +177,123 @@ func GetRules(logger *zap.SugaredLogger) ([]Rule, error) {
 	return rules, nil
 }
 
-func appendSnowflakeValidatorIfExists(logger *zap.SugaredLogger, rules []Rule) ([]Rule, error) {
+// appendConfiguredRules adds the rules that are enabled in the project config, e.g. the allowed owners and tags.
+func appendConfiguredRules(cfg *config.Config, rules []Rule) ([]Rule, error) {
+	if cfg.Metadata.RequireOwner {
+		rules = append(rules, &SimpleRule{
+			Identifier: "task-owner-exists",
+			Validator:  EnsureTaskHasOwner,
+			TaskScoped: true,
+		})
+	}
+
+	ownerPattern, err := cfg.OwnerPattern()
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerPattern != nil {
+		rules = append(rules, &SimpleRule{
+			Identifier: "task-owner-valid",
+			Validator:  EnsureTaskOwnerMatches(ownerPattern),
+			TaskScoped: true,
+		})
+	}
+
+	if len(cfg.Run.Pools) > 0 {
+		rules = append(rules, &SimpleRule{
+			Identifier: "task-pool-exists",
+			Validator:  EnsureTaskPoolsExist(cfg.Run.Pools),
+			TaskScoped: true,
+		})
+	}
+
+	if len(cfg.Metadata.AllowedTags) > 0 {
+		rules = append(rules, &SimpleRule{
+			Identifier: "task-tags-allowed",
+			Validator:  EnsureTaskTagsAreAllowed(cfg.Metadata.AllowedTags),
+			TaskScoped: true,
+		})
+	}
+
+	return rules, nil
+}
+
+// appendSQLStyleRules adds the style rules that are enabled in the project config, each of them is a separate rule.
+func appendSQLStyleRules(logger *zap.SugaredLogger, cfg *config.Config, rules []Rule) []Rule {
+	style := cfg.Validation.SQLStyle
+	checks := []struct {
+		enabled    bool
+		identifier string
+		check      SQLCheck
+	}{
+		{enabled: style.NoSelectStar, identifier: "sql-no-select-star", check: EnsureNoSelectStar},
+		{enabled: style.QualifiedTableNames, identifier: "sql-qualified-table-names", check: EnsureTableNamesAreQualified},
+		{enabled: style.NoHardcodedDates, identifier: "sql-no-hardcoded-dates", check: EnsureNoHardcodedDates},
+		{enabled: style.NoCTASOrderBy, identifier: "sql-no-ctas-order-by", check: EnsureCTASOrderByHasLimit},
+		{enabled: style.NoUnboundedDeletes, identifier: "sql-no-unbounded-deletes", check: EnsureDeletesHaveWhere},
+	}
+
+	for _, c := range checks {
+		if !c.enabled {
+			continue
+		}
+
+		rules = append(rules, &SQLStyleRule{
+			Identifier: c.identifier,
+			Check:      c.check,
+			Sources:    sqlStyleSources,
+			Logger:     logger,
+		})
+	}
+
+	return rules
+}
+
+// appendPluginRules adds the external rules from the project config, their names must not clash with the other rules.
+func appendPluginRules(logger *zap.SugaredLogger, cfg *config.Config, registry *tasktype.Registry, rules []Rule) ([]Rule, error) {
+	names := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		names[rule.Name()] = true
+	}
+
+	dir := ""
+	if cfg.Path != "" {
+		dir = filepath.Dir(cfg.Path)
+	}
+
+	for _, plugin := range cfg.Validation.Plugins {
+		if plugin.Name == "" || plugin.Command == "" {
+			return nil, errors.New("the validation plugins must have a name and a command")
+		}
+
+		if names[plugin.Name] {
+			return nil, errors.Errorf("the name of the validation plugin '%s' is already used by another rule", plugin.Name)
+		}
+		names[plugin.Name] = true
+
+		timeout, err := cfg.PluginTimeout(plugin)
+		if err != nil {
+			return nil, err
+		}
+
+		logger.Debugf("adding the validation plugin '%s' with the command '%s'", plugin.Name, cfg.PluginCommand(plugin))
+		rules = append(rules, &PluginRule{
+			Identifier: plugin.Name,
+			Command:    cfg.PluginCommand(plugin),
+			Args:       plugin.Args,
+			Dir:        dir,
+			Timeout:    timeout,
+			TaskScoped: plugin.TaskScoped,
+			Logger:     logger,
+			TaskTypes:  registry,
+		})
+	}
+
+	return rules, nil
+}
+
+func appendSnowflakeValidatorIfExists(logger *zap.SugaredLogger, cfg *config.Config, limiter *Semaphore, queryTimeout time.Duration, rules []Rule) ([]Rule, error) {
 	sfConfig, err := snowflake.LoadConfigFromEnv()
 	if err != nil {
 		return rules, err
@@ -103,43 +311,125 @@ func appendSnowflakeValidatorIfExists(logger *zap.SugaredLogger, rules []Rule) (
 	}
 	logger.Debug("snowflake ping is successful, adding the validator to the list of rules")
 
+	retryingValidator := withRetries(logger, cfg, &LimitedValidator{Validator: sf, Semaphore: limiter}, snowflake.IsTransientError)
+	validator, err := withValidationCache(logger, cfg, retryingValidator, "snowflake-validator", sfConfig.Identity())
+	if err != nil {
+		return nil, err
+	}
+
 	snowflakeValidator := &QueryValidatorRule{
-		Identifier:  "snowflake-validator",
-		TaskType:    taskTypeSnowflakeQuery,
-		Validator:   sf,
-		Extractor:   &splitQueryExtractor,
-		WorkerCount: 32,
-		Logger:      logger,
+		Identifier: "snowflake-validator",
+		TaskType:   taskTypeSnowflakeQuery,
+		Validator:  validator,
+		Extractor:  &splitQueryExtractor,
+		Logger:     logger,
+
+		QueryTimeout: queryTimeout,
 	}
 
 	return append(rules, snowflakeValidator), nil
 }
 
-func appendBigqueryValidatorIfExists(logger *zap.SugaredLogger, rules []Rule) ([]Rule, error) {
-	config, err := bigquery.LoadConfigFromEnv()
+func appendBigqueryValidatorIfExists(logger *zap.SugaredLogger, cfg *config.Config, limiter *Semaphore, queryTimeout time.Duration, rules []Rule) ([]Rule, error) {
+	bqConfig, err := bigquery.LoadConfigFromEnv()
 	if err != nil {
 		return rules, errors.Wrap(err, "failed to load bigquery config from env")
 	}
 
-	if !config.IsValid() {
+	if !bqConfig.IsValid() {
 		logger.Debug("no bigquery credentials found in env variables, skipping bigquery validation")
 		return rules, nil
 	}
 
 	logger.Debug("bigquery config is valid, appending the rule")
-	bq, err := bigquery.NewDB(config)
+	bq, err := bigquery.NewDB(bqConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	retryingValidator := withRetries(logger, cfg, &LimitedValidator{Validator: bq, Semaphore: limiter}, bigquery.IsTransientError)
+	validator, err := withValidationCache(logger, cfg, retryingValidator, "bigquery-validator", bqConfig.Identity())
 	if err != nil {
 		return nil, err
 	}
 
 	bqValidator := &QueryValidatorRule{
-		Identifier:  "bigquery-validator",
+		Identifier: "bigquery-validator",
+		TaskType:   taskTypeBigqueryQuery,
+		Validator:  validator,
+		Extractor:  &wholeFileExtractor,
+		Logger:     logger,
+
+		QueryTimeout: queryTimeout,
+	}
+
+	rules = append(rules, bqValidator)
+
+	budgets, err := cost.NewBudgets(cfg.Cost.Budgets)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the cost budgets")
+	}
+
+	if budgets.IsEmpty() {
+		logger.Debug("no cost budgets defined in the project config, skipping the cost rule")
+		return rules, nil
+	}
+
+	costRule := &QueryCostRule{
+		Identifier: "bigquery-cost-budget",
+		Estimator:  NewBigqueryCostEstimator(&LimitedDryRunner{DryRunner: bq, Semaphore: limiter}, cfg),
+		Budgets:    budgets,
+		Logger:     logger,
+	}
+
+	return append(rules, costRule), nil
+}
+
+// NewBigqueryCostEstimator creates an estimator for the BigQuery tasks that renders the queries the same way as the
+// validator does.
+func NewBigqueryCostEstimator(bq dryRunner, cfg *config.Config) *cost.Estimator {
+	return &cost.Estimator{
 		TaskType:    taskTypeBigqueryQuery,
-		Validator:   bq,
 		Extractor:   &wholeFileExtractor,
-		WorkerCount: 32,
+		DryRunner:   bq,
+		PricePerTiB: cfg.Cost.PricePerTiB,
+	}
+}
+
+// withRetries wraps the limited validator, which means the warehouse slot is released while waiting for the next
+// attempt and the other queries can use it in the meantime.
+func withRetries(logger *zap.SugaredLogger, cfg *config.Config, validator queryValidator, isTransient func(err error) bool) queryValidator {
+	return &RetryingValidator{
+		Validator:   validator,
+		IsTransient: isTransient,
 		Logger:      logger,
+		MaxAttempts: cfg.Validation.MaxAttempts,
+	}
+}
+
+func withValidationCache(logger *zap.SugaredLogger, cfg *config.Config, validator queryValidator, validatorType, identity string) (queryValidator, error) {
+	if cfg.Cache.Disabled {
+		logger.Debugf("the validation cache is disabled for '%s'", validatorType)
+		return validator, nil
 	}
 
-	return append(rules, bqValidator), nil
+	dir, err := cfg.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	ttl, err := cfg.CacheTTL()
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("using the validation cache at '%s' with a TTL of %s for '%s'", dir, ttl, validatorType)
+
+	return &CachedValidator{
+		Validator:     validator,
+		Cache:         cache.NewFileCache(afero.NewOsFs(), filepath.Join(dir, "query-validation"), ttl),
+		ValidatorType: validatorType,
+		Identity:      identity,
+		Logger:        logger,
+	}, nil
 }
@@ -0,0 +1,158 @@
+package lint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/inspect"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/process"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// maxPluginErrorLines is the number of lines kept from the stderr of a failing plugin, the last ones usually explain
+// the failure.
+const maxPluginErrorLines = 20
+
+// PluginResponse is what the plugins write to stdout. The issues without a task are reported for the pipeline, the
+// others must name one of the tasks the plugin received.
+type PluginResponse struct {
+	Issues []PluginIssue `json:"issues"`
+}
+
+type PluginIssue struct {
+	Task        string   `json:"task"`
+	Description string   `json:"description"`
+	Context     []string `json:"context"`
+}
+
+// PluginRule runs an external executable for every pipeline: the pipeline is written to its stdin as the same JSON
+// document `blast inspect` prints, and the issues are read from its stdout. The plugins that fail, time out or
+// return an invalid response do not stop the linting, they are reported as issues of this rule instead.
+type PluginRule struct {
+	Identifier string
+	Command    string
+	Args       []string
+
+	// Dir is the working directory of the plugin, the current directory is used when it is empty.
+	Dir        string
+	Timeout    time.Duration
+	TaskScoped bool
+	Logger     *zap.SugaredLogger
+
+	// TaskTypes resolves the effective parameters of the tasks in the document the plugin receives.
+	TaskTypes *tasktype.Registry
+}
+
+func (r *PluginRule) Name() string {
+	return r.Identifier
+}
+
+func (r *PluginRule) IsTaskScoped() bool {
+	return r.TaskScoped
+}
+
+func (r *PluginRule) Validate(ctx context.Context, p *pipeline.Pipeline) ([]*Issue, error) {
+	input, err := json.Marshal(inspect.NewPipelineDocument(p, r.TaskTypes))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to serialize the pipeline '%s' for the plugin '%s'", p.Name, r.Identifier)
+	}
+
+	pluginCtx := ctx
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		pluginCtx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(r.Command, r.Args...) //nolint:gosec
+	cmd.Dir = r.Dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	r.Logger.Debugf("running the plugin '%s' for the pipeline '%s'", r.Identifier, p.Name)
+	start := time.Now()
+	err = process.Run(pluginCtx, cmd)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// the linting is interrupted, the linter reports the results collected so far
+		return nil, ctx.Err()
+	case err != nil && errors.Is(pluginCtx.Err(), context.DeadlineExceeded):
+		return []*Issue{{
+			Description: fmt.Sprintf("The plugin '%s' timed out after %s", r.Identifier, time.Since(start).Round(time.Millisecond)),
+			Type:        IssueTimeout,
+		}}, nil
+	case err != nil:
+		return []*Issue{{
+			Description: fmt.Sprintf("The plugin '%s' failed: %s", r.Identifier, err),
+			Context:     lastLines(stderr.String(), maxPluginErrorLines),
+			Type:        IssueUnvalidated,
+		}}, nil
+	}
+
+	issues, err := r.parseResponse(p, stdout.Bytes())
+	if err != nil {
+		return []*Issue{{
+			Description: fmt.Sprintf("The plugin '%s' returned an invalid response: %s", r.Identifier, err),
+			Type:        IssueUnvalidated,
+		}}, nil
+	}
+
+	return issues, nil
+}
+
+func (r *PluginRule) parseResponse(p *pipeline.Pipeline, output []byte) ([]*Issue, error) {
+	var response PluginResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, err
+	}
+
+	tasks := make(map[string]*pipeline.Task, len(p.Tasks))
+	for _, task := range p.Tasks {
+		tasks[task.Name] = task
+	}
+
+	issues := make([]*Issue, 0, len(response.Issues))
+	for i, pluginIssue := range response.Issues {
+		if strings.TrimSpace(pluginIssue.Description) == "" {
+			return nil, errors.Errorf("the issue at index %d does not have a description", i)
+		}
+
+		issue := &Issue{Description: pluginIssue.Description, Context: pluginIssue.Context}
+		if pluginIssue.Task != "" {
+			task, ok := tasks[pluginIssue.Task]
+			if !ok {
+				return nil, errors.Errorf("the issue at index %d is for the unknown task '%s'", i, pluginIssue.Task)
+			}
+
+			issue.Task = task
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+func lastLines(output string, count int) []string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil
+	}
+
+	lines := strings.Split(output, "\n")
+	if len(lines) > count {
+		lines = lines[len(lines)-count:]
+	}
+
+	return lines
+}
@@ -0,0 +1,162 @@
+package lint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPluginRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	orders := &pipeline.Task{Name: "orders", Type: "bq.sql"}
+	p := &pipeline.Pipeline{
+		Name:           "sales",
+		DefinitionFile: pipeline.DefinitionFile{Path: "/repo/sales/pipeline.yml"},
+		Tasks:          []*pipeline.Task{orders},
+	}
+
+	tests := []struct {
+		name   string
+		script string
+		want   []*Issue
+	}{
+		{
+			name: "the pipeline is sent on stdin and the issues are read from stdout",
+			script: `input=$(cat)
+case "$input" in
+  *'"name":"sales"'*'"name":"orders"'*) ;;
+  *) echo "unexpected input: $input" >&2; exit 1 ;;
+esac
+echo '{"issues": [{"task": "orders", "description": "SELECT * is not allowed", "context": ["line 3"]}, {"description": "the pipeline needs an owner"}]}'`,
+			want: []*Issue{
+				{Task: orders, Description: "SELECT * is not allowed", Context: []string{"line 3"}},
+				{Description: "the pipeline needs an owner"},
+			},
+		},
+		{
+			name:   "no issues",
+			script: `cat > /dev/null; echo '{"issues": []}'`,
+			want:   []*Issue{},
+		},
+		{
+			name:   "failing plugins are reported with their errors",
+			script: "cat > /dev/null\necho 'first line' >&2\necho 'cannot connect to the catalog' >&2\nexit 2",
+			want: []*Issue{{
+				Description: "The plugin 'naming' failed: exit status 2",
+				Context:     []string{"first line", "cannot connect to the catalog"},
+				Type:        IssueUnvalidated,
+			}},
+		},
+		{
+			name:   "invalid responses are reported",
+			script: `cat > /dev/null; echo 'all good'`,
+			want: []*Issue{{
+				Description: "The plugin 'naming' returned an invalid response: invalid character 'a' looking for beginning of value",
+				Type:        IssueUnvalidated,
+			}},
+		},
+		{
+			name:   "issues for unknown tasks are reported",
+			script: `cat > /dev/null; echo '{"issues": [{"task": "customers", "description": "bad name"}]}'`,
+			want: []*Issue{{
+				Description: "The plugin 'naming' returned an invalid response: the issue at index 0 is for the unknown task 'customers'",
+				Type:        IssueUnvalidated,
+			}},
+		},
+		{
+			name:   "issues without a description are reported",
+			script: `cat > /dev/null; echo '{"issues": [{"task": "orders"}]}'`,
+			want: []*Issue{{
+				Description: "The plugin 'naming' returned an invalid response: the issue at index 0 does not have a description",
+				Type:        IssueUnvalidated,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule := &PluginRule{
+				Identifier: "naming",
+				Command:    writePlugin(t, tt.script),
+				Timeout:    10 * time.Second,
+				Logger:     zap.NewNop().Sugar(),
+				TaskTypes:  tasktype.Builtin(),
+			}
+
+			got, err := rule.Validate(context.Background(), p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPluginRule_ValidateTimesOut(t *testing.T) {
+	t.Parallel()
+
+	rule := &PluginRule{
+		Identifier: "slow",
+		Command:    writePlugin(t, "sleep 10\necho '{\"issues\": []}'"),
+		Timeout:    200 * time.Millisecond,
+		Logger:     zap.NewNop().Sugar(),
+	}
+
+	started := time.Now()
+	got, err := rule.Validate(context.Background(), &pipeline.Pipeline{Name: "sales"})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(started), 5*time.Second)
+	require.Len(t, got, 1)
+	assert.Equal(t, IssueTimeout, got[0].Type)
+	assert.Contains(t, got[0].Description, "The plugin 'slow' timed out after")
+}
+
+func TestPluginRule_ValidateIsInterrupted(t *testing.T) {
+	t.Parallel()
+
+	rule := &PluginRule{
+		Identifier: "slow",
+		Command:    writePlugin(t, "sleep 10"),
+		Logger:     zap.NewNop().Sugar(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err := rule.Validate(ctx, &pipeline.Pipeline{Name: "sales"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPluginRule_ValidateMissingCommand(t *testing.T) {
+	t.Parallel()
+
+	rule := &PluginRule{
+		Identifier: "missing",
+		Command:    filepath.Join(t.TempDir(), "missing-plugin"),
+		Logger:     zap.NewNop().Sugar(),
+	}
+
+	got, err := rule.Validate(context.Background(), &pipeline.Pipeline{Name: "sales"})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, IssueUnvalidated, got[0].Type)
+	assert.Contains(t, got[0].Description, "The plugin 'missing' failed:")
+}
+
+func writePlugin(t *testing.T, script string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "plugin.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/usr/bin/env bash\n"+script+"\n"), 0o700)) //nolint:gosec
+
+	return path
+}
@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// workerPool runs jobs with a bounded concurrency. The same pool is used for building the pipelines and for running
+// the rules, which means a single setting controls the amount of work done in parallel.
+type workerPool struct {
+	slots chan struct{}
+}
+
+func newWorkerPool(size int) *workerPool {
+	if size < 1 {
+		size = 1
+	}
+
+	return &workerPool{
+		slots: make(chan struct{}, size),
+	}
+}
+
+// run calls fn for every index in [0, count) and waits for all of them to finish. The first error in the order of the
+// indexes is returned, rather than the first one that happened, so that the results are deterministic. Once the
+// context is done no new jobs are started, and the skipped jobs get the context error.
+func (w *workerPool) run(ctx context.Context, count int, fn func(i int) error) error {
+	return w.runJobs(ctx, count, fn, false)
+}
+
+// runNested is the same as run for the jobs started by another job of the pool, e.g. the tasks checked by a rule. The
+// caller already holds a slot, so the jobs run on its goroutine when all the slots are taken rather than waiting for
+// one, which would deadlock once every slot is held by a caller.
+func (w *workerPool) runNested(ctx context.Context, count int, fn func(i int) error) error {
+	return w.runJobs(ctx, count, fn, true)
+}
+
+func (w *workerPool) runJobs(ctx context.Context, count int, fn func(i int) error, nested bool) error {
+	errs := make([]error, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		if nested {
+			select {
+			case w.slots <- struct{}{}:
+			default:
+				if errs[i] = ctx.Err(); errs[i] == nil {
+					errs[i] = fn(i)
+				}
+				continue
+			}
+		} else {
+			select {
+			case w.slots <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				continue
+			}
+		}
+
+		if ctx.Err() != nil {
+			<-w.slots
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-w.slots
+				wg.Done()
+			}()
+
+			errs[i] = fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type workerPoolKey struct{}
+
+// withWorkerPool passes the pool to the rules, which use it for the work they do in parallel.
+func withWorkerPool(ctx context.Context, pool *workerPool) context.Context {
+	return context.WithValue(ctx, workerPoolKey{}, pool)
+}
+
+// workerPoolFrom returns the pool the rule is executed in, or a new pool with a worker per CPU if the rule is not
+// executed by the linter.
+func workerPoolFrom(ctx context.Context) *workerPool {
+	if pool, ok := ctx.Value(workerPoolKey{}).(*workerPool); ok {
+		return pool
+	}
+
+	return newWorkerPool(runtime.NumCPU())
+}
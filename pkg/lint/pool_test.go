@@ -0,0 +1,123 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool_Run(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+
+	pool := newWorkerPool(3)
+	results := make([]int, 20)
+	err := pool.run(context.Background(), len(results), func(i int) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+		results[i] = i * 2
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.LessOrEqual(t, maxRunning, 3)
+	for i, result := range results {
+		require.Equal(t, i*2, result)
+	}
+}
+
+func TestWorkerPool_RunReturnsTheFirstErrorByIndex(t *testing.T) {
+	t.Parallel()
+
+	pool := newWorkerPool(0)
+	err := pool.run(context.Background(), 5, func(i int) error {
+		if i >= 2 {
+			return errors.New("failed at " + string(rune('0'+i)))
+		}
+
+		return nil
+	})
+
+	require.EqualError(t, err, "failed at 2")
+}
+
+func TestWorkerPool_RunStopsWhenTheContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var mu sync.Mutex
+	executed := 0
+
+	pool := newWorkerPool(1)
+	err := pool.run(ctx, 10, func(i int) error {
+		mu.Lock()
+		executed++
+		mu.Unlock()
+
+		if i == 2 {
+			cancel()
+		}
+
+		return nil
+	})
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.LessOrEqual(t, executed, 4)
+}
+
+func TestWorkerPool_RunNestedSharesTheSlots(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	track := func(delta int) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		running += delta
+		if running > maxRunning {
+			maxRunning = running
+		}
+	}
+
+	// every outer job holds a slot while its nested jobs run, which would deadlock if they waited for a free slot
+	pool := newWorkerPool(2)
+	results := make([][]int, 4)
+	err := pool.run(context.Background(), len(results), func(i int) error {
+		results[i] = make([]int, 5)
+		return pool.runNested(context.Background(), len(results[i]), func(j int) error {
+			track(1)
+			defer track(-1)
+
+			time.Sleep(time.Millisecond)
+			results[i][j] = i*10 + j
+			return nil
+		})
+	})
+
+	require.NoError(t, err)
+	require.LessOrEqual(t, maxRunning, 2)
+	for i := range results {
+		for j, result := range results[i] {
+			require.Equal(t, i*10+j, result)
+		}
+	}
+}
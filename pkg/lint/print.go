@@ -3,6 +3,7 @@ package lint
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
@@ -18,6 +19,12 @@ type taskSummary struct {
 	issues []*Issue
 }
 
+// ruleIssue keeps the rule that reported an issue, a task might have issues from multiple rules.
+type ruleIssue struct {
+	rule  Rule
+	issue *Issue
+}
+
 var (
 	faint           = color.New(color.Faint).SprintFunc()
 	successPrinter  = color.New(color.FgGreen)
@@ -45,9 +52,10 @@ func (l *Printer) printPipelineSummary(pipelineIssues *PipelineIssues) {
 	}
 
 	genericIssues := make([]*taskSummary, 0, len(pipelineIssues.Issues))
-	taskIssueMap := make(map[*pipeline.Task]*taskSummary)
+	taskIssueMap := make(map[*pipeline.Task][]*ruleIssue)
 
-	for rule, issues := range pipelineIssues.Issues {
+	for _, rule := range sortedRules(pipelineIssues.Issues) {
+		issues := pipelineIssues.Issues[rule]
 		genericIssuesForRule := &taskSummary{
 			rule:   rule,
 			issues: []*Issue{},
@@ -61,21 +69,7 @@ func (l *Printer) printPipelineSummary(pipelineIssues *PipelineIssues) {
 				continue
 			}
 
-			// create the defaults if there are no issues for this task yet
-			if _, ok := taskIssueMap[issue.Task]; !ok {
-				taskIssueMap[issue.Task] = &taskSummary{
-					rule:   rule,
-					issues: []*Issue{},
-				}
-			}
-
-			taskIssues := taskIssueMap[issue.Task].issues
-			taskIssues = append(taskIssues, issue)
-
-			taskIssueMap[issue.Task] = &taskSummary{
-				rule:   rule,
-				issues: taskIssues,
-			}
+			taskIssueMap[issue.Task] = append(taskIssueMap[issue.Task], &ruleIssue{rule: rule, issue: issue})
 		}
 
 		if len(genericIssuesForRule.issues) > 0 {
@@ -84,18 +78,60 @@ func (l *Printer) printPipelineSummary(pipelineIssues *PipelineIssues) {
 	}
 
 	for _, taskSummary := range genericIssues {
-		printIssues(taskSummary.rule, taskSummary.issues)
+		ruleIssues := make([]*ruleIssue, 0, len(taskSummary.issues))
+		for _, issue := range taskSummary.issues {
+			ruleIssues = append(ruleIssues, &ruleIssue{rule: taskSummary.rule, issue: issue})
+		}
+		printIssues(ruleIssues)
 	}
 
-	for task, summary := range taskIssueMap {
+	for _, task := range tasksInPipelineOrder(pipelineIssues.Pipeline, taskIssueMap) {
 		relativeTaskPath := pipelineIssues.Pipeline.RelativeTaskPath(task)
 		taskNamePrinter.Printf("  %s %s\n", task.Name, faint(fmt.Sprintf("(%s)", relativeTaskPath)))
-		printIssues(summary.rule, summary.issues)
+		printIssues(taskIssueMap[task])
 
 		issuePrinter.Println()
 	}
 }
 
+// sortedRules returns the rules sorted by their names, which keeps the output the same across runs.
+func sortedRules(issues map[Rule][]*Issue) []Rule {
+	rules := make([]Rule, 0, len(issues))
+	for rule := range issues {
+		rules = append(rules, rule)
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Name() < rules[j].Name()
+	})
+
+	return rules
+}
+
+func tasksInPipelineOrder(p *pipeline.Pipeline, taskIssueMap map[*pipeline.Task][]*ruleIssue) []*pipeline.Task {
+	tasks := make([]*pipeline.Task, 0, len(taskIssueMap))
+	seen := make(map[*pipeline.Task]bool, len(taskIssueMap))
+	for _, task := range p.Tasks {
+		if _, ok := taskIssueMap[task]; ok {
+			tasks = append(tasks, task)
+			seen[task] = true
+		}
+	}
+
+	// the issues might refer to tasks that are not in the pipeline anymore, they are printed at the end
+	remaining := make([]*pipeline.Task, 0)
+	for task := range taskIssueMap {
+		if !seen[task] {
+			remaining = append(remaining, task)
+		}
+	}
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return remaining[i].Name < remaining[j].Name
+	})
+
+	return append(tasks, remaining...)
+}
+
 func (l Printer) relativePipelinePath(p *pipeline.Pipeline) string {
 	absolutePipelineRoot := filepath.Dir(p.DefinitionFile.Path)
 
@@ -112,15 +148,21 @@ func (l Printer) relativePipelinePath(p *pipeline.Pipeline) string {
 	return pipelineDirectory
 }
 
-func printIssues(rule Rule, issues []*Issue) {
+func printIssues(issues []*ruleIssue) {
 	issueCount := len(issues)
-	for index, issue := range issues {
+	for index, ruleIssue := range issues {
+		issue := ruleIssue.issue
 		connector := "├──"
 		if index == issueCount-1 {
 			connector = "└──"
 		}
 
-		issuePrinter.Printf("    %s %s %s\n", connector, issue.Description, faint(fmt.Sprintf("(%s)", rule.Name())))
+		source := ruleIssue.rule.Name()
+		if issue.Type != IssueInvalid {
+			source = fmt.Sprintf("%s, %s", source, issue.Type)
+		}
+
+		issuePrinter.Printf("    %s %s %s\n", connector, issue.Description, faint(fmt.Sprintf("(%s)", source)))
 		printIssueContext(issue.Context, index == issueCount-1)
 	}
 }
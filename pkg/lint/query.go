@@ -2,8 +2,8 @@ package lint
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sync"
 	"time"
 
 	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
@@ -20,135 +20,152 @@ type queryExtractor interface {
 }
 
 type QueryValidatorRule struct {
-	Identifier  string
-	TaskType    string
-	Validator   queryValidator
-	Extractor   queryExtractor
-	WorkerCount int
-	Logger      *zap.SugaredLogger
+	Identifier string
+	TaskType   string
+	Validator  queryValidator
+	Extractor  queryExtractor
+	Logger     *zap.SugaredLogger
+
+	// QueryTimeout is the maximum duration to wait for a single query to be validated, zero means no timeout.
+	QueryTimeout time.Duration
 }
 
 func (q QueryValidatorRule) Name() string {
 	return q.Identifier
 }
 
-func (q QueryValidatorRule) validateTask(task *pipeline.Task, done chan<- []*Issue) {
-	issues := make([]*Issue, 0)
+func (q QueryValidatorRule) IsTaskScoped() bool {
+	return true
+}
 
+func (q QueryValidatorRule) validateTask(ctx context.Context, task *pipeline.Task) []*Issue {
 	queries, err := q.Extractor.ExtractQueriesFromFile(task.ExecutableFile.Path)
 	if err != nil {
-		issues = append(issues, &Issue{
-			Task:        task,
-			Description: fmt.Sprintf("Cannot read executable file '%s': %+v", task.ExecutableFile.Path, err),
-		})
-
-		done <- issues
-		return
+		return []*Issue{
+			{
+				Task:        task,
+				Description: fmt.Sprintf("Cannot read executable file '%s': %+v", task.ExecutableFile.Path, err),
+			},
+		}
 	}
 
 	q.Logger.Debugf("Found %d queries in file '%s'", len(queries), task.ExecutableFile.Path)
 
 	if len(queries) == 0 {
-		issues = append(issues, &Issue{
-			Task:        task,
-			Description: fmt.Sprintf("No queries found in executable file '%s'", task.ExecutableFile.Path),
-		})
-
-		done <- issues
-		return
-	}
-
-	var mu sync.Mutex
-	var wg sync.WaitGroup
-
-	for index, foundQuery := range queries {
-		wg.Add(1)
-		go func(index int, foundQuery *query.Query) {
-			defer wg.Done()
-
-			q.Logger.Debugw("Checking if a query is valid", "path", task.ExecutableFile.Path)
-			start := time.Now()
-
-			valid, err := q.Validator.IsValid(context.Background(), foundQuery)
-			if err != nil {
-				mu.Lock()
-				issues = append(issues, &Issue{
-					Task:        task,
-					Description: fmt.Sprintf("Invalid query found at index %d: %s", index, err),
-					Context: []string{
-						"The failing query is as follows:",
-						foundQuery.Query,
-					},
-				})
-				mu.Unlock()
-			} else if !valid {
-				mu.Lock()
-				issues = append(issues, &Issue{
-					Task:        task,
-					Description: fmt.Sprintf("Query '%s' is invalid", foundQuery.Query),
-					Context: []string{
-						"The failing query is as follows:",
-						foundQuery.Query,
-					},
-				})
-				mu.Unlock()
-			}
-
-			duration := time.Since(start)
-			q.Logger.Debugw("Finished with query checking", "path", task.ExecutableFile.Path, "duration", duration)
-		}(index, foundQuery)
+		return []*Issue{
+			{
+				Task:        task,
+				Description: fmt.Sprintf("No queries found in executable file '%s'", task.ExecutableFile.Path),
+			},
+		}
 	}
 
-	wg.Wait()
-	done <- issues
-}
-
-func (q QueryValidatorRule) bufferSize() int {
-	return 256
-}
+	// every query writes only to its own index, which keeps the order of the issues the same as the queries
+	results := make([]*Issue, len(queries))
+	_ = workerPoolFrom(ctx).runNested(ctx, len(queries), func(index int) error {
+		results[index] = q.validateQuery(ctx, task, index, queries[index])
+		return nil
+	})
 
-func (q *QueryValidatorRule) Validate(p *pipeline.Pipeline) ([]*Issue, error) {
 	issues := make([]*Issue, 0)
-
-	// skip if there are no workers defined
-	if q.WorkerCount == 0 {
-		return issues, nil
+	for _, issue := range results {
+		if issue != nil {
+			issues = append(issues, issue)
+		}
 	}
 
-	q.Logger.Debugf("Starting validation with %d workers for task type '%s'", q.WorkerCount, q.TaskType)
+	return issues
+}
 
-	taskChannel := make(chan *pipeline.Task, q.bufferSize())
-	results := make(chan []*Issue, q.bufferSize())
+func (q QueryValidatorRule) validateQuery(ctx context.Context, task *pipeline.Task, index int, foundQuery *query.Query) *Issue {
+	q.Logger.Debugw("Checking if a query is valid", "path", task.ExecutableFile.Path)
+	start := time.Now()
+	defer func() {
+		q.Logger.Debugw("Finished with query checking", "path", task.ExecutableFile.Path, "duration", time.Since(start))
+	}()
+
+	queryCtx := ctx
+	if q.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		queryCtx, cancel = context.WithTimeout(ctx, q.QueryTimeout)
+		defer cancel()
+	}
 
-	// start the workers
-	for i := 0; i < q.WorkerCount; i++ {
-		go func(taskChannel <-chan *pipeline.Task, results chan<- []*Issue) {
-			for task := range taskChannel {
-				q.validateTask(task, results)
-			}
-		}(taskChannel, results)
+	valid, err := q.Validator.IsValid(queryCtx, foundQuery)
+	var unvalidatedErr *UnvalidatedError
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.Canceled):
+		// the validation is interrupted, there is no point in reporting the queries that were not completed
+		q.Logger.Debugw("Query validation is cancelled", "path", task.ExecutableFile.Path)
+		return nil
+	case err != nil && (errors.Is(err, context.DeadlineExceeded) || errors.Is(queryCtx.Err(), context.DeadlineExceeded)):
+		return &Issue{
+			Task:        task,
+			Description: fmt.Sprintf("Query validation timed out at index %d after %s", index, time.Since(start).Round(time.Millisecond)),
+			Context: []string{
+				"The query that timed out is as follows:",
+				foundQuery.Query,
+			},
+			Type: IssueTimeout,
+		}
+	case errors.As(err, &unvalidatedErr):
+		return &Issue{
+			Task:        task,
+			Description: fmt.Sprintf("Could not validate the query at index %d after %d attempts: %s", index, unvalidatedErr.Attempts, unvalidatedErr.Err),
+			Context: []string{
+				"The query that could not be validated is as follows:",
+				foundQuery.Query,
+			},
+			Type: IssueUnvalidated,
+		}
+	case err != nil:
+		return &Issue{
+			Task:        task,
+			Description: fmt.Sprintf("Invalid query found at index %d: %s", index, err),
+			Context: []string{
+				"The failing query is as follows:",
+				foundQuery.Query,
+			},
+		}
+	case !valid:
+		return &Issue{
+			Task:        task,
+			Description: fmt.Sprintf("Query '%s' is invalid", foundQuery.Query),
+			Context: []string{
+				"The failing query is as follows:",
+				foundQuery.Query,
+			},
+		}
 	}
 
-	processedTaskCount := 0
+	return nil
+}
+
+// Validate checks all the tasks of the matching type concurrently in the worker pool of the linter, which bounds the
+// file reads along with the rest of the work. The amount of requests sent to the warehouse is bounded by the validator
+// itself, see LimitedValidator.
+func (q *QueryValidatorRule) Validate(ctx context.Context, p *pipeline.Pipeline) ([]*Issue, error) {
+	tasks := make([]*pipeline.Task, 0)
 	for _, task := range p.Tasks {
 		if task.Type != q.TaskType {
-			q.Logger.Debug("Skipping task, task type not matched")
 			continue
 		}
-		q.Logger.Debug("Processing task type")
 
-		processedTaskCount++
-		taskChannel <- task
-		q.Logger.Debugf("Pushed a task to the taskChannel")
+		tasks = append(tasks, task)
 	}
-	q.Logger.Debugf("Processed %d tasks at path '%s', closing channel", processedTaskCount, p.DefinitionFile.Path)
-	close(taskChannel)
-	q.Logger.Debugf("Closed the channel")
-
-	for i := 0; i < processedTaskCount; i++ {
-		foundIssues := <-results
-		q.Logger.Debugf("Received issues: %d/%d", i+1, processedTaskCount)
-		issues = append(issues, foundIssues...)
+
+	q.Logger.Debugf("Validating %d tasks of type '%s' at path '%s'", len(tasks), q.TaskType, p.DefinitionFile.Path)
+
+	results := make([][]*Issue, len(tasks))
+	// the tasks skipped due to a cancellation are not reported, the linter marks the whole result as interrupted
+	_ = workerPoolFrom(ctx).runNested(ctx, len(tasks), func(i int) error {
+		results[i] = q.validateTask(ctx, tasks[i])
+		return nil
+	})
+
+	issues := make([]*Issue, 0)
+	for _, taskIssues := range results {
+		issues = append(issues, taskIssues...)
 	}
 
 	return issues, nil
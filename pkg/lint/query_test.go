@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
 	"github.com/datablast-analytics/blast-cli/pkg/query"
@@ -225,23 +226,115 @@ func TestQueryValidatorRule_Validate(t *testing.T) {
 			}
 
 			q := &QueryValidatorRule{
-				TaskType:    taskType,
-				Validator:   validator,
-				Extractor:   extractor,
-				Logger:      zap.NewNop().Sugar(),
-				WorkerCount: 1,
+				TaskType:  taskType,
+				Validator: validator,
+				Extractor: extractor,
+				Logger:    zap.NewNop().Sugar(),
 			}
 
-			got, err := q.Validate(tt.p)
+			got, err := q.Validate(context.Background(), tt.p)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
 
-			assert.ElementsMatch(t, tt.want, got)
+			assert.Equal(t, tt.want, got)
 			validator.AssertExpectations(t)
 			extractor.AssertExpectations(t)
 		})
 	}
 }
+
+func TestQueryValidatorRule_ValidateWithTimeouts(t *testing.T) {
+	t.Parallel()
+
+	task := &pipeline.Task{
+		Type: "someTaskType",
+		ExecutableFile: pipeline.ExecutableFile{
+			Path: "path/to/file.sql",
+		},
+	}
+	p := &pipeline.Pipeline{Tasks: []*pipeline.Task{task}}
+
+	waitForContext := func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}
+
+	extractor := new(mockExtractor)
+	extractor.On("ExtractQueriesFromFile", "path/to/file.sql").
+		Return([]*query.Query{{Query: "fast query"}, {Query: "slow query"}}, nil)
+
+	validator := new(mockValidator)
+	validator.On("IsValid", mock.Anything, &query.Query{Query: "fast query"}).Return(true, nil)
+	validator.On("IsValid", mock.Anything, &query.Query{Query: "slow query"}).
+		Run(waitForContext).
+		Return(false, context.DeadlineExceeded)
+
+	q := &QueryValidatorRule{
+		TaskType:     "someTaskType",
+		Validator:    validator,
+		Extractor:    extractor,
+		Logger:       zap.NewNop().Sugar(),
+		QueryTimeout: 10 * time.Millisecond,
+	}
+
+	got, err := q.Validate(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Len(t, got, 1)
+	assert.Equal(t, IssueTimeout, got[0].Type)
+	assert.Contains(t, got[0].Description, "Query validation timed out at index 1")
+
+	// cancelled validations are not reported at all, the linter marks the whole result as interrupted instead
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cancelledValidator := new(mockValidator)
+	cancelledValidator.On("IsValid", mock.Anything, mock.Anything).Return(false, context.Canceled)
+	q.Validator = cancelledValidator
+
+	got, err = q.Validate(ctx, p)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestQueryValidatorRule_ValidateReportsUnvalidatedQueries(t *testing.T) {
+	t.Parallel()
+
+	task := &pipeline.Task{
+		Type: "someTaskType",
+		ExecutableFile: pipeline.ExecutableFile{
+			Path: "path/to/file.sql",
+		},
+	}
+	p := &pipeline.Pipeline{Tasks: []*pipeline.Task{task}}
+
+	extractor := new(mockExtractor)
+	extractor.On("ExtractQueriesFromFile", "path/to/file.sql").
+		Return([]*query.Query{{Query: "select 1"}}, nil)
+
+	validator := new(mockValidator)
+	validator.On("IsValid", mock.Anything, &query.Query{Query: "select 1"}).
+		Return(false, &UnvalidatedError{Err: errors.New("rate limit exceeded"), Attempts: 4})
+
+	q := &QueryValidatorRule{
+		TaskType:  "someTaskType",
+		Validator: validator,
+		Extractor: extractor,
+		Logger:    zap.NewNop().Sugar(),
+	}
+
+	got, err := q.Validate(context.Background(), p)
+	assert.NoError(t, err)
+	assert.Equal(t, []*Issue{
+		{
+			Task:        task,
+			Description: "Could not validate the query at index 0 after 4 attempts: rate limit exceeded",
+			Context: []string{
+				"The query that could not be validated is as follows:",
+				"select 1",
+			},
+			Type: IssueUnvalidated,
+		},
+	}, got)
+}
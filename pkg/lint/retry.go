@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/query"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMaxAttempts = 4
+	defaultBaseDelay   = 500 * time.Millisecond
+	defaultMaxDelay    = 10 * time.Second
+)
+
+// UnvalidatedError is returned when a query could not be validated because the warehouse kept failing with transient
+// errors, which means the validity of the query is unknown.
+type UnvalidatedError struct {
+	Err      error
+	Attempts int
+}
+
+func (e *UnvalidatedError) Error() string {
+	return fmt.Sprintf("could not validate the query after %d attempts: %s", e.Attempts, e.Err)
+}
+
+func (e *UnvalidatedError) Unwrap() error {
+	return e.Err
+}
+
+// RetryingValidator retries the validation with jittered exponential backoff when the warehouse returns a transient
+// error, such as a rate limit or a network failure. Any other error is returned as is, since it means that the query
+// itself is invalid.
+type RetryingValidator struct {
+	Validator   queryValidator
+	IsTransient func(err error) bool
+	Logger      *zap.SugaredLogger
+
+	// MaxAttempts is the total number of attempts including the first one, defaults to 4.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func (r *RetryingValidator) IsValid(ctx context.Context, q *query.Query) (bool, error) {
+	maxAttempts := r.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		var valid bool
+		valid, err = r.Validator.IsValid(ctx, q)
+		if err == nil || !r.IsTransient(err) {
+			return valid, err
+		}
+
+		if ctx.Err() != nil {
+			return false, err
+		}
+
+		if attempt == maxAttempts {
+			break
+		}
+
+		delay := r.backoff(attempt)
+		r.Logger.Debugw("Transient error while validating the query, retrying", "attempt", attempt, "delay", delay, "error", err)
+
+		if sleepErr := r.sleepFunc()(ctx, delay); sleepErr != nil {
+			return false, err
+		}
+	}
+
+	return false, &UnvalidatedError{Err: err, Attempts: maxAttempts}
+}
+
+// backoff returns a random delay between zero and the exponential delay for the given attempt, the "full jitter"
+// approach spreads the retries of the concurrent workers so that they do not hit the rate limits at the same time.
+func (r *RetryingValidator) backoff(attempt int) time.Duration {
+	baseDelay := r.BaseDelay
+	if baseDelay <= 0 {
+		baseDelay = defaultBaseDelay
+	}
+
+	maxDelay := r.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = defaultMaxDelay
+	}
+
+	delay := maxDelay
+	if shift := attempt - 1; shift < 32 && baseDelay<<shift < maxDelay {
+		delay = baseDelay << shift
+	}
+
+	return time.Duration(rand.Int63n(int64(delay) + 1)) //nolint:gosec
+}
+
+func (r *RetryingValidator) sleepFunc() func(ctx context.Context, d time.Duration) error {
+	if r.sleep != nil {
+		return r.sleep
+	}
+
+	return sleepWithContext
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,154 @@
+package lint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/query"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var errTransient = errors.New("rate limit exceeded")
+
+func isTestErrorTransient(err error) bool {
+	return errors.Is(err, errTransient)
+}
+
+func TestRetryingValidator_IsValid(t *testing.T) {
+	t.Parallel()
+
+	q := &query.Query{Query: "select 1"}
+
+	tests := []struct {
+		name         string
+		setup        func(v *mockValidator)
+		want         bool
+		wantAttempts int
+		wantErr      func(t *testing.T, err error)
+	}{
+		{
+			name: "valid queries are not retried",
+			setup: func(v *mockValidator) {
+				v.On("IsValid", mock.Anything, q).Return(true, nil).Once()
+			},
+			want:         true,
+			wantAttempts: 1,
+		},
+		{
+			name: "invalid queries are not retried",
+			setup: func(v *mockValidator) {
+				v.On("IsValid", mock.Anything, q).Return(false, errors.New("syntax error")).Once()
+			},
+			wantAttempts: 1,
+			wantErr: func(t *testing.T, err error) {
+				require.EqualError(t, err, "syntax error")
+			},
+		},
+		{
+			name: "transient errors are retried until the query is validated",
+			setup: func(v *mockValidator) {
+				v.On("IsValid", mock.Anything, q).Return(false, errTransient).Twice()
+				v.On("IsValid", mock.Anything, q).Return(true, nil).Once()
+			},
+			want:         true,
+			wantAttempts: 3,
+		},
+		{
+			name: "an invalid query after a transient error is reported as invalid",
+			setup: func(v *mockValidator) {
+				v.On("IsValid", mock.Anything, q).Return(false, errTransient).Once()
+				v.On("IsValid", mock.Anything, q).Return(false, errors.New("syntax error")).Once()
+			},
+			wantAttempts: 2,
+			wantErr: func(t *testing.T, err error) {
+				require.EqualError(t, err, "syntax error")
+			},
+		},
+		{
+			name: "the query is reported as unvalidated when the attempts run out",
+			setup: func(v *mockValidator) {
+				v.On("IsValid", mock.Anything, q).Return(false, errTransient).Times(3)
+			},
+			wantAttempts: 3,
+			wantErr: func(t *testing.T, err error) {
+				var unvalidatedErr *UnvalidatedError
+				require.ErrorAs(t, err, &unvalidatedErr)
+				assert.Equal(t, 3, unvalidatedErr.Attempts)
+				assert.ErrorIs(t, err, errTransient)
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			validator := new(mockValidator)
+			tt.setup(validator)
+
+			delays := make([]time.Duration, 0)
+			r := &RetryingValidator{
+				Validator:   validator,
+				IsTransient: isTestErrorTransient,
+				Logger:      zap.NewNop().Sugar(),
+				MaxAttempts: 3,
+				BaseDelay:   100 * time.Millisecond,
+				MaxDelay:    150 * time.Millisecond,
+				sleep: func(ctx context.Context, d time.Duration) error {
+					delays = append(delays, d)
+					return nil
+				},
+			}
+
+			got, err := r.IsValid(context.Background(), q)
+			if tt.wantErr != nil {
+				tt.wantErr(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+			validator.AssertNumberOfCalls(t, "IsValid", tt.wantAttempts)
+
+			require.Len(t, delays, tt.wantAttempts-1)
+			for i, delay := range delays {
+				assert.GreaterOrEqual(t, delay, time.Duration(0))
+				assert.LessOrEqual(t, delay, r.BaseDelay<<i)
+				assert.LessOrEqual(t, delay, r.MaxDelay)
+			}
+		})
+	}
+}
+
+func TestRetryingValidator_IsValidStopsWhenTheContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	q := &query.Query{Query: "select 1"}
+	validator := new(mockValidator)
+	validator.On("IsValid", mock.Anything, q).Return(false, errTransient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &RetryingValidator{
+		Validator:   validator,
+		IsTransient: isTestErrorTransient,
+		Logger:      zap.NewNop().Sugar(),
+		MaxAttempts: 5,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			cancel()
+			return ctx.Err()
+		},
+	}
+
+	got, err := r.IsValid(ctx, q)
+	assert.False(t, got)
+	assert.ErrorIs(t, err, errTransient)
+
+	var unvalidatedErr *UnvalidatedError
+	assert.False(t, errors.As(err, &unvalidatedErr))
+	validator.AssertNumberOfCalls(t, "IsValid", 1)
+}
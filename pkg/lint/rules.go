@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/sensor"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
 	"github.com/pkg/errors"
-	"github.com/robfig/cron/v3"
 	"github.com/spf13/afero"
 	"github.com/yourbasic/graph"
 )
@@ -22,12 +26,25 @@ const (
 	executableFileDoesNotExist    = `The executable file does not exist`
 	executableFileIsADirectory    = `The executable file is a directory, must be a file`
 	executableFileIsEmpty         = `The executable file is empty`
-	executableFileIsNotExecutable = "Executable file is not executable, give it the '644' or '755' permissions"
+	executableFileIsNotExecutable = "Executable file is not executable, give it the '755' permissions"
 
-	pipelineNameCannotBeEmpty      = "The pipeline name cannot be empty, it must be a valid name made of alphanumeric characters, dashes, dots and underscores"
-	pipelineNameMustBeAlphanumeric = "The pipeline name must be made of alphanumeric characters, dashes, dots and underscores"
+	pipelineNameMustBeUnique = "The pipeline name '%s' is used by other pipelines too, the cross-pipeline dependencies cannot tell them apart"
+
+	definitionFileIsUnreadable   = "The file cannot be read"
+	definitionFileHasSyntaxError = "The file has a syntax error"
+	definitionFileIsInvalid      = "The file has invalid values"
+	taskDefinitionIsSkipped      = "the task it defines is skipped"
+	pipelineDefinitionIsIgnored  = "the pipeline settings are ignored"
+
+	maxTaskRetries    = 10
+	maxTaskRetryDelay = 24 * time.Hour
+	minTaskPriority   = -100
+	maxTaskPriority   = 100
+
+	taskOwnerMustExist = "The task must have an owner, set it on the task or on the pipeline"
 
 	pipelineContainsCycle = "The pipeline has a cycle with dependencies, make sure there are no cyclic dependencies"
+	pipelinesContainCycle = "The pipelines have a cycle through cross-pipeline dependencies, make sure there are no cyclic dependencies"
 )
 
 const (
@@ -36,17 +53,13 @@ const (
 	taskTypeBigqueryQuery  = "bq.sql"
 )
 
-var validTaskTypes = map[string]struct{}{
-	taskTypeBigqueryQuery:                  {},
-	"bq.sensor.table":                      {},
-	"bq.sensor.query":                      {},
-	"bq.cost_tracker":                      {},
-	"bash":                                 {},
-	"gcs.from.s3":                          {},
-	"gcs.sensor.object_sensor_with_prefix": {},
-	taskTypePython:                         {},
-	"s3.sensor.key_sensor":                 {},
-	taskTypeSnowflakeQuery:                 {},
+// taskTypes are the built-in task types along with their parameters, the project config can add custom types on top of
+// them.
+var taskTypes = tasktype.Builtin()
+
+// ValidTaskTypes returns the built-in task types, sorted alphabetically.
+func ValidTaskTypes() []string {
+	return taskTypes.Names()
 }
 
 var validIDRegexCompiled = regexp.MustCompile(validIDRegex)
@@ -110,7 +123,9 @@ func EnsureTaskNameIsUnique(p *pipeline.Pipeline) ([]*Issue, error) {
 	return issues, nil
 }
 
-func EnsureExecutableFileIsValid(fs afero.Fs) PipelineValidator {
+// EnsureExecutableFileIsValid checks the files the tasks run, the file is mandatory only for the task types in the
+// registry that require it and it must be executable only for the script types.
+func EnsureExecutableFileIsValid(fs afero.Fs, registry *tasktype.Registry) PipelineValidator {
 	return func(p *pipeline.Pipeline) ([]*Issue, error) {
 		issues := make([]*Issue, 0)
 		for _, task := range p.Tasks {
@@ -118,8 +133,9 @@ func EnsureExecutableFileIsValid(fs afero.Fs) PipelineValidator {
 				continue
 			}
 
+			taskType := registry.Get(task.Type)
 			if task.ExecutableFile.Path == "" {
-				if task.Type == taskTypePython {
+				if taskType != nil && taskType.RequiresExecutableFile {
 					issues = append(issues, &Issue{
 						Task:        task,
 						Description: executableFileCannotBeEmpty,
@@ -152,7 +168,7 @@ func EnsureExecutableFileIsValid(fs afero.Fs) PipelineValidator {
 				})
 			}
 
-			if isFileExecutable(fileInfo.Mode()) {
+			if taskType != nil && taskType.Script && !isFileExecutable(fileInfo.Mode()) {
 				issues = append(issues, &Issue{
 					Task:        task,
 					Description: executableFileIsNotExecutable,
@@ -164,19 +180,17 @@ func EnsureExecutableFileIsValid(fs afero.Fs) PipelineValidator {
 	}
 }
 
-func EnsurePipelineNameIsValid(pipeline *pipeline.Pipeline) ([]*Issue, error) {
+// EnsurePipelineNameIsValid skips the pipelines whose definition is broken, which are reported by the
+// `valid-definition-files` rule already.
+func EnsurePipelineNameIsValid(p *pipeline.Pipeline) ([]*Issue, error) {
 	issues := make([]*Issue, 0)
-	if pipeline.Name == "" {
-		issues = append(issues, &Issue{
-			Description: pipelineNameCannotBeEmpty,
-		})
-
+	if p.HasBrokenDefinition() {
 		return issues, nil
 	}
 
-	if match := validIDRegexCompiled.MatchString(pipeline.Name); !match {
+	if err := pipeline.ValidateName(p.Name); err != nil {
 		issues = append(issues, &Issue{
-			Description: pipelineNameMustBeAlphanumeric,
+			Description: err.Error(),
 		})
 	}
 
@@ -200,6 +214,11 @@ func EnsureDependencyExists(p *pipeline.Pipeline) ([]*Issue, error) {
 	issues := make([]*Issue, 0)
 	for _, task := range p.Tasks {
 		for _, dep := range task.DependsOn {
+			// the cross-pipeline dependencies are resolved by EnsureCrossPipelineDependenciesExist
+			if pipeline.IsCrossPipelineDependency(dep) {
+				continue
+			}
+
 			if _, ok := taskMap[dep]; !ok {
 				issues = append(issues, &Issue{
 					Task:        task,
@@ -212,34 +231,103 @@ func EnsureDependencyExists(p *pipeline.Pipeline) ([]*Issue, error) {
 	return issues, nil
 }
 
+// EnsurePipelineScheduleIsValidCron skips the pipelines whose definition is broken, the same way as
+// EnsurePipelineNameIsValid.
 func EnsurePipelineScheduleIsValidCron(p *pipeline.Pipeline) ([]*Issue, error) {
 	issues := make([]*Issue, 0)
-	if p.Schedule == "" {
+	if p.HasBrokenDefinition() {
 		return issues, nil
 	}
 
-	_, err := cron.ParseStandard(string(p.Schedule))
-	if err != nil {
+	if err := pipeline.ValidateSchedule(string(p.Schedule)); err != nil {
+		issues = append(issues, &Issue{
+			Description: err.Error(),
+		})
+	}
+
+	return issues, nil
+}
+
+// EnsureOnlyAcceptedTaskTypesAreThere reports the tasks whose types are not in the registry.
+func EnsureOnlyAcceptedTaskTypesAreThere(registry *tasktype.Registry) PipelineValidator {
+	return func(p *pipeline.Pipeline) ([]*Issue, error) {
+		issues := make([]*Issue, 0)
+
+		for _, task := range p.Tasks {
+			if task.Type == "" {
+				continue
+			}
+
+			if registry.Get(task.Type) == nil {
+				issues = append(issues, &Issue{
+					Task:        task,
+					Description: fmt.Sprintf("Invalid task type '%s'", task.Type),
+				})
+			}
+		}
+
+		return issues, nil
+	}
+}
+
+// EnsureDefinitionFilesAreValid reports the files that could not be read while building the pipeline, which allows
+// reporting the rest of the issues instead of stopping at the first broken file.
+func EnsureDefinitionFilesAreValid(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0, len(p.BuildErrors))
+	for _, buildError := range p.BuildErrors {
+		problem := definitionFileHasSyntaxError
+		switch buildError.Kind {
+		case pipeline.BuildErrorUnreadable:
+			problem = definitionFileIsUnreadable
+		case pipeline.BuildErrorInvalid:
+			problem = definitionFileIsInvalid
+		case pipeline.BuildErrorSyntax:
+		}
+
+		consequence := taskDefinitionIsSkipped
+		if buildError.Path == p.DefinitionFile.Path {
+			consequence = pipelineDefinitionIsIgnored
+		}
+
 		issues = append(issues, &Issue{
-			Description: fmt.Sprintf("Invalid cron schedule '%s'", p.Schedule),
+			Description: fmt.Sprintf("%s, %s", problem, consequence),
+			Context:     []string{buildError.Path, buildError.Err.Error()},
 		})
 	}
 
 	return issues, nil
 }
 
-func EnsureOnlyAcceptedTaskTypesAreThere(p *pipeline.Pipeline) ([]*Issue, error) {
+// EnsureDefinitionKeysAreValid reports the unknown and the duplicate keys in the pipeline definition and in the task
+// definitions, which do not prevent building the pipeline but usually point to a typo.
+func EnsureDefinitionKeysAreValid(p *pipeline.Pipeline) ([]*Issue, error) {
 	issues := make([]*Issue, 0)
+	for _, violation := range p.DefinitionFile.Violations {
+		issues = append(issues, &Issue{
+			Description: fmt.Sprintf("%s line %d: %s", p.DefinitionFile.Name, violation.Line, violation.Message),
+		})
+	}
 
 	for _, task := range p.Tasks {
-		if task.Type == "" {
-			continue
+		for _, violation := range task.DefinitionFile.Violations {
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: fmt.Sprintf("%s line %d: %s", task.DefinitionFile.Name, violation.Line, violation.Message),
+			})
 		}
+	}
+
+	return issues, nil
+}
 
-		if _, ok := validTaskTypes[task.Type]; !ok {
+// EnsureTaskHasOwner reports the tasks that have no owner, neither on their own nor inherited from the pipeline.
+func EnsureTaskHasOwner(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+	for _, task := range p.Tasks {
+		if p.EffectiveOwner(task) == "" {
 			issues = append(issues, &Issue{
 				Task:        task,
-				Description: fmt.Sprintf("Invalid task type '%s'", task.Type),
+				Description: taskOwnerMustExist,
 			})
 		}
 	}
@@ -247,6 +335,212 @@ func EnsureOnlyAcceptedTaskTypesAreThere(p *pipeline.Pipeline) ([]*Issue, error)
 	return issues, nil
 }
 
+// EnsureTaskOwnerMatches reports the owners that do not match the pattern, the tasks without an owner are left to
+// the `task-owner-exists` rule.
+func EnsureTaskOwnerMatches(pattern *regexp.Regexp) PipelineValidator {
+	return func(p *pipeline.Pipeline) ([]*Issue, error) {
+		issues := make([]*Issue, 0)
+		for _, task := range p.Tasks {
+			owner := p.EffectiveOwner(task)
+			if owner == "" || pattern.MatchString(owner) {
+				continue
+			}
+
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: fmt.Sprintf("The owner '%s' does not match the allowed pattern '%s'", owner, pattern),
+			})
+		}
+
+		return issues, nil
+	}
+}
+
+// EnsureTaskTagsAreAllowed reports the tags that are not in the given list, including the ones inherited from the
+// pipeline.
+func EnsureTaskTagsAreAllowed(allowedTags []string) PipelineValidator {
+	allowed := make(map[string]bool, len(allowedTags))
+	for _, tag := range allowedTags {
+		allowed[tag] = true
+	}
+
+	return func(p *pipeline.Pipeline) ([]*Issue, error) {
+		issues := make([]*Issue, 0)
+		for _, task := range p.Tasks {
+			for _, tag := range p.EffectiveTags(task) {
+				if allowed[tag] {
+					continue
+				}
+
+				issues = append(issues, &Issue{
+					Task:        task,
+					Description: fmt.Sprintf("The tag '%s' is not allowed", tag),
+					Context:     []string{fmt.Sprintf("the allowed tags are: %s", strings.Join(allowedTags, ", "))},
+				})
+			}
+		}
+
+		return issues, nil
+	}
+}
+
+// EnsureExecutionPoliciesAreValid checks the default policy of the pipeline and the policies of the tasks on their own,
+// so that a problem in the defaults is reported once instead of once for every task that inherits it.
+func EnsureExecutionPoliciesAreValid(p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+	defaultProblems := policyProblems(p.DefaultPolicy)
+	if len(defaultProblems) == 0 {
+		defaultProblems = slaProblems(p.DefaultPolicy)
+	}
+
+	for _, problem := range defaultProblems {
+		issues = append(issues, &Issue{
+			Description: fmt.Sprintf("The default policy of the pipeline is invalid: %s", problem),
+		})
+	}
+
+	for _, task := range p.Tasks {
+		problems := policyProblems(task.Policy)
+		if len(problems) == 0 && (task.Policy.Timeout != "" || task.Policy.SLA != "") {
+			// the durations coming from the defaults are already checked, only the combination is left
+			problems = append(problems, slaProblems(p.EffectivePolicy(task))...)
+		}
+
+		for _, problem := range problems {
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: fmt.Sprintf("The execution policy is invalid: %s", problem),
+			})
+		}
+	}
+
+	return issues, nil
+}
+
+func policyProblems(policy pipeline.ExecutionPolicy) []string {
+	problems := make([]string, 0)
+	if policy.Retries != nil && (*policy.Retries < 0 || *policy.Retries > maxTaskRetries) {
+		problems = append(problems, fmt.Sprintf("the retries must be between 0 and %d, got %d", maxTaskRetries, *policy.Retries))
+	}
+
+	if policy.Priority != nil && (*policy.Priority < minTaskPriority || *policy.Priority > maxTaskPriority) {
+		problems = append(problems, fmt.Sprintf("the priority must be between %d and %d, got %d", minTaskPriority, maxTaskPriority, *policy.Priority))
+	}
+
+	if policy.Pool != "" && !validIDRegexCompiled.MatchString(policy.Pool) {
+		problems = append(problems, fmt.Sprintf("the pool '%s' must be made of alphanumeric characters, dashes, dots and underscores", policy.Pool))
+	}
+
+	resolved, err := policy.Resolve()
+	if err != nil {
+		return append(problems, err.Error())
+	}
+
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+		set   bool
+	}{
+		{name: "retry_delay", value: resolved.RetryDelay, set: policy.RetryDelay != ""},
+		{name: "timeout", value: resolved.Timeout, set: policy.Timeout != ""},
+		{name: "sla", value: resolved.SLA, set: policy.SLA != ""},
+	} {
+		if d.set && d.value <= 0 {
+			problems = append(problems, fmt.Sprintf("the %s must be positive, got %s", d.name, d.value))
+		}
+	}
+
+	if resolved.RetryDelay > maxTaskRetryDelay {
+		problems = append(problems, fmt.Sprintf("the retry_delay must be at most %s, got %s", maxTaskRetryDelay, resolved.RetryDelay))
+	}
+
+	return problems
+}
+
+// slaProblems reports the SLAs that cannot be met even if the first attempt succeeds.
+func slaProblems(policy pipeline.ExecutionPolicy) []string {
+	resolved, err := policy.Resolve()
+	if err != nil || resolved.SLA <= 0 || resolved.Timeout <= resolved.SLA {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("the sla (%s) is shorter than the timeout (%s), a single attempt can take longer than the sla", resolved.SLA, resolved.Timeout)}
+}
+
+// EnsureTaskPoolsExist reports the tasks that use a pool that is not configured, which would not limit the tasks at
+// all. The pools of the tasks are checked after the defaults of the pipeline are applied.
+func EnsureTaskPoolsExist(pools map[string]int) PipelineValidator {
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return func(p *pipeline.Pipeline) ([]*Issue, error) {
+		issues := make([]*Issue, 0)
+		for _, task := range p.Tasks {
+			pool := p.EffectivePolicy(task).Pool
+			if _, ok := pools[pool]; ok || pool == "" {
+				continue
+			}
+
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: fmt.Sprintf("The pool '%s' is not defined in the project config", pool),
+				Context:     []string{fmt.Sprintf("the defined pools are: %s", strings.Join(names, ", "))},
+			})
+		}
+
+		return issues, nil
+	}
+}
+
+// EnsureTaskParametersAreValid reports the unknown, missing and invalid parameters of the tasks based on the parameters
+// of their types in the registry. The tasks with unknown types are left to the task type rule.
+func EnsureTaskParametersAreValid(registry *tasktype.Registry) PipelineValidator {
+	return func(p *pipeline.Pipeline) ([]*Issue, error) {
+		issues := make([]*Issue, 0)
+		for _, task := range p.Tasks {
+			taskType := registry.Get(task.Type)
+			if taskType == nil {
+				continue
+			}
+
+			for _, problem := range taskType.Validate(task.Parameters, p.DefaultParameters) {
+				issues = append(issues, &Issue{
+					Task:        task,
+					Description: fmt.Sprintf("The task parameters are invalid: %s", problem),
+				})
+			}
+		}
+
+		return issues, nil
+	}
+}
+
+// EnsureSensorParametersAreValid reports the sensor tasks of the given type whose parameters do not work together, e.g.
+// a bucket name along with a full S3 URL. The parameters on their own are checked by the task parameters rule.
+func EnsureSensorParametersAreValid(taskType string) PipelineValidator {
+	return func(p *pipeline.Pipeline) ([]*Issue, error) {
+		issues := make([]*Issue, 0)
+		for _, task := range p.Tasks {
+			if task.Type != taskType {
+				continue
+			}
+
+			parameters := taskTypes.Get(taskType).Resolve(task.Parameters, p.DefaultParameters)
+			for _, problem := range sensor.Validate(taskType, parameters) {
+				issues = append(issues, &Issue{
+					Task:        task,
+					Description: fmt.Sprintf("The sensor parameters are invalid: %s", problem),
+				})
+			}
+		}
+
+		return issues, nil
+	}
+}
+
 // EnsurePipelineHasNoCycles ensures that the pipeline is a DAG, and contains no cycles.
 // Since the pipelines are directed graphs, strongly connected components mean cycles, therefore
 // they would be considered invalid for our pipelines.
@@ -273,7 +567,14 @@ func EnsurePipelineHasNoCycles(p *pipeline.Pipeline) ([]*Issue, error) {
 	g := graph.New(len(p.Tasks))
 	for _, task := range p.Tasks {
 		for _, dep := range task.DependsOn {
-			g.Add(taskNameToIndex[task.Name], taskNameToIndex[dep])
+			// the missing dependencies are reported by EnsureDependencyExists, and the cycles through other pipelines
+			// by EnsurePipelinesHaveNoCycles
+			depIndex, ok := taskNameToIndex[dep]
+			if !ok {
+				continue
+			}
+
+			g.Add(taskNameToIndex[task.Name], depIndex)
 		}
 	}
 
@@ -309,3 +610,177 @@ func EnsurePipelineHasNoCycles(p *pipeline.Pipeline) ([]*Issue, error) {
 
 	return issues, nil
 }
+
+// EnsureCrossPipelineDependenciesExist resolves the dependencies qualified with a pipeline name, e.g.
+// `core-pipeline:orders_clean`, across all the given pipelines.
+func EnsureCrossPipelineDependenciesExist(pipelines []*pipeline.Pipeline) (map[*pipeline.Pipeline][]*Issue, error) {
+	pipelinesByName := make(map[string]*pipeline.Pipeline, len(pipelines))
+	for _, p := range pipelines {
+		// duplicate pipeline names are reported by EnsurePipelineNamesAreUnique, the first one wins until they are fixed
+		if _, ok := pipelinesByName[p.Name]; !ok {
+			pipelinesByName[p.Name] = p
+		}
+	}
+
+	issues := make(map[*pipeline.Pipeline][]*Issue)
+	for _, p := range pipelines {
+		for _, task := range p.Tasks {
+			for _, dep := range task.CrossPipelineDependencies() {
+				var description string
+				upstreamPipeline, ok := pipelinesByName[dep.Pipeline]
+				switch {
+				case !ok:
+					description = fmt.Sprintf("Dependency '%s' does not exist, there is no pipeline named '%s'", dep, dep.Pipeline)
+				case upstreamPipeline.GetTaskByName(dep.Task) == nil:
+					description = fmt.Sprintf("Dependency '%s' does not exist, there is no task named '%s' in pipeline '%s'", dep, dep.Task, dep.Pipeline)
+				default:
+					continue
+				}
+
+				issues[p] = append(issues[p], &Issue{
+					Task:        task,
+					Description: description,
+				})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// EnsurePipelineNamesAreUnique reports the pipelines that share their name with another pipeline, listing the others
+// as the context. The pipelines without a name are reported by EnsurePipelineNameIsValid instead.
+func EnsurePipelineNamesAreUnique(pipelines []*pipeline.Pipeline) (map[*pipeline.Pipeline][]*Issue, error) {
+	pipelinesByName := make(map[string][]*pipeline.Pipeline, len(pipelines))
+	for _, p := range pipelines {
+		if p.Name != "" {
+			pipelinesByName[p.Name] = append(pipelinesByName[p.Name], p)
+		}
+	}
+
+	issues := make(map[*pipeline.Pipeline][]*Issue)
+	for _, p := range pipelines {
+		sameName := pipelinesByName[p.Name]
+		if len(sameName) < 2 {
+			continue
+		}
+
+		others := make([]string, 0, len(sameName)-1)
+		for _, other := range sameName {
+			if other != p {
+				others = append(others, fmt.Sprintf("Also defined in %s", other.DefinitionFile.Path))
+			}
+		}
+
+		issues[p] = append(issues[p], &Issue{
+			Description: fmt.Sprintf(pipelineNameMustBeUnique, p.Name),
+			Context:     others,
+		})
+	}
+
+	return issues, nil
+}
+
+// EnsurePipelinesHaveNoCycles builds a single graph out of the tasks of all the pipelines, and reports the cycles that
+// go through at least one cross-pipeline dependency. The cycles within a single pipeline are reported by
+// EnsurePipelineHasNoCycles instead. The issues are added to every pipeline that is a part of the cycle.
+func EnsurePipelinesHaveNoCycles(pipelines []*pipeline.Pipeline) (map[*pipeline.Pipeline][]*Issue, error) {
+	type taskNode struct {
+		pipeline *pipeline.Pipeline
+		task     *pipeline.Task
+		id       string
+	}
+
+	nodes := make([]*taskNode, 0)
+	nodeIndex := make(map[string]int)
+	for _, p := range pipelines {
+		for _, task := range p.Tasks {
+			id := pipeline.Dependency{Pipeline: p.Name, Task: task.Name}.String()
+			if _, ok := nodeIndex[id]; !ok {
+				nodeIndex[id] = len(nodes)
+			}
+
+			nodes = append(nodes, &taskNode{pipeline: p, task: task, id: id})
+		}
+	}
+
+	issues := make(map[*pipeline.Pipeline][]*Issue)
+	crossPipelineEdges := make(map[[2]int]bool)
+	dependencies := make([][]int, len(nodes))
+	g := graph.New(len(nodes))
+	for i, node := range nodes {
+		for _, dep := range node.task.DependsOn {
+			dependency := pipeline.ParseDependency(dep)
+			if !dependency.IsCrossPipeline() {
+				dependency.Pipeline = node.pipeline.Name
+			}
+
+			depIndex, ok := nodeIndex[dependency.String()]
+			if !ok {
+				continue
+			}
+
+			g.Add(i, depIndex)
+			dependencies[i] = append(dependencies[i], depIndex)
+			if !pipeline.IsCrossPipelineDependency(dep) {
+				continue
+			}
+
+			crossPipelineEdges[[2]int{i, depIndex}] = true
+			if depIndex == i {
+				issues[node.pipeline] = append(issues[node.pipeline], &Issue{
+					Description: pipelinesContainCycle,
+					Context:     []string{fmt.Sprintf("Task `%s` depends on itself", node.id)},
+				})
+			}
+		}
+	}
+
+	for _, cycle := range graph.StrongComponents(g) {
+		if len(cycle) == 1 {
+			continue
+		}
+
+		inCycle := make(map[int]bool, len(cycle))
+		for _, index := range cycle {
+			inCycle[index] = true
+		}
+
+		sort.Ints(cycle)
+		context := make([]string, 0, len(cycle))
+		involvesOtherPipelines := false
+		for _, index := range cycle {
+			for _, depIndex := range dependencies[index] {
+				if !inCycle[depIndex] {
+					continue
+				}
+
+				if crossPipelineEdges[[2]int{index, depIndex}] {
+					involvesOtherPipelines = true
+				}
+
+				context = append(context, fmt.Sprintf("%s ➜ %s", nodes[index].id, nodes[depIndex].id))
+			}
+		}
+
+		if !involvesOtherPipelines {
+			continue
+		}
+
+		reported := make(map[*pipeline.Pipeline]bool)
+		for _, index := range cycle {
+			p := nodes[index].pipeline
+			if reported[p] {
+				continue
+			}
+
+			reported[p] = true
+			issues[p] = append(issues[p], &Issue{
+				Description: pipelinesContainCycle,
+				Context:     context,
+			})
+		}
+	}
+
+	return issues, nil
+}
@@ -1,10 +1,15 @@
 package lint
 
 import (
-	"os"
+	"regexp"
 	"testing"
 
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/datablast-analytics/blast-cli/pkg/export/airflow"
+	"github.com/datablast-analytics/blast-cli/pkg/jsonschema"
 	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -125,6 +130,32 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 			},
 			want: noIssues,
 		},
+		{
+			name: "task with no executable is reported for the custom types that require one",
+			args: args{
+				pipeline: pipeline.Pipeline{
+					Tasks: []*pipeline.Task{
+						{
+							Type: "dbt.model",
+							DefinitionFile: pipeline.DefinitionFile{
+								Type: pipeline.YamlTask,
+							},
+						},
+					},
+				},
+			},
+			want: []*Issue{
+				{
+					Task: &pipeline.Task{
+						Type: "dbt.model",
+						DefinitionFile: pipeline.DefinitionFile{
+							Type: pipeline.YamlTask,
+						},
+					},
+					Description: executableFileCannotBeEmpty,
+				},
+			},
+		},
 		{
 			name: "task with no executable is skipped",
 			args: args{
@@ -248,6 +279,7 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 				pipeline: pipeline.Pipeline{
 					Tasks: []*pipeline.Task{
 						{
+							Type: "bash",
 							DefinitionFile: pipeline.DefinitionFile{
 								Type: pipeline.YamlTask,
 							},
@@ -262,6 +294,7 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 			want: []*Issue{
 				{
 					Task: &pipeline.Task{
+						Type: "bash",
 						DefinitionFile: pipeline.DefinitionFile{
 							Type: pipeline.YamlTask,
 						},
@@ -272,18 +305,6 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 					},
 					Description: executableFileIsEmpty,
 				},
-				{
-					Task: &pipeline.Task{
-						DefinitionFile: pipeline.DefinitionFile{
-							Type: pipeline.YamlTask,
-						},
-						ExecutableFile: pipeline.ExecutableFile{
-							Name: "some-file.sh",
-							Path: "some-path/some-file.sh",
-						},
-					},
-					Description: executableFileIsNotExecutable,
-				},
 			},
 		},
 		{
@@ -293,7 +314,7 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 					fileName := "some-path/some-file.sh"
 					file, err := fs.Create(fileName)
 					require.NoError(t, err)
-					err = fs.Chmod(fileName, os.FileMode(0o100))
+					err = fs.Chmod(fileName, 0o644)
 					require.NoError(t, err)
 					defer func() { require.NoError(t, file.Close()) }()
 
@@ -303,6 +324,7 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 				pipeline: pipeline.Pipeline{
 					Tasks: []*pipeline.Task{
 						{
+							Type: "bash",
 							DefinitionFile: pipeline.DefinitionFile{
 								Type: pipeline.YamlTask,
 							},
@@ -317,6 +339,7 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 			want: []*Issue{
 				{
 					Task: &pipeline.Task{
+						Type: "bash",
 						DefinitionFile: pipeline.DefinitionFile{
 							Type: pipeline.YamlTask,
 						},
@@ -337,17 +360,17 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 					require.NoError(t, err)
 					defer func() { require.NoError(t, file.Close()) }()
 
-					err = fs.Chmod("some-path/some-file.sh", 0o644)
+					err = fs.Chmod("some-path/some-file.sh", 0o755)
 					require.NoError(t, err)
 
 					_, err = file.Write([]byte("some content"))
 					require.NoError(t, err)
 
-					file, err = fs.Create("some-path/some-other-file.sh")
+					file, err = fs.Create("some-path/some-query.sql")
 					require.NoError(t, err)
 					defer func() { require.NoError(t, file.Close()) }()
 
-					err = fs.Chmod("some-path/some-other-file.sh", 0o644)
+					err = fs.Chmod("some-path/some-query.sql", 0o644)
 					require.NoError(t, err)
 
 					_, err = file.Write([]byte("some other content"))
@@ -356,6 +379,7 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 				pipeline: pipeline.Pipeline{
 					Tasks: []*pipeline.Task{
 						{
+							Type: "bash",
 							DefinitionFile: pipeline.DefinitionFile{
 								Type: pipeline.YamlTask,
 							},
@@ -365,12 +389,13 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 							},
 						},
 						{
+							Type: "bq.sql",
 							DefinitionFile: pipeline.DefinitionFile{
 								Type: pipeline.YamlTask,
 							},
 							ExecutableFile: pipeline.ExecutableFile{
-								Name: "some-other-file.sh",
-								Path: "some-path/some-other-file.sh",
+								Name: "some-query.sql",
+								Path: "some-path/some-query.sql",
 							},
 						},
 					},
@@ -389,7 +414,7 @@ func TestEnsureExecutableFileIsValid(t *testing.T) {
 				tt.args.setupFilesystem(t, fs)
 			}
 
-			checker := EnsureExecutableFileIsValid(fs)
+			checker := EnsureExecutableFileIsValid(fs, customTaskTypes(t))
 
 			got, err := checker(&tt.args.pipeline)
 			if tt.wantErr {
@@ -478,6 +503,20 @@ func TestEnsureDependencyExists(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "cross-pipeline dependencies are left to the project rule",
+			args: args{
+				p: &pipeline.Pipeline{
+					Tasks: []*pipeline.Task{
+						{
+							Name:      "task1",
+							DependsOn: []string{"core-pipeline:orders_clean"},
+						},
+					},
+				},
+			},
+			want: noIssues,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -530,6 +569,19 @@ func TestEnsurePipelineScheduleIsValidCron(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "pipelines with a broken definition are skipped",
+			args: args{
+				p: &pipeline.Pipeline{
+					Schedule:       "some random schedule",
+					DefinitionFile: pipeline.DefinitionFile{Path: "/pipelines/sales/pipeline.yml"},
+					BuildErrors: []*pipeline.BuildError{
+						{Path: "/pipelines/sales/pipeline.yml", Kind: pipeline.BuildErrorInvalid},
+					},
+				},
+			},
+			want: noIssues,
+		},
 		{
 			name: "valid schedule passes the check",
 			args: args{
@@ -566,6 +618,18 @@ func TestEnsurePipelineScheduleIsValidCron(t *testing.T) {
 	}
 }
 
+// customTaskTypes returns the built-in task types along with a custom one that requires an executable file.
+func customTaskTypes(t *testing.T) *tasktype.Registry {
+	t.Helper()
+
+	registry, err := tasktype.Load(map[string]config.TaskType{
+		"dbt.model": {Extension: ".sql", RequiresExecutableFile: true},
+	})
+	require.NoError(t, err)
+
+	return registry
+}
+
 func TestEnsureOnlyAcceptedTaskTypesAreThere(t *testing.T) {
 	t.Parallel()
 
@@ -624,13 +688,26 @@ func TestEnsureOnlyAcceptedTaskTypesAreThere(t *testing.T) {
 			},
 			want: noIssues,
 		},
+		{
+			name: "task with a custom type is not flagged",
+			args: args{
+				p: &pipeline.Pipeline{
+					Tasks: []*pipeline.Task{
+						{
+							Type: "dbt.model",
+						},
+					},
+				},
+			},
+			want: noIssues,
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := EnsureOnlyAcceptedTaskTypesAreThere(tt.args.p)
+			got, err := EnsureOnlyAcceptedTaskTypesAreThere(customTaskTypes(t))(tt.args.p)
 			if tt.wantErr {
 				require.Error(t, err)
 			} else {
@@ -749,12 +826,24 @@ func TestEnsurePipelineNameIsValid(t *testing.T) {
 			},
 			want: []*Issue{
 				{
-					Description: pipelineNameCannotBeEmpty,
+					Description: pipeline.ErrEmptyName.Error(),
 					Context:     nil,
 				},
 			},
 			wantErr: false,
 		},
+		{
+			name: "pipelines with a broken definition are skipped",
+			args: args{
+				p: &pipeline.Pipeline{
+					DefinitionFile: pipeline.DefinitionFile{Path: "/pipelines/sales/pipeline.yml"},
+					BuildErrors: []*pipeline.BuildError{
+						{Path: "/pipelines/sales/pipeline.yml", Kind: pipeline.BuildErrorSyntax},
+					},
+				},
+			},
+			want: noIssues,
+		},
 		{
 			name: "spaces are not accepted",
 			args: args{
@@ -764,7 +853,7 @@ func TestEnsurePipelineNameIsValid(t *testing.T) {
 			},
 			want: []*Issue{
 				{
-					Description: pipelineNameMustBeAlphanumeric,
+					Description: pipeline.ErrInvalidName.Error(),
 					Context:     nil,
 				},
 			},
@@ -798,6 +887,438 @@ func TestEnsurePipelineNameIsValid(t *testing.T) {
 	}
 }
 
+func TestEnsureDefinitionFilesAreValid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		p    *pipeline.Pipeline
+		want []*Issue
+	}{
+		{
+			name: "pipeline without build errors passes",
+			p: &pipeline.Pipeline{
+				DefinitionFile: pipeline.DefinitionFile{Path: "/pipeline/pipeline.yml"},
+			},
+			want: noIssues,
+		},
+		{
+			name: "build errors are reported with the file and the cause",
+			p: &pipeline.Pipeline{
+				DefinitionFile: pipeline.DefinitionFile{Path: "/pipeline/pipeline.yml"},
+				BuildErrors: []*pipeline.BuildError{
+					{Path: "/pipeline/pipeline.yml", Kind: pipeline.BuildErrorSyntax, Err: errors.New("yaml: line 1: did not find expected node content")},
+					{Path: "/pipeline/tasks/task1/task.yml", Kind: pipeline.BuildErrorUnreadable, Err: errors.New("permission denied")},
+					{Path: "/pipeline/tasks/task2/task.yml", Kind: pipeline.BuildErrorInvalid, Err: errors.New("line 3: `depends` must be a list")},
+					{Path: "/pipeline/tasks/task3.sql", Kind: pipeline.BuildErrorSyntax, Err: errors.New("line 1: the block is not closed with `*/`")},
+				},
+			},
+			want: []*Issue{
+				{
+					Description: "The file has a syntax error, the pipeline settings are ignored",
+					Context:     []string{"/pipeline/pipeline.yml", "yaml: line 1: did not find expected node content"},
+				},
+				{
+					Description: "The file cannot be read, the task it defines is skipped",
+					Context:     []string{"/pipeline/tasks/task1/task.yml", "permission denied"},
+				},
+				{
+					Description: "The file has invalid values, the task it defines is skipped",
+					Context:     []string{"/pipeline/tasks/task2/task.yml", "line 3: `depends` must be a list"},
+				},
+				{
+					Description: "The file has a syntax error, the task it defines is skipped",
+					Context:     []string{"/pipeline/tasks/task3.sql", "line 1: the block is not closed with `*/`"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureDefinitionFilesAreValid(tt.p)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsureDefinitionKeysAreValid(t *testing.T) {
+	t.Parallel()
+
+	taskWithViolations := &pipeline.Task{
+		Name: "task1",
+		DefinitionFile: pipeline.DefinitionFile{
+			Name: "task.yml",
+			Violations: jsonschema.Violations{
+				{Kind: jsonschema.ViolationUnknownKey, Line: 4, Message: "unknown key `depend`, did you mean `depends`?"},
+				{Kind: jsonschema.ViolationDuplicateKey, Line: 9, Message: "duplicate key `type`, it is already defined on line 2 and only the last value is used"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name string
+		p    *pipeline.Pipeline
+		want []*Issue
+	}{
+		{
+			name: "pipeline without violations passes",
+			p: &pipeline.Pipeline{
+				DefinitionFile: pipeline.DefinitionFile{Name: "pipeline.yml"},
+				Tasks:          []*pipeline.Task{{Name: "task1"}},
+			},
+			want: noIssues,
+		},
+		{
+			name: "violations in the pipeline and the tasks are reported",
+			p: &pipeline.Pipeline{
+				DefinitionFile: pipeline.DefinitionFile{
+					Name: "pipeline.yml",
+					Violations: jsonschema.Violations{
+						{Kind: jsonschema.ViolationUnknownKey, Line: 3, Message: "unknown key `defaultParameter`, did you mean `defaultParameters`?"},
+					},
+				},
+				Tasks: []*pipeline.Task{{Name: "task0"}, taskWithViolations},
+			},
+			want: []*Issue{
+				{
+					Description: "pipeline.yml line 3: unknown key `defaultParameter`, did you mean `defaultParameters`?",
+				},
+				{
+					Task:        taskWithViolations,
+					Description: "task.yml line 4: unknown key `depend`, did you mean `depends`?",
+				},
+				{
+					Task:        taskWithViolations,
+					Description: "task.yml line 9: duplicate key `type`, it is already defined on line 2 and only the last value is used",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureDefinitionKeysAreValid(tt.p)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsureTaskHasOwner(t *testing.T) {
+	t.Parallel()
+
+	withOwner := &pipeline.Task{Name: "task1", Owner: "jane@example.com"}
+	withoutOwner := &pipeline.Task{Name: "task2"}
+
+	tests := []struct {
+		name string
+		p    *pipeline.Pipeline
+		want []*Issue
+	}{
+		{
+			name: "tasks without an owner are reported",
+			p:    &pipeline.Pipeline{Tasks: []*pipeline.Task{withOwner, withoutOwner}},
+			want: []*Issue{
+				{
+					Task:        withoutOwner,
+					Description: taskOwnerMustExist,
+				},
+			},
+		},
+		{
+			name: "the owner of the pipeline is inherited",
+			p:    &pipeline.Pipeline{Owner: "data@example.com", Tasks: []*pipeline.Task{withOwner, withoutOwner}},
+			want: noIssues,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureTaskHasOwner(tt.p)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsureTaskOwnerMatches(t *testing.T) {
+	t.Parallel()
+
+	valid := &pipeline.Task{Name: "task1", Owner: "jane@example.com"}
+	invalid := &pipeline.Task{Name: "task2", Owner: "jane@gmail.com"}
+	inherited := &pipeline.Task{Name: "task3"}
+
+	tests := []struct {
+		name string
+		p    *pipeline.Pipeline
+		want []*Issue
+	}{
+		{
+			name: "owners that do not match are reported",
+			p:    &pipeline.Pipeline{Tasks: []*pipeline.Task{valid, invalid, inherited}},
+			want: []*Issue{
+				{
+					Task:        invalid,
+					Description: "The owner 'jane@gmail.com' does not match the allowed pattern '@example\\.com$'",
+				},
+			},
+		},
+		{
+			name: "the owner of the pipeline is checked as well",
+			p:    &pipeline.Pipeline{Owner: "data", Tasks: []*pipeline.Task{valid, inherited}},
+			want: []*Issue{
+				{
+					Task:        inherited,
+					Description: "The owner 'data' does not match the allowed pattern '@example\\.com$'",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureTaskOwnerMatches(regexp.MustCompile(`@example\.com$`))(tt.p)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsureTaskTagsAreAllowed(t *testing.T) {
+	t.Parallel()
+
+	allowed := &pipeline.Task{Name: "task1", Tags: []string{"finance"}}
+	unknown := &pipeline.Task{Name: "task2", Tags: []string{"finance", "misc"}}
+
+	tests := []struct {
+		name string
+		p    *pipeline.Pipeline
+		want []*Issue
+	}{
+		{
+			name: "allowed tags pass",
+			p:    &pipeline.Pipeline{Tags: []string{"daily"}, Tasks: []*pipeline.Task{allowed}},
+			want: noIssues,
+		},
+		{
+			name: "unknown tags are reported, including the ones of the pipeline",
+			p:    &pipeline.Pipeline{Tags: []string{"weekly"}, Tasks: []*pipeline.Task{allowed, unknown}},
+			want: []*Issue{
+				{
+					Task:        allowed,
+					Description: "The tag 'weekly' is not allowed",
+					Context:     []string{"the allowed tags are: daily, finance"},
+				},
+				{
+					Task:        unknown,
+					Description: "The tag 'weekly' is not allowed",
+					Context:     []string{"the allowed tags are: daily, finance"},
+				},
+				{
+					Task:        unknown,
+					Description: "The tag 'misc' is not allowed",
+					Context:     []string{"the allowed tags are: daily, finance"},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureTaskTagsAreAllowed([]string{"daily", "finance"})(tt.p)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsureExecutionPoliciesAreValid(t *testing.T) {
+	t.Parallel()
+
+	intPtr := func(value int) *int {
+		return &value
+	}
+
+	validTask := &pipeline.Task{Name: "valid", Policy: pipeline.ExecutionPolicy{Retries: intPtr(3), RetryDelay: "5m", Timeout: "1h", SLA: "2h", Pool: "bigquery", Priority: intPtr(10)}}
+	invalidTask := &pipeline.Task{Name: "invalid", Policy: pipeline.ExecutionPolicy{Retries: intPtr(11), RetryDelay: "48h", Timeout: "1 hour", Pool: "big query", Priority: intPtr(101)}}
+	negativeTask := &pipeline.Task{Name: "negative", Policy: pipeline.ExecutionPolicy{Retries: intPtr(-1), SLA: "-5m"}}
+	slaTask := &pipeline.Task{Name: "sla", Policy: pipeline.ExecutionPolicy{SLA: "30m"}}
+
+	tests := []struct {
+		name string
+		p    *pipeline.Pipeline
+		want []*Issue
+	}{
+		{
+			name: "valid policies pass",
+			p: &pipeline.Pipeline{
+				DefaultPolicy: pipeline.ExecutionPolicy{Retries: intPtr(1), Timeout: "30m"},
+				Tasks:         []*pipeline.Task{validTask, {Name: "empty"}},
+			},
+			want: noIssues,
+		},
+		{
+			name: "invalid values are reported for each task",
+			p: &pipeline.Pipeline{
+				Tasks: []*pipeline.Task{validTask, invalidTask, negativeTask},
+			},
+			want: []*Issue{
+				{Task: invalidTask, Description: "The execution policy is invalid: the retries must be between 0 and 10, got 11"},
+				{Task: invalidTask, Description: "The execution policy is invalid: the priority must be between -100 and 100, got 101"},
+				{Task: invalidTask, Description: "The execution policy is invalid: the pool 'big query' must be made of alphanumeric characters, dashes, dots and underscores"},
+				{Task: invalidTask, Description: "The execution policy is invalid: invalid timeout '1 hour', use a duration such as 30s, 5m or 1h30m"},
+				{Task: negativeTask, Description: "The execution policy is invalid: the retries must be between 0 and 10, got -1"},
+				{Task: negativeTask, Description: "The execution policy is invalid: the sla must be positive, got -5m0s"},
+			},
+		},
+		{
+			name: "the defaults are reported once for the pipeline",
+			p: &pipeline.Pipeline{
+				DefaultPolicy: pipeline.ExecutionPolicy{RetryDelay: "48h"},
+				Tasks:         []*pipeline.Task{{Name: "task1"}, {Name: "task2"}},
+			},
+			want: []*Issue{
+				{Description: "The default policy of the pipeline is invalid: the retry_delay must be at most 24h0m0s, got 48h0m0s"},
+			},
+		},
+		{
+			name: "SLAs shorter than the timeouts are reported, including the inherited timeouts",
+			p: &pipeline.Pipeline{
+				DefaultPolicy: pipeline.ExecutionPolicy{Timeout: "1h"},
+				Tasks:         []*pipeline.Task{slaTask, {Name: "inherited"}},
+			},
+			want: []*Issue{
+				{Task: slaTask, Description: "The execution policy is invalid: the sla (30m0s) is shorter than the timeout (1h0m0s), a single attempt can take longer than the sla"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := EnsureExecutionPoliciesAreValid(tt.p)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnsureTaskPoolsExist(t *testing.T) {
+	t.Parallel()
+
+	definedPool := &pipeline.Task{Name: "task1", Policy: pipeline.ExecutionPolicy{Pool: "bigquery"}}
+	undefinedPool := &pipeline.Task{Name: "task2", Policy: pipeline.ExecutionPolicy{Pool: "snowflake"}}
+	inheritedPool := &pipeline.Task{Name: "task3"}
+	p := &pipeline.Pipeline{
+		DefaultPolicy: pipeline.ExecutionPolicy{Pool: "unknown"},
+		Tasks:         []*pipeline.Task{definedPool, undefinedPool, inheritedPool},
+	}
+
+	got, err := EnsureTaskPoolsExist(map[string]int{"bigquery": 2, "local": 1})(p)
+	require.NoError(t, err)
+	require.Equal(t, []*Issue{
+		{
+			Task:        undefinedPool,
+			Description: "The pool 'snowflake' is not defined in the project config",
+			Context:     []string{"the defined pools are: bigquery, local"},
+		},
+		{
+			Task:        inheritedPool,
+			Description: "The pool 'unknown' is not defined in the project config",
+			Context:     []string{"the defined pools are: bigquery, local"},
+		},
+	}, got)
+
+	got, err = EnsureTaskPoolsExist(map[string]int{"bigquery": 2})(&pipeline.Pipeline{Tasks: []*pipeline.Task{{Name: "no-pool"}}})
+	require.NoError(t, err)
+	require.Equal(t, noIssues, got)
+}
+
+func TestEnsureSensorParametersAreValid(t *testing.T) {
+	t.Parallel()
+
+	withURL := &pipeline.Task{
+		Name:       "task1",
+		Type:       "s3.sensor.key_sensor",
+		Parameters: map[string]string{"bucket_key": "s3://exports/orders/_SUCCESS", "poke_interval": "5m"},
+	}
+	withBucket := &pipeline.Task{
+		Name:       "task2",
+		Type:       "s3.sensor.key_sensor",
+		Parameters: map[string]string{"bucket_key": "orders/_SUCCESS"},
+	}
+	missing := &pipeline.Task{Name: "task3", Type: "s3.sensor.key_sensor"}
+	otherType := &pipeline.Task{Name: "task4", Type: "gcs.sensor.object_sensor_with_prefix"}
+	p := &pipeline.Pipeline{
+		DefaultParameters: map[string]string{"bucket_name": "exports"},
+		Tasks:             []*pipeline.Task{withURL, withBucket, missing, otherType},
+	}
+
+	got, err := EnsureSensorParametersAreValid("s3.sensor.key_sensor")(p)
+	require.NoError(t, err)
+	require.Equal(t, []*Issue{
+		{
+			Task:        withURL,
+			Description: "The sensor parameters are invalid: the parameter 'bucket_name' must not be set when 'bucket_key' is a full s3:// URL",
+		},
+	}, got)
+}
+
+func TestEnsureTaskParametersAreValid(t *testing.T) {
+	t.Parallel()
+
+	valid := &pipeline.Task{
+		Name:       "task1",
+		Type:       "bq.sensor.table",
+		Parameters: map[string]string{"dataset_id": "raw", "table_id": "orders", "timeout": "1h"},
+	}
+	invalid := &pipeline.Task{
+		Name:       "task2",
+		Type:       "bq.sensor.table",
+		Parameters: map[string]string{"table": "orders", "timeout": "1 hour"},
+	}
+	anyParameter := &pipeline.Task{
+		Name:       "task3",
+		Type:       "bq.sql",
+		Parameters: map[string]string{"anything": "goes"},
+	}
+	unknownType := &pipeline.Task{Name: "task4", Type: "some.type", Parameters: map[string]string{"key": "value"}}
+	p := &pipeline.Pipeline{
+		DefaultParameters: map[string]string{"project_id": "analytics", "dataset_id": "staging"},
+		Tasks:             []*pipeline.Task{valid, invalid, anyParameter, unknownType},
+	}
+
+	got, err := EnsureTaskParametersAreValid(tasktype.Builtin())(p)
+	require.NoError(t, err)
+	require.Equal(t, []*Issue{
+		{
+			Task:        invalid,
+			Description: "The task parameters are invalid: the parameter 'table' is not known, the parameters of 'bq.sensor.table' are: project_id, dataset_id, table_id, poke_interval, timeout",
+		},
+		{
+			Task:        invalid,
+			Description: "The task parameters are invalid: the parameter 'table_id' is required",
+		},
+		{
+			Task:        invalid,
+			Description: "The task parameters are invalid: the parameter 'timeout' must be a positive duration such as 30s, 5m or 1h, got '1 hour'",
+		},
+	}, got)
+}
+
 func TestEnsurePipelineHasNoCycles(t *testing.T) {
 	t.Parallel()
 	type args struct {
@@ -878,3 +1399,135 @@ func TestEnsurePipelineHasNoCycles(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureCrossPipelineDependenciesExist(t *testing.T) {
+	t.Parallel()
+
+	ordersClean := &pipeline.Task{Name: "orders_clean"}
+	core := &pipeline.Pipeline{
+		Name:  "core-pipeline",
+		Tasks: []*pipeline.Task{ordersClean},
+	}
+
+	validDependency := &pipeline.Task{
+		Name:      "campaigns",
+		DependsOn: []string{"orders_clean", "core-pipeline:orders_clean"},
+	}
+	missingTask := &pipeline.Task{
+		Name:      "attribution",
+		DependsOn: []string{"core-pipeline:customers"},
+	}
+	missingPipeline := &pipeline.Task{
+		Name:      "spend",
+		DependsOn: []string{"finance-pipeline:invoices"},
+	}
+	marketing := &pipeline.Pipeline{
+		Name:  "marketing-pipeline",
+		Tasks: []*pipeline.Task{validDependency, missingTask, missingPipeline},
+	}
+
+	got, err := EnsureCrossPipelineDependenciesExist([]*pipeline.Pipeline{core, marketing})
+	require.NoError(t, err)
+	require.Equal(t, map[*pipeline.Pipeline][]*Issue{
+		marketing: {
+			{
+				Task:        missingTask,
+				Description: "Dependency 'core-pipeline:customers' does not exist, there is no task named 'customers' in pipeline 'core-pipeline'",
+			},
+			{
+				Task:        missingPipeline,
+				Description: "Dependency 'finance-pipeline:invoices' does not exist, there is no pipeline named 'finance-pipeline'",
+			},
+		},
+	}, got)
+}
+
+func TestEnsurePipelineNamesAreUnique(t *testing.T) {
+	t.Parallel()
+
+	first := &pipeline.Pipeline{Name: "sales", DefinitionFile: pipeline.DefinitionFile{Path: "/repo/sales/pipeline.yml"}}
+	second := &pipeline.Pipeline{Name: "sales", DefinitionFile: pipeline.DefinitionFile{Path: "/repo/sales-copy/pipeline.yml"}}
+	unique := &pipeline.Pipeline{Name: "marketing", DefinitionFile: pipeline.DefinitionFile{Path: "/repo/marketing/pipeline.yml"}}
+	unnamed := &pipeline.Pipeline{DefinitionFile: pipeline.DefinitionFile{Path: "/repo/broken/pipeline.yml"}}
+	otherUnnamed := &pipeline.Pipeline{DefinitionFile: pipeline.DefinitionFile{Path: "/repo/other-broken/pipeline.yml"}}
+
+	got, err := EnsurePipelineNamesAreUnique([]*pipeline.Pipeline{first, unique, second, unnamed, otherUnnamed})
+	require.NoError(t, err)
+	require.Equal(t, map[*pipeline.Pipeline][]*Issue{
+		first: {
+			{
+				Description: "The pipeline name 'sales' is used by other pipelines too, the cross-pipeline dependencies cannot tell them apart",
+				Context:     []string{"Also defined in /repo/sales-copy/pipeline.yml"},
+			},
+		},
+		second: {
+			{
+				Description: "The pipeline name 'sales' is used by other pipelines too, the cross-pipeline dependencies cannot tell them apart",
+				Context:     []string{"Also defined in /repo/sales/pipeline.yml"},
+			},
+		},
+	}, got)
+}
+
+func TestEnsurePipelinesHaveNoCycles(t *testing.T) {
+	t.Parallel()
+
+	core := &pipeline.Pipeline{
+		Name: "core",
+		Tasks: []*pipeline.Task{
+			{Name: "orders"},
+			{Name: "orders_clean", DependsOn: []string{"orders", "marketing:campaigns"}},
+			{Name: "self", DependsOn: []string{"core:self"}},
+		},
+	}
+	marketing := &pipeline.Pipeline{
+		Name: "marketing",
+		Tasks: []*pipeline.Task{
+			{Name: "raw_campaigns"},
+			{Name: "campaigns", DependsOn: []string{"raw_campaigns", "attribution"}},
+			{Name: "attribution", DependsOn: []string{"core:orders_clean"}},
+		},
+	}
+	local := &pipeline.Pipeline{
+		Name: "local",
+		Tasks: []*pipeline.Task{
+			{Name: "a", DependsOn: []string{"b"}},
+			{Name: "b", DependsOn: []string{"a", "core:orders"}},
+		},
+	}
+
+	crossPipelineCycle := []string{
+		"core:orders_clean ➜ marketing:campaigns",
+		"marketing:campaigns ➜ marketing:attribution",
+		"marketing:attribution ➜ core:orders_clean",
+	}
+
+	got, err := EnsurePipelinesHaveNoCycles([]*pipeline.Pipeline{core, marketing, local})
+	require.NoError(t, err)
+	require.Equal(t, map[*pipeline.Pipeline][]*Issue{
+		core: {
+			{
+				Description: pipelinesContainCycle,
+				Context:     []string{"Task `core:self` depends on itself"},
+			},
+			{
+				Description: pipelinesContainCycle,
+				Context:     crossPipelineCycle,
+			},
+		},
+		marketing: {
+			{
+				Description: pipelinesContainCycle,
+				Context:     crossPipelineCycle,
+			},
+		},
+	}, got)
+}
+
+func TestValidTaskTypesCanBeExportedToAirflow(t *testing.T) {
+	t.Parallel()
+
+	for _, taskType := range ValidTaskTypes() {
+		assert.True(t, airflow.IsSupported(taskType), "task type '%s' has no Airflow operator", taskType)
+	}
+}
@@ -0,0 +1,307 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/sqlparser"
+	"go.uber.org/zap"
+)
+
+// SQLCheck returns the problems found in the statements of a single query.
+type SQLCheck func(statements []sqlparser.Statement, dialect sqlparser.Dialect) []string
+
+// SQLSource tells the style rules how to read the queries of a task type.
+type SQLSource struct {
+	Extractor queryExtractor
+	Dialect   sqlparser.Dialect
+}
+
+// SQLStyleRule parses the queries of the SQL tasks and runs a single check on them, which allows enabling the checks
+// one by one.
+type SQLStyleRule struct {
+	Identifier string
+	Check      SQLCheck
+	Sources    map[string]SQLSource
+	Logger     *zap.SugaredLogger
+}
+
+func (r *SQLStyleRule) Name() string {
+	return r.Identifier
+}
+
+func (r *SQLStyleRule) IsTaskScoped() bool {
+	return true
+}
+
+func (r *SQLStyleRule) Validate(ctx context.Context, p *pipeline.Pipeline) ([]*Issue, error) {
+	issues := make([]*Issue, 0)
+	for _, task := range p.Tasks {
+		source, ok := r.Sources[task.Type]
+		if !ok || task.ExecutableFile.Path == "" {
+			continue
+		}
+
+		issues = append(issues, r.validateTask(task, source)...)
+	}
+
+	return issues, nil
+}
+
+func (r *SQLStyleRule) validateTask(task *pipeline.Task, source SQLSource) []*Issue {
+	queries, err := source.Extractor.ExtractQueriesFromFile(task.ExecutableFile.Path)
+	if err != nil {
+		return []*Issue{
+			{
+				Task:        task,
+				Description: fmt.Sprintf("Cannot read executable file '%s': %+v", task.ExecutableFile.Path, err),
+			},
+		}
+	}
+
+	r.Logger.Debugf("checking %d queries in file '%s' for rule '%s'", len(queries), task.ExecutableFile.Path, r.Identifier)
+
+	issues := make([]*Issue, 0)
+	for index, foundQuery := range queries {
+		statements, err := sqlparser.Parse(foundQuery.Query, source.Dialect)
+		if err != nil {
+			issues = append(issues, &Issue{
+				Task:        task,
+				Description: fmt.Sprintf("Could not parse the query at index %d: %s", index, err),
+				Type:        IssueUnvalidated,
+			})
+			continue
+		}
+
+		// the files split into multiple queries point to the query, the others are a single query anyway
+		var issueContext []string
+		if len(queries) > 1 {
+			issueContext = []string{fmt.Sprintf("Found in the query at index %d", index)}
+		}
+
+		for _, problem := range unique(r.Check(statements, source.Dialect)) {
+			issues = append(issues, &Issue{Task: task, Description: problem, Context: issueContext})
+		}
+	}
+
+	return issues
+}
+
+func unique(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+
+		seen[value] = true
+		result = append(result, value)
+	}
+
+	return result
+}
+
+// EnsureNoSelectStar reports the * in the final output of the queries, i.e. the rows returned or written to a table.
+// The CTEs and the subqueries can still use it since their columns are listed by the final select.
+func EnsureNoSelectStar(statements []sqlparser.Statement, dialect sqlparser.Dialect) []string {
+	var problems []string
+	for _, statement := range statements {
+		var query *sqlparser.Query
+		switch s := statement.(type) {
+		case *sqlparser.QueryStatement:
+			query = s.Query
+		case *sqlparser.CreateTable:
+			query = s.Query
+		case *sqlparser.Insert:
+			query = s.Query
+		}
+
+		for _, selectNode := range finalSelects(query) {
+			for _, column := range selectNode.Columns {
+				star, ok := column.Expr.(*sqlparser.Star)
+				if !ok {
+					continue
+				}
+
+				name := "*"
+				if len(star.Qualifier) > 0 {
+					name = strings.Join(star.Qualifier, ".") + ".*"
+				}
+
+				problems = append(problems, fmt.Sprintf("The query selects %s in its final output, list the columns explicitly instead", name))
+			}
+		}
+	}
+
+	return problems
+}
+
+// finalSelects returns the selects that produce the output of the query, which are all the branches of a UNION.
+func finalSelects(query *sqlparser.Query) []*sqlparser.Select {
+	if query == nil {
+		return nil
+	}
+
+	var selects []*sqlparser.Select
+	var collect func(body sqlparser.QueryBody)
+	collect = func(body sqlparser.QueryBody) {
+		switch b := body.(type) {
+		case *sqlparser.Select:
+			selects = append(selects, b)
+		case *sqlparser.SetOperation:
+			collect(b.Left)
+			collect(b.Right)
+		case *sqlparser.Query:
+			collect(b.Body)
+		}
+	}
+	collect(query.Body)
+
+	return selects
+}
+
+// minimumTableNameParts is the number of parts of a fully qualified name, e.g. project.dataset.table in BigQuery and
+// database.schema.table in Snowflake.
+const minimumTableNameParts = 3
+
+// EnsureTableNamesAreQualified reports the tables that are referenced without their project and dataset, or their
+// database and schema. The CTEs, the temporary tables, the names starting with a table alias such as the arrays of
+// BigQuery, and the names with template expressions are skipped, since they are not tables of the warehouse.
+func EnsureTableNamesAreQualified(statements []sqlparser.Statement, dialect sqlparser.Dialect) []string {
+	local := make(map[string]bool)
+	var tables []*sqlparser.TableName
+	for _, statement := range statements {
+		if create, ok := statement.(*sqlparser.CreateTable); ok && create.Temporary {
+			local[strings.ToLower(create.Name.String())] = true
+		}
+
+		sqlparser.Inspect(statement, func(node sqlparser.Node) bool {
+			switch n := node.(type) {
+			case *sqlparser.CommonTableExpression:
+				local[strings.ToLower(n.Name)] = true
+			case *sqlparser.TableName:
+				tables = append(tables, n)
+				if n.Alias != "" {
+					local[strings.ToLower(n.Alias)] = true
+				}
+			case *sqlparser.DerivedTable:
+				if n.Alias != "" {
+					local[strings.ToLower(n.Alias)] = true
+				}
+			case *sqlparser.TableFunction:
+				if n.Alias != "" {
+					local[strings.ToLower(n.Alias)] = true
+				}
+			}
+
+			return true
+		})
+	}
+
+	qualifiers := "the project and the dataset"
+	if dialect == sqlparser.Snowflake {
+		qualifiers = "the database and the schema"
+	}
+
+	var problems []string
+	for _, table := range tables {
+		if len(table.Parts) >= minimumTableNameParts || local[strings.ToLower(table.Parts[0])] || local[strings.ToLower(table.String())] || hasTemplate(table.Parts) {
+			continue
+		}
+
+		problems = append(problems, fmt.Sprintf("The table '%s' is not fully qualified, the name should include %s as well", table, qualifiers))
+	}
+
+	return problems
+}
+
+func hasTemplate(parts []string) bool {
+	for _, part := range parts {
+		if strings.Contains(part, "{{") {
+			return true
+		}
+	}
+
+	return false
+}
+
+var dateLiteral = regexp.MustCompile(`^\d{4}-(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])([ T][\d:.]+)?( ?(Z|UTC|[+-]\d{2}(:?\d{2})?))?$`)
+
+// EnsureNoHardcodedDates reports the date and timestamp literals, the queries should use the template variables such
+// as {{ ds }} instead so that they can be run for any date.
+func EnsureNoHardcodedDates(statements []sqlparser.Statement, dialect sqlparser.Dialect) []string {
+	var problems []string
+	for _, statement := range statements {
+		sqlparser.Inspect(statement, func(node sqlparser.Node) bool {
+			literal, ok := node.(*sqlparser.Literal)
+			if !ok || literal.Kind != sqlparser.StringLiteral {
+				return true
+			}
+
+			// the typed literals are checked by their value as well, e.g. DATE '{{ ds }}' is what the rule suggests
+			if !strings.Contains(literal.Value, "{{") && dateLiteral.MatchString(literal.Value) {
+				problems = append(problems, fmt.Sprintf("The query has the hard-coded date '%s', use a template variable such as {{ ds }} instead", literal.Value))
+			}
+
+			return true
+		})
+	}
+
+	return problems
+}
+
+// EnsureCTASOrderByHasLimit reports the tables created from a query that is sorted without a limit, the tables do not
+// keep the order of the rows, so the sorting is only a waste.
+func EnsureCTASOrderByHasLimit(statements []sqlparser.Statement, dialect sqlparser.Dialect) []string {
+	var problems []string
+	for _, statement := range statements {
+		create, ok := statement.(*sqlparser.CreateTable)
+		if !ok || create.View || create.Query == nil {
+			continue
+		}
+
+		// the query of CREATE TABLE ... AS (SELECT ...) is wrapped in parentheses, the sorting is on the inner query
+		query := create.Query
+		for len(query.OrderBy) == 0 && query.Limit == nil {
+			inner, ok := query.Body.(*sqlparser.Query)
+			if !ok {
+				break
+			}
+
+			query = inner
+		}
+
+		if len(query.OrderBy) > 0 && query.Limit == nil {
+			problems = append(problems, fmt.Sprintf("The table '%s' is created from a query with ORDER BY but without LIMIT, the order of the rows is not kept in the table", create.Name))
+		}
+	}
+
+	return problems
+}
+
+// EnsureDeletesHaveWhere reports the statements that remove all the rows of a table, which are the DELETE statements
+// without a condition and the TRUNCATE statements.
+func EnsureDeletesHaveWhere(statements []sqlparser.Statement, dialect sqlparser.Dialect) []string {
+	var problems []string
+	for _, statement := range statements {
+		switch s := statement.(type) {
+		case *sqlparser.Delete:
+			if s.Where == nil {
+				problems = append(problems, fmt.Sprintf("The DELETE statement for the table '%s' does not have a WHERE clause", s.Table))
+				continue
+			}
+
+			if literal, ok := s.Where.(*sqlparser.Literal); ok && literal.Kind == sqlparser.BoolLiteral && literal.Value == "TRUE" {
+				problems = append(problems, fmt.Sprintf("The DELETE statement for the table '%s' has a WHERE clause that matches all the rows", s.Table))
+			}
+		case *sqlparser.Truncate:
+			problems = append(problems, fmt.Sprintf("The TRUNCATE statement removes all the rows of the table '%s', use DELETE with a WHERE clause instead", s.Table))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,176 @@
+package lint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/query"
+	"github.com/datablast-analytics/blast-cli/pkg/sqlparser"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSQLChecks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		check   SQLCheck
+		sql     string
+		dialect sqlparser.Dialect
+		want    []string
+	}{
+		{
+			name:  "select star in the final output",
+			check: EnsureNoSelectStar,
+			sql: `WITH raw AS (SELECT * FROM p.d.events)
+SELECT r.*, (SELECT COUNT(*) FROM p.d.users) AS users FROM raw r
+UNION ALL
+SELECT * EXCEPT (id) FROM p.d.archive`,
+			want: []string{
+				"The query selects r.* in its final output, list the columns explicitly instead",
+				"The query selects * in its final output, list the columns explicitly instead",
+			},
+		},
+		{
+			name:  "select star in the created tables and the inserts",
+			check: EnsureNoSelectStar,
+			sql:   "CREATE TABLE p.d.copy AS SELECT * FROM p.d.orders; INSERT INTO p.d.copy SELECT id, name FROM p.d.orders",
+			want:  []string{"The query selects * in its final output, list the columns explicitly instead"},
+		},
+		{
+			name:  "select star only in the ctes and the subqueries",
+			check: EnsureNoSelectStar,
+			sql:   "WITH raw AS (SELECT * FROM p.d.events) SELECT id FROM (SELECT * FROM raw)",
+		},
+		{
+			name:  "unqualified bigquery tables",
+			check: EnsureTableNamesAreQualified,
+			sql: `CREATE TEMP TABLE staging AS SELECT id FROM sales.orders;
+WITH recent AS (SELECT id FROM ` + "`project.sales.orders`" + `)
+SELECT r.id, item FROM recent r, r.items AS item
+JOIN staging s USING (id)
+JOIN {{ ref('customers') }} c ON c.id = r.id
+JOIN orders o ON o.id = r.id`,
+			want: []string{
+				"The table 'sales.orders' is not fully qualified, the name should include the project and the dataset as well",
+				"The table 'orders' is not fully qualified, the name should include the project and the dataset as well",
+			},
+		},
+		{
+			name:    "unqualified snowflake tables",
+			check:   EnsureTableNamesAreQualified,
+			sql:     `DELETE FROM analytics.orders WHERE id IN (SELECT id FROM "Raw"."Sales"."Orders")`,
+			dialect: sqlparser.Snowflake,
+			want:    []string{"The table 'analytics.orders' is not fully qualified, the name should include the database and the schema as well"},
+		},
+		{
+			name:  "hard-coded dates",
+			check: EnsureNoHardcodedDates,
+			sql: `SELECT id FROM p.d.orders
+WHERE dt = '2022-01-31' AND created_at > TIMESTAMP '2022-01-01 10:00:00' AND code = '2022-99' AND day = '{{ ds }}'
+AND updated_at > TIMESTAMP '2022-01-01T10:00:00Z' AND partition_dt = DATE '{{ ds }}' AND tz = DATETIME 'now'`,
+			want: []string{
+				"The query has the hard-coded date '2022-01-31', use a template variable such as {{ ds }} instead",
+				"The query has the hard-coded date '2022-01-01 10:00:00', use a template variable such as {{ ds }} instead",
+				"The query has the hard-coded date '2022-01-01T10:00:00Z', use a template variable such as {{ ds }} instead",
+			},
+		},
+		{
+			name:  "order by without limit in the created tables",
+			check: EnsureCTASOrderByHasLimit,
+			sql: `CREATE TABLE p.d.sorted AS SELECT id FROM p.d.orders ORDER BY id;
+CREATE TABLE p.d.top AS SELECT id FROM p.d.orders ORDER BY id LIMIT 10;
+CREATE VIEW p.d.sorted_view AS SELECT id FROM p.d.orders ORDER BY id;
+SELECT id FROM p.d.orders ORDER BY id;
+CREATE TABLE p.d.wrapped AS (SELECT * FROM p.d.orders ORDER BY 1);
+CREATE TABLE p.d.wrapped_top AS (SELECT * FROM p.d.orders ORDER BY 1) LIMIT 10`,
+			want: []string{
+				"The table 'p.d.sorted' is created from a query with ORDER BY but without LIMIT, the order of the rows is not kept in the table",
+				"The table 'p.d.wrapped' is created from a query with ORDER BY but without LIMIT, the order of the rows is not kept in the table",
+			},
+		},
+		{
+			name:  "deletes without conditions",
+			check: EnsureDeletesHaveWhere,
+			sql: `DELETE FROM p.d.orders WHERE dt = '{{ ds }}';
+DELETE p.d.orders WHERE TRUE;
+DELETE FROM p.d.customers;
+TRUNCATE TABLE p.d.staging`,
+			want: []string{
+				"The DELETE statement for the table 'p.d.orders' has a WHERE clause that matches all the rows",
+				"The DELETE statement for the table 'p.d.customers' does not have a WHERE clause",
+				"The TRUNCATE statement removes all the rows of the table 'p.d.staging', use DELETE with a WHERE clause instead",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			statements, err := sqlparser.Parse(tt.sql, tt.dialect)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tt.check(statements, tt.dialect))
+		})
+	}
+}
+
+func TestSQLStyleRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/pipeline/tasks/orders.sql", []byte("-- @blast.name: orders\nSELECT * FROM p.d.orders;\nSELECT *, id FROM p.d.orders"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/pipeline/tasks/broken.sql", []byte("SELECT 'abc"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/pipeline/tasks/clean.sql", []byte("SELECT id FROM p.d.orders"), 0o644))
+
+	orders := &pipeline.Task{Name: "orders", Type: "sf.sql", ExecutableFile: pipeline.ExecutableFile{Path: "/pipeline/tasks/orders.sql"}}
+	broken := &pipeline.Task{Name: "broken", Type: "sf.sql", ExecutableFile: pipeline.ExecutableFile{Path: "/pipeline/tasks/broken.sql"}}
+	missing := &pipeline.Task{Name: "missing", Type: "sf.sql", ExecutableFile: pipeline.ExecutableFile{Path: "/pipeline/tasks/missing.sql"}}
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Task{
+			orders,
+			broken,
+			missing,
+			{Name: "clean", Type: "sf.sql", ExecutableFile: pipeline.ExecutableFile{Path: "/pipeline/tasks/clean.sql"}},
+			{Name: "script", Type: "bash", ExecutableFile: pipeline.ExecutableFile{Path: "/pipeline/tasks/orders.sql"}},
+		},
+	}
+
+	rule := &SQLStyleRule{
+		Identifier: "sql-no-select-star",
+		Check:      EnsureNoSelectStar,
+		Sources: map[string]SQLSource{
+			"sf.sql": {
+				Extractor: &query.FileQuerySplitterExtractor{Fs: fs, Renderer: query.Renderer{}},
+				Dialect:   sqlparser.Snowflake,
+			},
+		},
+		Logger: zap.NewNop().Sugar(),
+	}
+
+	issues, err := rule.Validate(context.Background(), p)
+	require.NoError(t, err)
+	require.Len(t, issues, 4)
+
+	assert.Equal(t, &Issue{
+		Task:        orders,
+		Description: "The query selects * in its final output, list the columns explicitly instead",
+		Context:     []string{"Found in the query at index 0"},
+	}, issues[0])
+	assert.Equal(t, &Issue{
+		Task:        orders,
+		Description: "The query selects * in its final output, list the columns explicitly instead",
+		Context:     []string{"Found in the query at index 1"},
+	}, issues[1])
+	assert.Equal(t, &Issue{
+		Task:        broken,
+		Description: "Could not parse the query at index 0: line 1: the string is not closed",
+		Type:        IssueUnvalidated,
+	}, issues[2])
+	assert.Equal(t, missing, issues[3].Task)
+	assert.Contains(t, issues[3].Description, "Cannot read executable file '/pipeline/tasks/missing.sql'")
+}
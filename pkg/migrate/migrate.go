@@ -0,0 +1,196 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	definitionFileName = "task.yml"
+	folderPermissions  = 0o755
+)
+
+type File struct {
+	Path    string
+	Content []byte
+	Mode    os.FileMode
+}
+
+// Change is the set of file operations that converts a single task, nothing is touched until it is applied.
+type Change struct {
+	Task   *pipeline.Task
+	Write  []File
+	Remove []string
+}
+
+// Migrator converts the tasks between the comment annotations and the `task.yml` definitions.
+type Migrator struct {
+	fs           afero.Fs
+	tasksDirName string
+	syntaxes     pipeline.CommentSyntaxes
+}
+
+func NewMigrator(fs afero.Fs, tasksDirName string) *Migrator {
+	return &Migrator{
+		fs:           fs,
+		tasksDirName: tasksDirName,
+		syntaxes:     pipeline.BuiltinCommentSyntaxes(),
+	}
+}
+
+// SetCommentSyntaxes replaces the built-in comment syntaxes with the given ones, e.g. to migrate the tasks of the
+// custom types as well.
+func (m *Migrator) SetCommentSyntaxes(syntaxes pipeline.CommentSyntaxes) {
+	m.syntaxes = syntaxes
+}
+
+// ParseFormat accepts both the singular and the plural form for the comments, e.g. `--to comments`.
+func ParseFormat(format string) (pipeline.TaskDefinitionType, error) {
+	switch strings.ToLower(format) {
+	case string(pipeline.YamlTask), "yml":
+		return pipeline.YamlTask, nil
+	case string(pipeline.CommentTask), "comments":
+		return pipeline.CommentTask, nil
+	}
+
+	return "", errors.Errorf("unknown format '%s', it must be either 'yaml' or 'comments'", format)
+}
+
+// Plan returns the change that converts the task to the given format, or nil if the task is defined that way
+// already. The tasks that cannot be represented in the target format are refused with an error.
+func (m *Migrator) Plan(p *pipeline.Pipeline, t *pipeline.Task, to pipeline.TaskDefinitionType) (*Change, error) {
+	if t.DefinitionFile.Type == to {
+		return nil, nil
+	}
+
+	switch to {
+	case pipeline.YamlTask:
+		return m.planYaml(t)
+	case pipeline.CommentTask:
+		return m.planComments(p, t)
+	}
+
+	return nil, errors.Errorf("unknown format '%s'", to)
+}
+
+// planYaml moves the file into a folder named after it, e.g. `tasks/orders.sql` becomes `tasks/orders/orders.sql`
+// with a `tasks/orders/task.yml` next to it, since a folder can hold only one definition.
+func (m *Migrator) planYaml(t *pipeline.Task) (*Change, error) {
+	sourcePath := t.DefinitionFile.Path
+	fileName := filepath.Base(sourcePath)
+	taskDir := filepath.Join(filepath.Dir(sourcePath), strings.TrimSuffix(fileName, filepath.Ext(fileName)))
+	if exists, _ := afero.Exists(m.fs, taskDir); exists {
+		return nil, errors.Errorf("cannot move the task into '%s', the path already exists", taskDir)
+	}
+
+	info, err := m.fs.Stat(sourcePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the task file '%s'", sourcePath)
+	}
+
+	content, err := afero.ReadFile(m.fs, sourcePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the task file '%s'", sourcePath)
+	}
+
+	body, err := m.syntaxes.StripComments(content, filepath.Ext(sourcePath))
+	if err != nil {
+		return nil, err
+	}
+
+	moved := *t
+	moved.ExecutableFile = pipeline.ExecutableFile{
+		Name: fileName,
+		Path: filepath.Join(taskDir, fileName),
+	}
+
+	definition, err := pipeline.MarshalYaml(&moved, taskDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Change{
+		Task: t,
+		Write: []File{
+			{Path: filepath.Join(taskDir, definitionFileName), Content: definition, Mode: 0o644},
+			{Path: moved.ExecutableFile.Path, Content: body, Mode: info.Mode().Perm()},
+		},
+		Remove: []string{sourcePath},
+	}, nil
+}
+
+// planComments adds the annotations to the run file in place and removes the `task.yml` file.
+func (m *Migrator) planComments(p *pipeline.Pipeline, t *pipeline.Task) (*Change, error) {
+	runFile := t.ExecutableFile.Path
+	if runFile == "" {
+		return nil, errors.New("the task has no run file to hold the comments")
+	}
+
+	extension := filepath.Ext(runFile)
+	if !m.syntaxes.SupportsComments(extension) {
+		return nil, errors.Errorf("the run file '%s' cannot be annotated with comments, the '%s' files are not supported", t.ExecutableFile.Name, extension)
+	}
+
+	// the comment-defined tasks are only looked up in the tasks folder
+	tasksDir := filepath.Join(filepath.Dir(p.DefinitionFile.Path), m.tasksDirName)
+	if relative, err := filepath.Rel(tasksDir, runFile); err != nil || strings.HasPrefix(relative, "..") {
+		return nil, errors.Errorf("the run file '%s' is outside of the tasks folder", runFile)
+	}
+
+	for _, other := range p.Tasks {
+		if other != t && other.ExecutableFile.Path == runFile {
+			return nil, errors.Errorf("the run file '%s' is shared with the task '%s'", t.ExecutableFile.Name, other.Name)
+		}
+	}
+
+	info, err := m.fs.Stat(runFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the run file '%s'", runFile)
+	}
+
+	content, err := afero.ReadFile(m.fs, runFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the run file '%s'", runFile)
+	}
+
+	if m.syntaxes.HasComments(content, extension) {
+		return nil, errors.Errorf("the run file '%s' already contains annotations", t.ExecutableFile.Name)
+	}
+
+	rows, err := m.syntaxes.MarshalComments(t, extension)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Change{
+		Task:   t,
+		Write:  []File{{Path: runFile, Content: pipeline.AddComments(content, rows), Mode: info.Mode().Perm()}},
+		Remove: []string{t.DefinitionFile.Path},
+	}, nil
+}
+
+// Apply writes the new files before removing the old ones, so that an interrupted change never loses a task.
+func (m *Migrator) Apply(change *Change) error {
+	for _, file := range change.Write {
+		if err := m.fs.MkdirAll(filepath.Dir(file.Path), folderPermissions); err != nil {
+			return errors.Wrapf(err, "failed to create the folder for '%s'", file.Path)
+		}
+
+		if err := afero.WriteFile(m.fs, file.Path, file.Content, file.Mode); err != nil {
+			return errors.Wrapf(err, "failed to write the file '%s'", file.Path)
+		}
+	}
+
+	for _, path := range change.Remove {
+		if err := m.fs.Remove(path); err != nil {
+			return errors.Wrapf(err, "failed to remove the file '%s'", path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPipeline(tasks ...*pipeline.Task) *pipeline.Pipeline {
+	p := &pipeline.Pipeline{
+		Name:           "sales",
+		DefinitionFile: pipeline.DefinitionFile{Name: "pipeline.yml", Path: "/repo/sales/pipeline.yml"},
+		Tasks:          tasks,
+	}
+	for _, task := range tasks {
+		task.Pipeline = p
+	}
+
+	return p
+}
+
+func commentTask(path string) *pipeline.Task {
+	return &pipeline.Task{
+		Name:           "orders",
+		Type:           "bq.sql",
+		DependsOn:      []string{"customers"},
+		ExecutableFile: pipeline.ExecutableFile{Name: filepath.Base(path), Path: path},
+		DefinitionFile: pipeline.DefinitionFile{Name: filepath.Base(path), Path: path, Type: pipeline.CommentTask},
+	}
+}
+
+func yamlTask(dir, runFile string) *pipeline.Task {
+	return &pipeline.Task{
+		Name:           "orders",
+		Type:           "bq.sql",
+		Parameters:     map[string]string{"param1": "value1"},
+		ExecutableFile: pipeline.ExecutableFile{Name: filepath.Base(runFile), Path: runFile},
+		DefinitionFile: pipeline.DefinitionFile{Name: definitionFileName, Path: filepath.Join(dir, definitionFileName), Type: pipeline.YamlTask},
+	}
+}
+
+func TestMigrator_ToYaml(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/repo/sales/tasks/orders.sql", []byte("-- @blast.name: orders\n-- @blast.type: bq.sql\n-- @blast.depends: customers\n\nSELECT 1\n"), 0o644))
+
+	task := commentTask("/repo/sales/tasks/orders.sql")
+	m := NewMigrator(fs, "tasks")
+	change, err := m.Plan(newPipeline(task), task, pipeline.YamlTask)
+	require.NoError(t, err)
+	require.NoError(t, m.Apply(change))
+
+	assertFile(t, fs, "/repo/sales/tasks/orders/task.yml", "name: orders\ntype: bq.sql\nrun: orders.sql\ndepends:\n  - customers\n")
+	assertFile(t, fs, "/repo/sales/tasks/orders/orders.sql", "SELECT 1\n")
+
+	exists, err := afero.Exists(fs, "/repo/sales/tasks/orders.sql")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestMigrator_ToComments(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/repo/sales/tasks/orders/task.yml", []byte("name: orders\ntype: bq.sql\nrun: query.sql\n"), 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/repo/sales/tasks/orders/query.sql", []byte("SELECT 1\n"), 0o644))
+
+	task := yamlTask("/repo/sales/tasks/orders", "/repo/sales/tasks/orders/query.sql")
+	m := NewMigrator(fs, "tasks")
+	change, err := m.Plan(newPipeline(task), task, pipeline.CommentTask)
+	require.NoError(t, err)
+	require.NoError(t, m.Apply(change))
+
+	assertFile(t, fs, "/repo/sales/tasks/orders/query.sql", "-- @blast.name: orders\n-- @blast.type: bq.sql\n-- @blast.parameters.param1: value1\n\nSELECT 1\n")
+
+	exists, err := afero.Exists(fs, "/repo/sales/tasks/orders/task.yml")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestMigrator_PlanSkipsTasksInTheTargetFormat(t *testing.T) {
+	t.Parallel()
+
+	task := commentTask("/repo/sales/tasks/orders.sql")
+	change, err := NewMigrator(afero.NewMemMapFs(), "tasks").Plan(newPipeline(task), task, pipeline.CommentTask)
+	require.NoError(t, err)
+	assert.Nil(t, change)
+}
+
+func TestMigrator_PlanRefusesTasksThatCannotBeConverted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		setup func() (*pipeline.Pipeline, *pipeline.Task)
+		to    pipeline.TaskDefinitionType
+	}{
+		{
+			name: "the target folder exists",
+			files: map[string]string{
+				"/repo/sales/tasks/orders.sql":        "-- @blast.name: orders\nSELECT 1\n",
+				"/repo/sales/tasks/orders/helper.sql": "SELECT 2\n",
+			},
+			setup: func() (*pipeline.Pipeline, *pipeline.Task) {
+				task := commentTask("/repo/sales/tasks/orders.sql")
+				return newPipeline(task), task
+			},
+			to: pipeline.YamlTask,
+		},
+		{
+			name: "the task has no run file",
+			files: map[string]string{
+				"/repo/sales/tasks/orders/task.yml": "name: orders\ntype: bq.sensor.table\n",
+			},
+			setup: func() (*pipeline.Pipeline, *pipeline.Task) {
+				task := yamlTask("/repo/sales/tasks/orders", "")
+				return newPipeline(task), task
+			},
+			to: pipeline.CommentTask,
+		},
+		{
+			name: "the run file does not support comments",
+			files: map[string]string{
+				"/repo/sales/tasks/hello/task.yml": "name: hello\ntype: bash\nrun: hello.rb\n",
+				"/repo/sales/tasks/hello/hello.rb": "puts 'hello'\n",
+			},
+			setup: func() (*pipeline.Pipeline, *pipeline.Task) {
+				task := yamlTask("/repo/sales/tasks/hello", "/repo/sales/tasks/hello/hello.rb")
+				return newPipeline(task), task
+			},
+			to: pipeline.CommentTask,
+		},
+		{
+			name: "the run file is outside of the tasks folder",
+			files: map[string]string{
+				"/repo/sales/tasks/orders/task.yml": "name: orders\ntype: bq.sql\nrun: ../../queries/orders.sql\n",
+				"/repo/sales/queries/orders.sql":    "SELECT 1\n",
+			},
+			setup: func() (*pipeline.Pipeline, *pipeline.Task) {
+				task := yamlTask("/repo/sales/tasks/orders", "/repo/sales/queries/orders.sql")
+				return newPipeline(task), task
+			},
+			to: pipeline.CommentTask,
+		},
+		{
+			name: "the run file is shared with another task",
+			files: map[string]string{
+				"/repo/sales/tasks/orders/task.yml":  "name: orders\ntype: bq.sql\nrun: ../query.sql\n",
+				"/repo/sales/tasks/refunds/task.yml": "name: refunds\ntype: bq.sql\nrun: ../query.sql\n",
+				"/repo/sales/tasks/query.sql":        "SELECT 1\n",
+			},
+			setup: func() (*pipeline.Pipeline, *pipeline.Task) {
+				task := yamlTask("/repo/sales/tasks/orders", "/repo/sales/tasks/query.sql")
+				other := yamlTask("/repo/sales/tasks/refunds", "/repo/sales/tasks/query.sql")
+				other.Name = "refunds"
+				return newPipeline(task, other), task
+			},
+			to: pipeline.CommentTask,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			for path, content := range tt.files {
+				require.NoError(t, afero.WriteFile(fs, path, []byte(content), 0o644))
+			}
+
+			p, task := tt.setup()
+			change, err := NewMigrator(fs, "tasks").Plan(p, task, tt.to)
+			require.Error(t, err)
+			assert.Nil(t, change)
+		})
+	}
+}
+
+func TestMigrator_RoundTripKeepsTheTasks(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"pipeline.yml":               "name: sales\nschedule: \"@daily\"\n",
+		"tasks/customers.sql":        "-- @blast.name: customers\n-- @blast.description: all the customers: active and churned\n-- @blast.type: bq.sql\n-- @blast.parameters.source: gs://bucket/customers\n\nSELECT 1\n",
+		"tasks/export.py":            "#!/usr/bin/env python3\n# @blast.name: export\n# @blast.type: python\n# @blast.depends: customers, orders\n# @blast.connections.gcpConnectionId: gcp\n\nprint('hello world')\n",
+		"tasks/orders/task.yml":      "name: orders\ntype: sf.sql\nrun: query.sql\ndepends:\n  - customers\n  - raw:orders\n",
+		"tasks/orders/query.sql":     "SELECT 2\n",
+		"tasks/cleanup/task.yml":     "name: cleanup\ntype: bash\nrun: run.sh\ndepends:\n  - export\n",
+		"tasks/cleanup/run.sh":       "echo cleanup\n",
+		"tasks/wait/task.yml":        "name: wait\ntype: bq.sensor.table\nparameters:\n  table: project.dataset.table\n",
+		"tasks/orders/unrelated.txt": "not a task",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	builder := pipeline.NewBuilder(pipeline.BuilderConfig{
+		PipelineFileName:   "pipeline.yml",
+		TasksDirectoryName: "tasks",
+		TasksFileName:      definitionFileName,
+	}, pipeline.CreateTaskFromYamlDefinition, pipeline.CreateTaskFromFileComments)
+
+	original, err := builder.CreatePipelineFromPath(root)
+	require.NoError(t, err)
+	require.Len(t, original.Tasks, 5)
+
+	m := NewMigrator(afero.NewOsFs(), "tasks")
+	migrate := func(to pipeline.TaskDefinitionType) (*pipeline.Pipeline, []string) {
+		p, err := builder.CreatePipelineFromPath(root)
+		require.NoError(t, err)
+
+		refused := make([]string, 0)
+		for _, task := range p.Tasks {
+			change, err := m.Plan(p, task, to)
+			if err != nil {
+				refused = append(refused, task.Name)
+				continue
+			}
+
+			if change != nil {
+				require.NoError(t, m.Apply(change))
+			}
+		}
+
+		migrated, err := builder.CreatePipelineFromPath(root)
+		require.NoError(t, err)
+		sort.Strings(refused)
+
+		return migrated, refused
+	}
+
+	migrated, refused := migrate(pipeline.YamlTask)
+	assert.Empty(t, refused)
+	assertSameTasks(t, original, migrated)
+	for _, task := range migrated.Tasks {
+		assert.Equal(t, pipeline.YamlTask, task.DefinitionFile.Type, task.Name)
+	}
+
+	migrated, refused = migrate(pipeline.CommentTask)
+	assert.Equal(t, []string{"wait"}, refused)
+	assertSameTasks(t, original, migrated)
+
+	content, err := os.ReadFile(filepath.Join(root, "tasks/export/export.py"))
+	require.NoError(t, err)
+	assert.Equal(t, "#!/usr/bin/env python3\n# @blast.name: export\n# @blast.type: python\n# @blast.depends: customers, orders\n# @blast.connections.gcpConnectionId: gcp\n\nprint('hello world')\n", string(content))
+}
+
+func assertSameTasks(t *testing.T, want, got *pipeline.Pipeline) {
+	t.Helper()
+
+	require.Len(t, got.Tasks, len(want.Tasks))
+	for _, expected := range want.Tasks {
+		task := got.GetTaskByName(expected.Name)
+		require.NotNil(t, task, "task '%s' is missing", expected.Name)
+
+		assert.Equal(t, expected.Description, task.Description, expected.Name)
+		assert.Equal(t, expected.Type, task.Type, expected.Name)
+		if len(expected.DependsOn) == 0 {
+			assert.Empty(t, task.DependsOn, expected.Name)
+		} else {
+			assert.Equal(t, expected.DependsOn, task.DependsOn, expected.Name)
+		}
+		assert.Equal(t, len(expected.Parameters), len(task.Parameters), expected.Name)
+		for key, value := range expected.Parameters {
+			assert.Equal(t, value, task.Parameters[key], expected.Name)
+		}
+		assert.Equal(t, len(expected.Connections), len(task.Connections), expected.Name)
+		for key, value := range expected.Connections {
+			assert.Equal(t, value, task.Connections[key], expected.Name)
+		}
+		assert.Equal(t, expected.ExecutableFile.Name, task.ExecutableFile.Name, expected.Name)
+	}
+}
+
+func assertFile(t *testing.T, fs afero.Fs, path, want string) {
+	t.Helper()
+
+	content, err := afero.ReadFile(fs, path)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(content))
+}
@@ -5,30 +5,126 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/datablast-analytics/blast-cli/pkg/jsonschema"
 	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 )
 
+// ReadYaml reads the file strictly, the unknown and the duplicate keys are errors.
 func ReadYaml(path string, out interface{}) error {
 	buf, err := ioutil.ReadFile(path)
 	if err != nil {
 		return errors.Wrapf(err, "failed to read file %s", path)
 	}
 
-	err = yaml.Unmarshal(buf, out)
+	err = DecodeYaml(buf, out)
 	if err != nil {
-		return errors.Wrapf(err, "cannot read the pipeline definition at '%s'", path)
+		return errors.Wrapf(err, "cannot read the YAML file at '%s'", path)
+	}
+
+	return nil
+}
+
+// ReadYamlLenient reads the file the same way as ReadYaml, except that the unknown and the duplicate keys do not stop
+// the decoding, they are returned as the violations instead.
+func ReadYamlLenient(path string, out interface{}) (jsonschema.Violations, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file %s", path)
+	}
+
+	violations, err := DecodeYamlLenient(buf, out)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot read the YAML file at '%s'", path)
+	}
+
+	return violations, nil
+}
+
+// DecodeYaml decodes the content into the given value after checking it against the schema generated from the type
+// of the value, which rejects the unknown keys that yaml.v3 would silently ignore.
+func DecodeYaml(content []byte, out interface{}) error {
+	violations, err := DecodeYamlLenient(content, out)
+	if err != nil {
+		return err
+	}
+
+	if len(violations) > 0 {
+		return violations
+	}
+
+	return nil
+}
+
+// DecodeYamlLenient decodes the content even if it has unknown or duplicate keys, which are returned as the
+// violations. The unknown keys are ignored and the last value of the duplicate keys is used.
+//
+// The unknown keys are found with the schema rather than with yaml.Decoder.KnownFields: the known fields can only be
+// checked when decoding the raw content, while the duplicate keys are dropped from the parsed nodes before decoding,
+// and the decoder errors have neither the path of the key nor a suggestion for the typo.
+func DecodeYamlLenient(content []byte, out interface{}) (jsonschema.Violations, error) {
+	var document yaml.Node
+	err := yaml.Unmarshal(content, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	var recoverable, invalid jsonschema.Violations
+	for _, violation := range jsonschema.Validate(jsonschema.Reflect(out), &document) {
+		if violation.IsRecoverable() {
+			recoverable = append(recoverable, violation)
+		} else {
+			invalid = append(invalid, violation)
+		}
+	}
+
+	if len(invalid) > 0 {
+		return nil, invalid
+	}
+
+	removeDuplicateKeys(&document)
+	err = document.Decode(out)
+	if err != nil && len(document.Content) > 0 {
+		return nil, err
 	}
 
 	validate := validator.New()
 
 	err = validate.Struct(out)
 	if err != nil {
-		return errors.Wrapf(err, "cannot validate the YAML file at '%s'", path)
+		return nil, errors.Wrap(err, "the values are not valid")
 	}
 
-	return nil
+	return recoverable, nil
+}
+
+// removeDuplicateKeys keeps only the last value of the keys that are defined multiple times in the same mapping,
+// since yaml.v3 refuses to decode them.
+func removeDuplicateKeys(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		last := make(map[string]int, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Tag != "!!merge" {
+				last[node.Content[i].Value] = i
+			}
+		}
+
+		content := make([]*yaml.Node, 0, len(node.Content))
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Tag != "!!merge" && last[key.Value] != i {
+				continue
+			}
+
+			content = append(content, key, node.Content[i+1])
+		}
+		node.Content = content
+	}
+
+	for _, child := range node.Content {
+		removeDuplicateKeys(child)
+	}
 }
 
 // ExcludeSubItemsInDirectoryContainingFile cleans up the list to remove sub-paths that are in the same directory as
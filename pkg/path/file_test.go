@@ -1,9 +1,13 @@
 package path
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/datablast-analytics/blast-cli/pkg/jsonschema"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 type parents struct {
@@ -70,6 +74,14 @@ func Test_readYamlFileFromPath(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unknown keys are rejected",
+			args: args{
+				path: "testdata/yamlreader/unknown-key.yml",
+				out:  &exampleData{},
+			},
+			wantErr: true,
+		},
 		{
 			name: "file does not exist",
 			args: args{
@@ -102,6 +114,121 @@ func Test_readYamlFileFromPath(t *testing.T) {
 	}
 }
 
+func TestReadYaml_UnknownKeysAreReportedWithTheirLine(t *testing.T) {
+	t.Parallel()
+
+	err := ReadYaml("testdata/yamlreader/unknown-key.yml", &exampleData{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 5: unknown key `skill`, did you mean `skills`?")
+}
+
+func TestReadYamlLenient(t *testing.T) {
+	t.Parallel()
+
+	var got exampleData
+	violations, err := ReadYamlLenient("testdata/yamlreader/duplicate-key.yml", &got)
+	require.NoError(t, err)
+
+	require.Equal(t, exampleData{
+		Name:   "jane",
+		Middle: "james",
+		Age:    30,
+		Skills: []string{"go"},
+		Family: family{Parents: parents{FirstParent: "papa"}},
+	}, got)
+
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, violation.Error())
+	}
+	require.Equal(t, []string{
+		"line 6: duplicate key `skills`, it is already defined on line 4 and only the last value is used",
+		"line 11: duplicate key `family.parents.parent1`, it is already defined on line 10 and only the last value is used",
+	}, messages)
+
+	err = ReadYaml("testdata/yamlreader/duplicate-key.yml", &exampleData{})
+	require.Error(t, err)
+}
+
+type inlined struct {
+	Team string `yaml:"team"`
+}
+
+// decoderRules uses the struct features that yaml.v3 handles specially when it decides which keys are known.
+type decoderRules struct {
+	inlined  `yaml:",inline"`
+	Name     string `yaml:"name"`
+	Untagged string
+	Skipped  string            `yaml:"-"`
+	Labels   map[string]string `yaml:"labels"`
+	Child    *parents          `yaml:"child"`
+	List     []parents         `yaml:"list"`
+}
+
+func TestDecodeYamlLenient_MatchesTheKnownFieldsOfTheDecoder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		content     string
+		wantUnknown bool
+	}{
+		{
+			name:    "all the keys are known",
+			content: "team: data\nname: jane\nuntagged: x\nlabels: {any: key}\nchild: {parent1: papa}\nlist: [{parent2: mama}]\n",
+		},
+		{
+			name:        "skipped fields",
+			content:     "skipped: x\n",
+			wantUnknown: true,
+		},
+		{
+			name:        "the field names are not keys",
+			content:     "Untagged: x\n",
+			wantUnknown: true,
+		},
+		{
+			name:        "inlined structs are not keys",
+			content:     "inlined: {team: data}\n",
+			wantUnknown: true,
+		},
+		{
+			name:        "pointers to structs",
+			content:     "child: {parent3: x}\n",
+			wantUnknown: true,
+		},
+		{
+			name:        "lists of structs",
+			content:     "list: [{parent1: x}, {nope: 1}]\n",
+			wantUnknown: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			decoder := yaml.NewDecoder(strings.NewReader(tt.content))
+			decoder.KnownFields(true)
+			var strict decoderRules
+			strictErr := decoder.Decode(&strict)
+
+			var lenient decoderRules
+			violations, err := DecodeYamlLenient([]byte(tt.content), &lenient)
+			require.NoError(t, err)
+
+			hasUnknown := false
+			for _, violation := range violations {
+				hasUnknown = hasUnknown || violation.Kind == jsonschema.ViolationUnknownKey
+			}
+
+			assert.Equal(t, tt.wantUnknown, strictErr != nil, "the decoder")
+			assert.Equal(t, tt.wantUnknown, hasUnknown, "the schema")
+			assert.Equal(t, strict, lenient)
+		})
+	}
+}
+
 func TestExcludeItemsInDirectoryContainingFile(t *testing.T) {
 	t.Parallel()
 
@@ -1,53 +1,67 @@
 package pipeline
 
 import (
-	"bufio"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/datablast-analytics/blast-cli/pkg/jsonschema"
+	"github.com/datablast-analytics/blast-cli/pkg/path"
 	"github.com/pkg/errors"
 )
 
-const configMarker = "@blast."
+const (
+	configMarker = "@blast."
 
-var commentMarkers = map[string]string{
-	".sql": "--",
-	".py":  "#",
-}
+	// blockMarker starts an embedded YAML document that follows the `task.yml` schema, either in the consecutive line
+	// comments after it or in a block comment.
+	blockMarker = "@blast"
+)
 
+// blockKeyRegex matches the top-level keys of the blocks of line comments, e.g. `name: orders` or `depends:`.
+var blockKeyRegex = regexp.MustCompile(`^[\w.-]+:(\s|$)`)
+
+// CreateTaskFromFileComments creates the task from the annotations in the given file with the built-in comment
+// syntaxes, the files whose extensions are not supported are skipped.
 func CreateTaskFromFileComments(filePath string) (*Task, error) {
-	extension := filepath.Ext(filePath)
-	commentMarker, ok := commentMarkers[extension]
+	return builtinCommentSyntaxes.CreateTaskFromFileComments(filePath)
+}
+
+// CreateTaskFromFileComments creates the task from the annotations in the given file, the files whose extensions do not
+// have a syntax are skipped. It can be given to the builder to support the custom task types of a project.
+func (s CommentSyntaxes) CreateTaskFromFileComments(filePath string) (*Task, error) {
+	syntax, ok := s.For(filePath)
 	if !ok {
 		return nil, nil
 	}
 
-	file, err := os.Open(filePath)
+	content, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to open file %s", filePath)
-	}
-	defer file.Close()
-
-	var commentRows []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		rowText := scanner.Text()
-		if !strings.HasPrefix(rowText, commentMarker) {
-			continue
-		}
-
-		commentValue := strings.TrimSpace(strings.TrimPrefix(rowText, commentMarker))
-		if strings.HasPrefix(commentValue, configMarker) {
-			commentRows = append(commentRows, strings.TrimPrefix(commentValue, configMarker))
-		}
+		return nil, errors.Wrapf(err, "failed to read file %s", filePath)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, errors.Wrapf(err, "failed to read file %s", filePath)
+	found, err := findAnnotations(content, syntax)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the annotations in file %s", filePath)
 	}
 
-	if len(commentRows) == 0 {
+	var task *Task
+	switch {
+	case found.block != nil && len(found.rows) > 0:
+		return nil, errors.Errorf("the file %s defines the task both with a block on line %d and with `%s` rows, only one of them can be used", filePath, found.blockLine, configMarker)
+	case found.block != nil:
+		task, err = blockToTask(found.block, found.blockLine)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the block on line %d in file %s", found.blockLine, filePath)
+		}
+	case len(found.rows) > 0:
+		task, err = commentRowsToTask(found.rows)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the annotations in file %s", filePath)
+		}
+	default:
 		return nil, nil
 	}
 
@@ -56,29 +70,218 @@ func CreateTaskFromFileComments(filePath string) (*Task, error) {
 		return nil, errors.Wrapf(err, "failed to get absolute path for file %s", filePath)
 	}
 
-	task := commentRowsToTask(commentRows)
 	task.ExecutableFile = ExecutableFile{
 		Name: filepath.Base(filePath),
 		Path: absFilePath,
 	}
 
+	if task.Type == "" {
+		task.Type = syntax.DefaultType
+	}
+
+	return task, nil
+}
+
+type commentRow struct {
+	line   int
+	column int
+	value  string
+}
+
+type annotations struct {
+	rows      []commentRow
+	block     []string
+	blockLine int
+
+	// lines holds the indexes of all the lines that belong to the annotations, including the block delimiters
+	lines map[int]bool
+}
+
+// findAnnotations collects the `@blast.` rows and the `@blast` block from the file content, the line numbers are
+// 1-based to match the editors.
+func findAnnotations(content []byte, syntax CommentSyntax) (*annotations, error) {
+	commentMarker := syntax.LinePrefix
+
+	found := &annotations{lines: make(map[int]bool)}
+	startBlock := func(line int) error {
+		if found.block != nil {
+			return errors.Errorf("line %d: only one block is allowed, there is already one on line %d", line, found.blockLine)
+		}
+
+		found.blockLine = line
+		found.block = make([]string, 0)
+		return nil
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+
+		if syntax.hasBlockComments() && isBlockStart(line, syntax) {
+			if err := startBlock(i + 1); err != nil {
+				return nil, err
+			}
+
+			found.lines[i] = true
+			end := i + 1
+			for ; end < len(lines); end++ {
+				found.lines[end] = true
+				blockLine := strings.TrimRight(lines[end], "\r")
+				if strings.TrimSpace(blockLine) == syntax.BlockEnd {
+					break
+				}
+
+				found.block = append(found.block, blockLine)
+			}
+
+			if end == len(lines) {
+				return nil, errors.Errorf("line %d: the block is not closed with `%s`", i+1, syntax.BlockEnd)
+			}
+
+			i = end
+			continue
+		}
+
+		if commentMarker == "" || !strings.HasPrefix(line, commentMarker) {
+			continue
+		}
+
+		commentValue := strings.TrimSpace(strings.TrimPrefix(line, commentMarker))
+		switch {
+		case commentValue == blockMarker:
+			if err := startBlock(i + 1); err != nil {
+				return nil, err
+			}
+
+			found.lines[i] = true
+			for i+1 < len(lines) && isBlockCommentLine(strings.TrimRight(lines[i+1], "\r"), commentMarker) {
+				i++
+				found.lines[i] = true
+
+				blockLine := strings.TrimPrefix(strings.TrimRight(lines[i], "\r"), commentMarker)
+				found.block = append(found.block, strings.TrimPrefix(blockLine, " "))
+			}
+		case strings.HasPrefix(commentValue, configMarker):
+			found.lines[i] = true
+			found.rows = append(found.rows, commentRow{
+				line:   i + 1,
+				column: strings.Index(line, configMarker) + len(configMarker) + 1,
+				value:  strings.TrimPrefix(commentValue, configMarker),
+			})
+		}
+	}
+
+	return found, nil
+}
+
+// isBlockCommentLine reports whether the line continues a block of line comments. The block is a YAML mapping, so
+// besides the blank and indented lines only the keys and the YAML comments can follow, the block ends at the first
+// line that is not one of them, e.g. a regular comment or the code.
+func isBlockCommentLine(line, commentMarker string) bool {
+	if !strings.HasPrefix(line, commentMarker) {
+		return false
+	}
+
+	value := strings.TrimPrefix(strings.TrimPrefix(line, commentMarker), " ")
+	if strings.TrimSpace(value) == "" || strings.HasPrefix(value, " ") || strings.HasPrefix(value, "\t") {
+		return true
+	}
+
+	return strings.HasPrefix(value, "#") || blockKeyRegex.MatchString(value)
+}
+
+func isBlockStart(line string, syntax CommentSyntax) bool {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, syntax.BlockStart) {
+		return false
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(trimmed, syntax.BlockStart)) == blockMarker
+}
+
+// blockToTask reads the block with the same schema as the `task.yml` files, except the file to run, which is always
+// the annotated file itself. The line numbers of the violations are moved to match the file, the block starts on the
+// line after the given one.
+func blockToTask(block []string, blockLine int) (*Task, error) {
+	var definition taskDefinition
+	violations, err := path.DecodeYamlLenient([]byte(dedent(block)), &definition)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, violation := range violations {
+		violation.Line += blockLine
+	}
+
+	if definition.RunFile != "" {
+		return nil, errors.New("the `run` key cannot be used in the annotations, the annotated file is the one that runs")
+	}
+
+	task := definition.toTask()
+	task.DefinitionFile = DefinitionFile{Violations: violations}
+	if task.Parameters == nil {
+		task.Parameters = make(map[string]string)
+	}
+	if task.Connections == nil {
+		task.Connections = make(map[string]string)
+	}
+	if task.DependsOn == nil {
+		task.DependsOn = []string{}
+	}
+
 	return task, nil
 }
 
-func commentRowsToTask(commentRows []string) *Task {
+// dedent removes the indentation shared by all the non-empty lines, which allows indenting the block comments.
+func dedent(lines []string) string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		lineIndent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == -1 || lineIndent < indent {
+			indent = lineIndent
+		}
+	}
+
+	dedented := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			line = line[indent:]
+		}
+
+		dedented[i] = line
+	}
+
+	return strings.Join(dedented, "\n")
+}
+
+func commentRowsToTask(commentRows []commentRow) (*Task, error) {
 	task := Task{
 		Parameters:  make(map[string]string),
 		Connections: make(map[string]string),
 		DependsOn:   []string{},
 	}
+	var violations jsonschema.Violations
 	for _, row := range commentRows {
-		keyValue := strings.Split(row, ":")
-		if len(keyValue) != 2 {
-			continue
+		// only the first colon separates the key, the values might contain colons, e.g. cross-pipeline dependencies
+		key, value, ok := strings.Cut(row.value, ":")
+		if !ok {
+			return nil, errors.Errorf("line %d: the annotation `%s%s` must be in the `key: value` format", row.line, configMarker, row.value)
 		}
 
-		key := strings.TrimSpace(keyValue[0])
-		value := strings.TrimSpace(keyValue[1])
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		if isPolicyField, err := task.Policy.setField(key, value); isPolicyField {
+			if err != nil {
+				return nil, errors.Errorf("line %d: %v", row.line, err)
+			}
+
+			continue
+		}
 
 		switch key {
 		case "name":
@@ -99,28 +302,93 @@ func commentRowsToTask(commentRows []string) *Task {
 				task.DependsOn = append(task.DependsOn, strings.TrimSpace(v))
 			}
 
+			continue
+		case "owner":
+			task.Owner = value
+
+			continue
+		case "team":
+			task.Team = value
+
+			continue
+		case "tags":
+			for _, tag := range strings.Split(value, ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					task.Tags = append(task.Tags, tag)
+				}
+			}
+
 			continue
 		}
 
 		if strings.HasPrefix(key, "parameters.") {
 			parameters := strings.Split(key, ".")
 			if len(parameters) != 2 {
-				continue
+				return nil, errors.Errorf("line %d: invalid parameter name `%s`, use a block for the names with dots", row.line, strings.TrimPrefix(key, "parameters."))
 			}
 
 			task.Parameters[parameters[1]] = value
 			continue
 		}
 
+		if strings.HasPrefix(key, "meta.") {
+			meta := strings.Split(key, ".")
+			if len(meta) != 2 {
+				return nil, errors.Errorf("line %d: invalid metadata name `%s`, use a block for the names with dots", row.line, strings.TrimPrefix(key, "meta."))
+			}
+
+			if task.Meta == nil {
+				task.Meta = make(map[string]string)
+			}
+			task.Meta[meta[1]] = value
+			continue
+		}
+
 		if strings.HasPrefix(key, "connections.") {
 			connections := strings.Split(key, ".")
 			if len(connections) != 2 {
-				continue
+				return nil, errors.Errorf("line %d: invalid connection name `%s`, use a block for the names with dots", row.line, strings.TrimPrefix(key, "connections."))
 			}
 
 			task.Connections[connections[1]] = value
+			continue
+		}
+
+		violations = append(violations, unknownAnnotation(row, key))
+	}
+
+	task.DefinitionFile = DefinitionFile{Violations: violations}
+
+	return &task, nil
+}
+
+// commentRowKeys are the keys of the `@blast.` rows, the parameters, the metadata and the connections are followed by
+// the name of the entry, e.g. `@blast.parameters.destination`.
+var commentRowKeys = []string{
+	"connections", "depends", "description", "meta", "name", "owner", "parameters", "pool", "priority", "retries",
+	"retry_delay", "sla", "tags", "team", "timeout", "type",
+}
+
+// unknownAnnotation reports the unknown keys the same way as the unknown keys in the blocks, which do not prevent
+// building the task but usually point to a typo.
+func unknownAnnotation(row commentRow, key string) *jsonschema.Violation {
+	message := fmt.Sprintf("unknown annotation `%s%s`", configMarker, key)
+
+	name, entry, isEntry := strings.Cut(key, ".")
+	if suggestion := jsonschema.ClosestKey(name, commentRowKeys); suggestion != "" {
+		if isEntry {
+			suggestion += "." + entry
 		}
+		message += fmt.Sprintf(", did you mean `%s%s`?", configMarker, suggestion)
+	} else {
+		message += fmt.Sprintf(", the allowed annotations are: %s", strings.Join(commentRowKeys, ", "))
 	}
 
-	return &task
+	return &jsonschema.Violation{
+		Kind:    jsonschema.ViolationUnknownKey,
+		Path:    key,
+		Line:    row.line,
+		Column:  row.column,
+		Message: message,
+	}
 }
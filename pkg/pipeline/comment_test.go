@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/datablast-analytics/blast-cli/pkg/jsonschema"
 	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
 	"github.com/stretchr/testify/require"
 )
@@ -89,6 +90,254 @@ func Test_createTaskFromFile(t *testing.T) {
 				DependsOn: []string{"task1", "task2", "task3", "task4", "task5", "task3"},
 			},
 		},
+		{
+			name: "SQL file with a block comment parsed",
+			args: args{
+				filePath: "testdata/comments/block.sql",
+			},
+			want: &pipeline.Task{
+				Name:        "some-sql-task",
+				Description: "Builds the daily orders.\n\nThe source is read from: gs://bucket/orders\n",
+				Type:        "bq.sql",
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "block.sql",
+					Path: absPath("testdata/comments/block.sql"),
+				},
+				Parameters: map[string]string{
+					"source":        "gs://bucket/orders",
+					"dataset.table": "project.dataset.table",
+				},
+				Connections: map[string]string{
+					"gcpConnectionId": "gcp",
+				},
+				DependsOn: []string{"task1", "raw:task2"},
+			},
+		},
+		{
+			name: "Python file with a block of line comments parsed",
+			args: args{
+				filePath: "testdata/comments/block.py",
+			},
+			want: &pipeline.Task{
+				Name:        "some-python-task",
+				Description: "a folded description with a colon: here\n",
+				Type:        "python",
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "block.py",
+					Path: absPath("testdata/comments/block.py"),
+				},
+				Parameters:  map[string]string{},
+				Connections: map[string]string{},
+				DependsOn:   []string{"task1", "task2"},
+			},
+		},
+		{
+			name: "block of line comments ends at the first regular comment",
+			args: args{
+				filePath: "testdata/comments/block-with-comments.sql",
+			},
+			want: &pipeline.Task{
+				Name:        "some-sql-task",
+				Description: "Builds the daily orders.\n\nThe source is read from: gs://bucket/orders\n",
+				Type:        "bq.sql",
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "block-with-comments.sql",
+					Path: absPath("testdata/comments/block-with-comments.sql"),
+				},
+				Parameters:  map[string]string{},
+				Connections: map[string]string{},
+				DependsOn:   []string{"task1"},
+			},
+		},
+		{
+			name: "Python file with a docstring parsed",
+			args: args{
+				filePath: "testdata/comments/docstring.py",
+			},
+			want: &pipeline.Task{
+				Name: "some-python-task",
+				Type: "python",
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "docstring.py",
+					Path: absPath("testdata/comments/docstring.py"),
+				},
+				Parameters:  map[string]string{"url": "https://example.com/x"},
+				Connections: map[string]string{},
+				DependsOn:   []string{},
+			},
+		},
+		{
+			name: "SQL file with the ownership rows parsed",
+			args: args{
+				filePath: "testdata/comments/ownership.sql",
+			},
+			want: &pipeline.Task{
+				Name: "some-sql-task",
+				Type: "bq.sql",
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "ownership.sql",
+					Path: absPath("testdata/comments/ownership.sql"),
+				},
+				Parameters:  map[string]string{},
+				Connections: map[string]string{},
+				DependsOn:   []string{},
+				Owner:       "jane@example.com",
+				Team:        "analytics",
+				Tags:        []string{"finance", "daily", "pii"},
+				Meta:        map[string]string{"tier": "gold", "docs": "https://wiki.example.com/orders"},
+			},
+		},
+		{
+			name: "SQL file with the execution policy rows parsed",
+			args: args{
+				filePath: "testdata/comments/policy.sql",
+			},
+			want: &pipeline.Task{
+				Name: "some-sql-task",
+				Type: "bq.sql",
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "policy.sql",
+					Path: absPath("testdata/comments/policy.sql"),
+				},
+				Parameters:  map[string]string{},
+				Connections: map[string]string{},
+				DependsOn:   []string{},
+				Policy: pipeline.ExecutionPolicy{
+					Retries:    intPtr(3),
+					RetryDelay: "5m",
+					Timeout:    "1h",
+					SLA:        "2h",
+					Pool:       "bigquery",
+					Priority:   intPtr(10),
+				},
+			},
+		},
+		{
+			name: "policy numbers that are not whole numbers are reported",
+			args: args{
+				filePath: "testdata/comments/invalid-policy.sql",
+			},
+			wantErr: true,
+		},
+		{
+			name: "metadata names with dots are reported",
+			args: args{
+				filePath: "testdata/comments/invalid-meta.sql",
+			},
+			wantErr: true,
+		},
+		{
+			name: "rows without a colon are reported",
+			args: args{
+				filePath: "testdata/comments/invalid-row.sql",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid YAML in a block is reported",
+			args: args{
+				filePath: "testdata/comments/invalid-block.sql",
+			},
+			wantErr: true,
+		},
+		{
+			name: "blocks that are not closed are reported",
+			args: args{
+				filePath: "testdata/comments/unclosed-block.sql",
+			},
+			wantErr: true,
+		},
+		{
+			name: "rows and blocks cannot be mixed",
+			args: args{
+				filePath: "testdata/comments/mixed.sql",
+			},
+			wantErr: true,
+		},
+		{
+			name: "blocks cannot point to another file to run",
+			args: args{
+				filePath: "testdata/comments/block-with-run.sql",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown keys in a block are kept as violations with their line in the file",
+			args: args{
+				filePath: "testdata/comments/block-unknown-key.sql",
+			},
+			want: &pipeline.Task{
+				Name:        "some-sql-task",
+				Type:        "bq.sql",
+				Parameters:  map[string]string{},
+				Connections: map[string]string{},
+				DependsOn:   []string{},
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "block-unknown-key.sql",
+					Path: absPath("testdata/comments/block-unknown-key.sql"),
+				},
+				DefinitionFile: pipeline.DefinitionFile{
+					Violations: jsonschema.Violations{
+						{
+							Kind:    jsonschema.ViolationUnknownKey,
+							Path:    "depend",
+							Line:    4,
+							Column:  1,
+							Message: "unknown key `depend`, did you mean `depends`?",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unknown rows are kept as violations like the unknown keys in a block",
+			args: args{
+				filePath: "testdata/comments/unknown-row.sql",
+			},
+			want: &pipeline.Task{
+				Name:        "some-sql-task",
+				Type:        "bq.sql",
+				Parameters:  map[string]string{},
+				Connections: map[string]string{},
+				DependsOn:   []string{},
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "unknown-row.sql",
+					Path: absPath("testdata/comments/unknown-row.sql"),
+				},
+				DefinitionFile: pipeline.DefinitionFile{
+					Violations: jsonschema.Violations{
+						{
+							Kind:    jsonschema.ViolationUnknownKey,
+							Path:    "dependss",
+							Line:    3,
+							Column:  11,
+							Message: "unknown annotation `@blast.dependss`, did you mean `@blast.depends`?",
+						},
+						{
+							Kind:    jsonschema.ViolationUnknownKey,
+							Path:    "retires",
+							Line:    4,
+							Column:  11,
+							Message: "unknown annotation `@blast.retires`, did you mean `@blast.retries`?",
+						},
+						{
+							Kind:    jsonschema.ViolationUnknownKey,
+							Path:    "parameter.destination",
+							Line:    5,
+							Column:  11,
+							Message: "unknown annotation `@blast.parameter.destination`, did you mean `@blast.parameters.destination`?",
+						},
+						{
+							Kind:    jsonschema.ViolationUnknownKey,
+							Path:    "schedule",
+							Line:    6,
+							Column:  11,
+							Message: "unknown annotation `@blast.schedule`, the allowed annotations are: connections, depends, description, meta, name, owner, parameters, pool, priority, retries, retry_delay, sla, tags, team, timeout, type",
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -118,3 +367,7 @@ func BenchmarkCreateTaskFromFileComments(b *testing.B) {
 		require.NoError(b, err)
 	}
 }
+
+func intPtr(value int) *int {
+	return &value
+}
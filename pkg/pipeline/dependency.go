@@ -0,0 +1,54 @@
+package pipeline
+
+import "strings"
+
+// DependencySeparator separates the pipeline name from the task name in a cross-pipeline dependency, e.g.
+// `core-pipeline:orders_clean`.
+const DependencySeparator = ":"
+
+// Dependency is a reference to an upstream task. Pipeline is empty for the tasks in the same pipeline, which is the case
+// for the unqualified dependencies such as `orders_clean`.
+type Dependency struct {
+	Pipeline string
+	Task     string
+}
+
+func ParseDependency(value string) Dependency {
+	pipelineName, taskName, found := strings.Cut(value, DependencySeparator)
+	if !found {
+		return Dependency{Task: value}
+	}
+
+	return Dependency{Pipeline: pipelineName, Task: taskName}
+}
+
+// IsCrossPipeline returns true if the dependency is qualified with a pipeline name.
+func (d Dependency) IsCrossPipeline() bool {
+	return d.Pipeline != ""
+}
+
+func (d Dependency) String() string {
+	if !d.IsCrossPipeline() {
+		return d.Task
+	}
+
+	return d.Pipeline + DependencySeparator + d.Task
+}
+
+// IsCrossPipelineDependency checks if the given `depends` value refers to a task in another pipeline.
+func IsCrossPipelineDependency(value string) bool {
+	return strings.Contains(value, DependencySeparator)
+}
+
+// CrossPipelineDependencies returns the dependencies of the task that are qualified with a pipeline name, in the order
+// they are defined.
+func (t *Task) CrossPipelineDependencies() []Dependency {
+	dependencies := make([]Dependency, 0)
+	for _, dep := range t.DependsOn {
+		if dependency := ParseDependency(dep); dependency.IsCrossPipeline() {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	return dependencies
+}
@@ -0,0 +1,52 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDependency(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value           string
+		want            Dependency
+		isCrossPipeline bool
+	}{
+		{
+			value: "orders_clean",
+			want:  Dependency{Task: "orders_clean"},
+		},
+		{
+			value:           "core-pipeline:orders_clean",
+			want:            Dependency{Pipeline: "core-pipeline", Task: "orders_clean"},
+			isCrossPipeline: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+
+			got := ParseDependency(tt.value)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.isCrossPipeline, got.IsCrossPipeline())
+			assert.Equal(t, tt.isCrossPipeline, IsCrossPipelineDependency(tt.value))
+			assert.Equal(t, tt.value, got.String())
+		})
+	}
+}
+
+func TestTask_CrossPipelineDependencies(t *testing.T) {
+	t.Parallel()
+
+	task := &Task{
+		DependsOn: []string{"task1", "core:orders", "task2", "finance:invoices"},
+	}
+
+	assert.Equal(t, []Dependency{
+		{Pipeline: "core", Task: "orders"},
+		{Pipeline: "finance", Task: "invoices"},
+	}, task.CrossPipelineDependencies())
+}
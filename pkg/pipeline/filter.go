@@ -0,0 +1,97 @@
+package pipeline
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	filterTag   = "tag"
+	filterOwner = "owner"
+)
+
+// TaskFilter selects the tasks by their effective tags and owners. The values of the same kind are alternatives, e.g.
+// `tag:daily` and `tag:hourly` match the tasks with either tag, while the different kinds must all match.
+type TaskFilter struct {
+	Tags   []string
+	Owners []string
+}
+
+// ParseTaskFilters parses the filters given as `tag:<tag>` or `owner:<owner>`.
+func ParseTaskFilters(filters []string) (*TaskFilter, error) {
+	filter := &TaskFilter{}
+	for _, f := range filters {
+		kind, value, found := strings.Cut(f, ":")
+		value = strings.TrimSpace(value)
+		if !found || value == "" {
+			return nil, errors.Errorf("invalid filter '%s', use '%s:<tag>' or '%s:<owner>'", f, filterTag, filterOwner)
+		}
+
+		switch strings.TrimSpace(kind) {
+		case filterTag:
+			filter.Tags = append(filter.Tags, value)
+		case filterOwner:
+			filter.Owners = append(filter.Owners, value)
+		default:
+			return nil, errors.Errorf("unknown filter '%s', only '%s' and '%s' are supported", kind, filterTag, filterOwner)
+		}
+	}
+
+	return filter, nil
+}
+
+func (f *TaskFilter) IsEmpty() bool {
+	return f == nil || (len(f.Tags) == 0 && len(f.Owners) == 0)
+}
+
+// Matches checks the task against the filter, using the tags and the owner inherited from the pipeline.
+func (f *TaskFilter) Matches(p *Pipeline, t *Task) bool {
+	if f.IsEmpty() {
+		return true
+	}
+
+	if len(f.Owners) > 0 && !contains(f.Owners, p.EffectiveOwner(t)) {
+		return false
+	}
+
+	if len(f.Tags) == 0 {
+		return true
+	}
+
+	for _, tag := range p.EffectiveTags(t) {
+		if contains(f.Tags, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Apply returns a copy of the pipeline with only the tasks that match the filter, the tasks themselves are shared.
+// The second value is false if none of the tasks match.
+func (f *TaskFilter) Apply(p *Pipeline) (*Pipeline, bool) {
+	if f.IsEmpty() {
+		return p, true
+	}
+
+	subset := *p
+	subset.Tasks = make([]*Task, 0, len(p.Tasks))
+	for _, t := range p.Tasks {
+		if f.Matches(p, t) {
+			subset.Tasks = append(subset.Tasks, t)
+		}
+	}
+
+	return &subset, len(subset.Tasks) > 0
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
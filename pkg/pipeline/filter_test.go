@@ -0,0 +1,136 @@
+package pipeline_test
+
+import (
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskFilters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		filters []string
+		want    *pipeline.TaskFilter
+		wantErr bool
+	}{
+		{
+			name: "no filters",
+			want: &pipeline.TaskFilter{},
+		},
+		{
+			name:    "tags and owners are collected",
+			filters: []string{"tag:daily", "owner: jane@example.com", "tag:finance"},
+			want: &pipeline.TaskFilter{
+				Tags:   []string{"daily", "finance"},
+				Owners: []string{"jane@example.com"},
+			},
+		},
+		{
+			name:    "filters without a kind are refused",
+			filters: []string{"daily"},
+			wantErr: true,
+		},
+		{
+			name:    "filters without a value are refused",
+			filters: []string{"tag:"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown kinds are refused",
+			filters: []string{"team:analytics"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := pipeline.ParseTaskFilters(tt.filters)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTaskFilter_Apply(t *testing.T) {
+	t.Parallel()
+
+	orders := &pipeline.Task{Name: "orders", Tags: []string{"finance"}}
+	customers := &pipeline.Task{Name: "customers", Owner: "jane@example.com"}
+	events := &pipeline.Task{Name: "events", Owner: "jane@example.com", Tags: []string{"hourly"}}
+
+	p := &pipeline.Pipeline{
+		Name:  "sales",
+		Owner: "data@example.com",
+		Tags:  []string{"daily"},
+		Tasks: []*pipeline.Task{orders, customers, events},
+	}
+
+	tests := []struct {
+		name      string
+		filter    *pipeline.TaskFilter
+		wantTasks []*pipeline.Task
+		wantMatch bool
+	}{
+		{
+			name:      "empty filters match all the tasks",
+			filter:    &pipeline.TaskFilter{},
+			wantTasks: []*pipeline.Task{orders, customers, events},
+			wantMatch: true,
+		},
+		{
+			name:      "tags are matched against the tags of the task",
+			filter:    &pipeline.TaskFilter{Tags: []string{"finance", "hourly"}},
+			wantTasks: []*pipeline.Task{orders, events},
+			wantMatch: true,
+		},
+		{
+			name:      "tags are inherited from the pipeline",
+			filter:    &pipeline.TaskFilter{Tags: []string{"daily"}},
+			wantTasks: []*pipeline.Task{orders, customers, events},
+			wantMatch: true,
+		},
+		{
+			name:      "owners are inherited from the pipeline",
+			filter:    &pipeline.TaskFilter{Owners: []string{"data@example.com"}},
+			wantTasks: []*pipeline.Task{orders},
+			wantMatch: true,
+		},
+		{
+			name:      "tags and owners must both match",
+			filter:    &pipeline.TaskFilter{Tags: []string{"hourly"}, Owners: []string{"jane@example.com"}},
+			wantTasks: []*pipeline.Task{events},
+			wantMatch: true,
+		},
+		{
+			name:      "no matching tasks",
+			filter:    &pipeline.TaskFilter{Tags: []string{"finance"}, Owners: []string{"jane@example.com"}},
+			wantTasks: []*pipeline.Task{},
+			wantMatch: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, matched := tt.filter.Apply(p)
+			assert.Equal(t, tt.wantMatch, matched)
+			assert.Equal(t, tt.wantTasks, got.Tasks)
+			assert.Equal(t, "sales", got.Name)
+		})
+	}
+
+	// the original pipeline is left untouched
+	assert.Len(t, p.Tasks, 3)
+}
@@ -1,11 +1,16 @@
 package pipeline
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/datablast-analytics/blast-cli/pkg/jsonschema"
 	"github.com/datablast-analytics/blast-cli/pkg/path"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -27,6 +32,9 @@ type DefinitionFile struct {
 	Name string
 	Path string
 	Type TaskDefinitionType
+
+	// Violations are the problems in the file that did not prevent reading it, such as the unknown keys.
+	Violations jsonschema.Violations
 }
 
 type Task struct {
@@ -39,16 +47,77 @@ type Task struct {
 	Connections    map[string]string
 	DependsOn      []string
 	Pipeline       *Pipeline
+
+	// Owner, Team, Tags and Meta describe who is responsible for the task, they default to the ones of the pipeline.
+	Owner string
+	Team  string
+	Tags  []string
+	Meta  map[string]string
+
+	// Policy decides how the task runs, the fields that are not set are taken from the default policy of the pipeline.
+	Policy ExecutionPolicy
+}
+
+type BuildErrorKind string
+
+const (
+	BuildErrorUnreadable BuildErrorKind = "unreadable"
+	BuildErrorSyntax     BuildErrorKind = "syntax"
+	BuildErrorInvalid    BuildErrorKind = "invalid"
+)
+
+// BuildError is a file that could not be read while building the pipeline, the pipeline is built without it.
+type BuildError struct {
+	Path string
+	Kind BuildErrorKind
+	Err  error
+}
+
+func (e *BuildError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// newBuildError classifies the error by its cause, the errors that are neither about reading the file nor about the
+// values in it are syntax errors, e.g. malformed YAML or annotations.
+func newBuildError(filePath string, err error) *BuildError {
+	var (
+		pathError       *os.PathError
+		typeError       *yaml.TypeError
+		violations      jsonschema.Violations
+		validationError validator.ValidationErrors
+	)
+
+	kind := BuildErrorSyntax
+	switch {
+	case errors.As(err, &pathError):
+		kind = BuildErrorUnreadable
+	case errors.As(err, &typeError), errors.As(err, &violations), errors.As(err, &validationError):
+		kind = BuildErrorInvalid
+	}
+
+	return &BuildError{Path: filePath, Kind: kind, Err: err}
 }
 
 type Pipeline struct {
-	LegacyID           string   `yaml:"id"`
-	Name               string   `yaml:"name"`
-	Schedule           schedule `yaml:"schedule"`
-	DefinitionFile     DefinitionFile
-	DefaultParameters  map[string]string `yaml:"defaultParameters"`
-	DefaultConnections map[string]string `yaml:"defaultConnections"`
-	Tasks              []*Task
+	LegacyID           string            `yaml:"id" description:"Deprecated, use name instead."`
+	Name               string            `yaml:"name" description:"The unique name of the pipeline."`
+	Schedule           schedule          `yaml:"schedule" description:"A standard cron expression or a descriptor such as @daily."`
+	DefinitionFile     DefinitionFile    `yaml:"-"`
+	DefaultParameters  map[string]string `yaml:"defaultParameters" description:"The parameters applied to all the tasks, the tasks can override them."`
+	DefaultConnections map[string]string `yaml:"defaultConnections" description:"The connections applied to all the tasks, the tasks can override them."`
+	DefaultPolicy      ExecutionPolicy   `yaml:"defaultPolicy" description:"The execution policy applied to all the tasks, the tasks can override any of its fields."`
+	Owner              string            `yaml:"owner" description:"The person responsible for the pipeline, used for the tasks that do not have an owner."`
+	Team               string            `yaml:"team" description:"The team responsible for the pipeline, used for the tasks that do not have a team."`
+	Tags               []string          `yaml:"tags" description:"The tags applied to all the tasks in the pipeline."`
+	Meta               map[string]string `yaml:"meta" description:"Free-form metadata applied to all the tasks, the tasks can override them."`
+	Tasks              []*Task           `yaml:"-"`
+
+	// BuildErrors are the files that could not be read, they are only collected if the builder is configured to do so.
+	BuildErrors []*BuildError `yaml:"-"`
 }
 
 func (p *Pipeline) RelativeTaskPath(t *Task) string {
@@ -62,12 +131,187 @@ func (p *Pipeline) RelativeTaskPath(t *Task) string {
 	return pipelineDirectory
 }
 
+// HasBrokenDefinition reports whether the pipeline definition file could not be read, in which case the pipeline is
+// built without its settings, e.g. its name and schedule are empty.
+func (p *Pipeline) HasBrokenDefinition() bool {
+	for _, buildError := range p.BuildErrors {
+		if buildError.Path == p.DefinitionFile.Path {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EffectiveParameters returns the parameters of the task resolved with the defaults of its type in the registry and
+// the default parameters of the pipeline, see tasktype.TaskType.Resolve, which are the values the
+// `valid-task-parameters` rule checks. The tasks of unknown types get their parameters merged on top of the default
+// parameters of the pipeline.
+func (p *Pipeline) EffectiveParameters(t *Task, registry *tasktype.Registry) map[string]string {
+	if taskType := registry.Get(t.Type); taskType != nil {
+		return taskType.Resolve(t.Parameters, p.DefaultParameters)
+	}
+
+	return mergeDefaults(p.DefaultParameters, t.Parameters)
+}
+
+// EffectiveConnections returns the connections of the task merged on top of the default connections of the pipeline.
+func (p *Pipeline) EffectiveConnections(t *Task) map[string]string {
+	return mergeDefaults(p.DefaultConnections, t.Connections)
+}
+
+// EffectivePolicy returns the execution policy of the task with the fields that are not set taken from the default
+// policy of the pipeline.
+func (p *Pipeline) EffectivePolicy(t *Task) ExecutionPolicy {
+	return t.Policy.withDefaults(p.DefaultPolicy)
+}
+
+// EffectiveMeta returns the metadata of the task merged on top of the metadata of the pipeline.
+func (p *Pipeline) EffectiveMeta(t *Task) map[string]string {
+	return mergeDefaults(p.Meta, t.Meta)
+}
+
+// EffectiveOwner returns the owner of the task, or the owner of the pipeline if the task does not have one.
+func (p *Pipeline) EffectiveOwner(t *Task) string {
+	if t.Owner != "" {
+		return t.Owner
+	}
+
+	return p.Owner
+}
+
+// EffectiveTeam returns the team of the task, or the team of the pipeline if the task does not have one.
+func (p *Pipeline) EffectiveTeam(t *Task) string {
+	if t.Team != "" {
+		return t.Team
+	}
+
+	return p.Team
+}
+
+// EffectiveTags returns the tags of the pipeline followed by the tags of the task, without duplicates.
+func (p *Pipeline) EffectiveTags(t *Task) []string {
+	tags := make([]string, 0, len(p.Tags)+len(t.Tags))
+	seen := make(map[string]bool, len(p.Tags)+len(t.Tags))
+	for _, tag := range append(append([]string{}, p.Tags...), t.Tags...) {
+		if seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+func mergeDefaults(defaults, values map[string]string) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(values))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range values {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+func (p *Pipeline) GetTaskByName(name string) *Task {
+	for _, task := range p.Tasks {
+		if task.Name == name {
+			return task
+		}
+	}
+
+	return nil
+}
+
+// GetDownstreamTasks returns all the tasks that depend on the given task, directly or transitively, in the order they
+// are defined in the pipeline.
+func (p *Pipeline) GetDownstreamTasks(t *Task) []*Task {
+	dependents := make(map[string][]*Task)
+	for _, task := range p.Tasks {
+		for _, dep := range task.DependsOn {
+			if name, ok := p.localDependency(dep); ok {
+				dependents[name] = append(dependents[name], task)
+			}
+		}
+	}
+
+	return p.collectTasks(t, func(task *Task) []*Task {
+		return dependents[task.Name]
+	})
+}
+
+// GetUpstreamTasks returns all the tasks the given task depends on, directly or transitively, in the order they are
+// defined in the pipeline.
+func (p *Pipeline) GetUpstreamTasks(t *Task) []*Task {
+	return p.collectTasks(t, func(task *Task) []*Task {
+		dependencies := make([]*Task, 0, len(task.DependsOn))
+		for _, dep := range task.DependsOn {
+			name, ok := p.localDependency(dep)
+			if !ok {
+				continue
+			}
+
+			if upstream := p.GetTaskByName(name); upstream != nil {
+				dependencies = append(dependencies, upstream)
+			}
+		}
+
+		return dependencies
+	})
+}
+
+// localDependency returns the name of the task the dependency refers to in this pipeline. The dependencies qualified
+// with the name of the pipeline itself are local too, e.g. `sales:orders` in the `sales` pipeline.
+func (p *Pipeline) localDependency(value string) (string, bool) {
+	dependency := ParseDependency(value)
+	if dependency.IsCrossPipeline() && dependency.Pipeline != p.Name {
+		return "", false
+	}
+
+	return dependency.Task, true
+}
+
+func (p *Pipeline) collectTasks(start *Task, next func(task *Task) []*Task) []*Task {
+	visited := map[*Task]bool{start: true}
+	queue := []*Task{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, task := range next(current) {
+			if visited[task] {
+				continue
+			}
+
+			visited[task] = true
+			queue = append(queue, task)
+		}
+	}
+
+	result := make([]*Task, 0, len(visited)-1)
+	for _, task := range p.Tasks {
+		if task != start && visited[task] {
+			result = append(result, task)
+		}
+	}
+
+	return result
+}
+
 type TaskCreator func(path string) (*Task, error)
 
 type BuilderConfig struct {
 	PipelineFileName   string
 	TasksDirectoryName string
 	TasksFileName      string
+
+	// CollectErrors makes the builder keep building the pipeline when a file cannot be read, the file is added to the
+	// BuildErrors of the pipeline instead of failing the whole pipeline.
+	CollectErrors bool
 }
 
 type builder struct {
@@ -88,10 +332,21 @@ func (p *builder) CreatePipelineFromPath(pathToPipeline string) (*Pipeline, erro
 	pipelineFilePath := filepath.Join(pathToPipeline, p.config.PipelineFileName)
 	tasksPath := filepath.Join(pathToPipeline, p.config.TasksDirectoryName)
 
+	absPipelineFilePath, err := filepath.Abs(pipelineFilePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting absolute path for pipeline file at '%s'", pipelineFilePath)
+	}
+
 	var pipeline Pipeline
-	err := path.ReadYaml(pipelineFilePath, &pipeline)
+	violations, err := path.ReadYamlLenient(pipelineFilePath, &pipeline)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error reading pipeline file at '%s'", pipelineFilePath)
+		if !p.config.CollectErrors {
+			return nil, errors.Wrapf(err, "error reading pipeline file at '%s'", pipelineFilePath)
+		}
+
+		// the tasks are still built, their issues are reported even if the pipeline definition is broken
+		pipeline = Pipeline{}
+		pipeline.BuildErrors = append(pipeline.BuildErrors, newBuildError(absPipelineFilePath, err))
 	}
 
 	// this is needed until we migrate all the pipelines to use the new naming convention
@@ -99,14 +354,10 @@ func (p *builder) CreatePipelineFromPath(pathToPipeline string) (*Pipeline, erro
 		pipeline.Name = pipeline.LegacyID
 	}
 
-	absPipelineFilePath, err := filepath.Abs(pipelineFilePath)
-	if err != nil {
-		return nil, errors.Wrapf(err, "error getting absolute path for pipeline file at '%s'", pipelineFilePath)
-	}
-
 	pipeline.DefinitionFile = DefinitionFile{
-		Name: filepath.Base(pipelineFilePath),
-		Path: absPipelineFilePath,
+		Name:       filepath.Base(pipelineFilePath),
+		Path:       absPipelineFilePath,
+		Violations: violations,
 	}
 
 	taskFiles, err := path.GetAllFilesRecursive(tasksPath)
@@ -124,6 +375,11 @@ func (p *builder) CreatePipelineFromPath(pathToPipeline string) (*Pipeline, erro
 
 		task, err := creator(file)
 		if err != nil {
+			if p.config.CollectErrors {
+				pipeline.BuildErrors = append(pipeline.BuildErrors, newBuildError(file, err))
+				continue
+			}
+
 			return nil, errors.Wrapf(err, "error creating Task from file '%s'", file)
 		}
 
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -241,3 +242,186 @@ func TestTask_RelativePathToPipelineRoot(t *testing.T) {
 		})
 	}
 }
+
+func TestPipeline_GetDownstreamAndUpstreamTasks(t *testing.T) {
+	t.Parallel()
+
+	// a -> b -> d, a -> c -> d, e is standalone
+	a := &pipeline.Task{Name: "a"}
+	b := &pipeline.Task{Name: "b", DependsOn: []string{"a"}}
+	c := &pipeline.Task{Name: "c", DependsOn: []string{"a"}}
+	d := &pipeline.Task{Name: "d", DependsOn: []string{"b", "c", "some-missing-task"}}
+	e := &pipeline.Task{Name: "e"}
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Task{d, e, c, b, a},
+	}
+
+	assert.Equal(t, []*pipeline.Task{d, c, b}, p.GetDownstreamTasks(a))
+	assert.Equal(t, []*pipeline.Task{d}, p.GetDownstreamTasks(b))
+	assert.Empty(t, p.GetDownstreamTasks(d))
+	assert.Empty(t, p.GetDownstreamTasks(e))
+
+	assert.Equal(t, []*pipeline.Task{c, b, a}, p.GetUpstreamTasks(d))
+	assert.Equal(t, []*pipeline.Task{a}, p.GetUpstreamTasks(b))
+	assert.Empty(t, p.GetUpstreamTasks(a))
+
+	assert.Equal(t, c, p.GetTaskByName("c"))
+	assert.Nil(t, p.GetTaskByName("some-missing-task"))
+}
+
+func TestPipeline_GetDownstreamAndUpstreamTasksWithQualifiedDependencies(t *testing.T) {
+	t.Parallel()
+
+	// the dependencies qualified with the name of the pipeline itself are the same as the unqualified ones
+	a := &pipeline.Task{Name: "a"}
+	b := &pipeline.Task{Name: "b", DependsOn: []string{"sales:a"}}
+	c := &pipeline.Task{Name: "c", DependsOn: []string{"b", "marketing:a"}}
+
+	p := &pipeline.Pipeline{
+		Name:  "sales",
+		Tasks: []*pipeline.Task{a, b, c},
+	}
+
+	assert.Equal(t, []*pipeline.Task{b, c}, p.GetDownstreamTasks(a))
+	assert.Equal(t, []*pipeline.Task{a, b}, p.GetUpstreamTasks(c))
+}
+
+func TestPipeline_EffectiveParametersAndConnections(t *testing.T) {
+	t.Parallel()
+
+	registry := tasktype.Builtin()
+	p := &pipeline.Pipeline{
+		DefaultParameters:  map[string]string{"env": "prod", "region": "eu", "timeout": "1h"},
+		DefaultConnections: map[string]string{"gcpConnectionId": "gcp-default"},
+	}
+	task := &pipeline.Task{
+		Parameters:  map[string]string{"region": "us", "table": "orders"},
+		Connections: map[string]string{},
+	}
+
+	assert.Equal(t, map[string]string{"env": "prod", "region": "us", "table": "orders", "timeout": "1h"}, p.EffectiveParameters(task, registry))
+	assert.Equal(t, map[string]string{"gcpConnectionId": "gcp-default"}, p.EffectiveConnections(task))
+
+	// the defaults of the type are included, and only the known pipeline defaults are taken by the strict types
+	sensor := &pipeline.Task{
+		Type:       "s3.sensor.key_sensor",
+		Parameters: map[string]string{"bucket_key": "orders/*"},
+	}
+	assert.Equal(t, map[string]string{"bucket_key": "orders/*", "poke_interval": "1m", "timeout": "1h"}, p.EffectiveParameters(sensor, registry))
+
+	// the returned maps are copies, changing them must not affect the pipeline or the task
+	p.EffectiveParameters(task, registry)["env"] = "dev"
+	assert.Equal(t, "prod", p.DefaultParameters["env"])
+	assert.NotContains(t, task.Parameters, "env")
+}
+
+func TestPipeline_EffectiveOwnership(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Owner: "data@example.com",
+		Team:  "data",
+		Tags:  []string{"daily", "finance"},
+		Meta:  map[string]string{"tier": "silver", "slack": "#data"},
+	}
+
+	inherited := &pipeline.Task{}
+	assert.Equal(t, "data@example.com", p.EffectiveOwner(inherited))
+	assert.Equal(t, "data", p.EffectiveTeam(inherited))
+	assert.Equal(t, []string{"daily", "finance"}, p.EffectiveTags(inherited))
+	assert.Equal(t, map[string]string{"tier": "silver", "slack": "#data"}, p.EffectiveMeta(inherited))
+
+	overridden := &pipeline.Task{
+		Owner: "jane@example.com",
+		Team:  "analytics",
+		Tags:  []string{"pii", "finance"},
+		Meta:  map[string]string{"tier": "gold"},
+	}
+	assert.Equal(t, "jane@example.com", p.EffectiveOwner(overridden))
+	assert.Equal(t, "analytics", p.EffectiveTeam(overridden))
+	assert.Equal(t, []string{"daily", "finance", "pii"}, p.EffectiveTags(overridden))
+	assert.Equal(t, map[string]string{"tier": "gold", "slack": "#data"}, p.EffectiveMeta(overridden))
+}
+
+func TestBuilder_CollectErrors(t *testing.T) {
+	t.Parallel()
+
+	absPath := func(path string) string {
+		absolutePath, _ := filepath.Abs(path)
+		return absolutePath
+	}
+
+	tests := []struct {
+		name            string
+		pathToPipeline  string
+		wantName        string
+		wantTasks       []string
+		wantBuildErrors map[string]pipeline.BuildErrorKind
+		wantBroken      bool
+	}{
+		{
+			name:           "broken task files are collected and the rest of the tasks are built",
+			pathToPipeline: "testdata/broken-pipeline",
+			wantName:       "broken-pipeline",
+			wantTasks:      []string{"annotated", "valid"},
+			wantBuildErrors: map[string]pipeline.BuildErrorKind{
+				"testdata/broken-pipeline/tasks/broken-yaml/task.yml":    pipeline.BuildErrorSyntax,
+				"testdata/broken-pipeline/tasks/invalid-values/task.yml": pipeline.BuildErrorInvalid,
+				"testdata/broken-pipeline/tasks/unclosed.sql":            pipeline.BuildErrorSyntax,
+			},
+		},
+		{
+			name:           "the tasks are built even if the pipeline definition is broken",
+			pathToPipeline: "testdata/broken-pipeline-definition",
+			wantTasks:      []string{"annotated"},
+			wantBuildErrors: map[string]pipeline.BuildErrorKind{
+				"testdata/broken-pipeline-definition/pipeline.yml": pipeline.BuildErrorSyntax,
+			},
+			wantBroken: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			builderConfig := pipeline.BuilderConfig{
+				PipelineFileName:   "pipeline.yml",
+				TasksDirectoryName: "tasks",
+				TasksFileName:      "task.yml",
+			}
+
+			_, err := pipeline.NewBuilder(builderConfig, pipeline.CreateTaskFromYamlDefinition, pipeline.CreateTaskFromFileComments).
+				CreatePipelineFromPath(tt.pathToPipeline)
+			require.Error(t, err, "the builder must fail if it is not configured to collect the errors")
+
+			builderConfig.CollectErrors = true
+			got, err := pipeline.NewBuilder(builderConfig, pipeline.CreateTaskFromYamlDefinition, pipeline.CreateTaskFromFileComments).
+				CreatePipelineFromPath(tt.pathToPipeline)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantName, got.Name)
+			assert.Equal(t, absPath(filepath.Join(tt.pathToPipeline, "pipeline.yml")), got.DefinitionFile.Path)
+			assert.Equal(t, tt.wantBroken, got.HasBrokenDefinition())
+
+			taskNames := make([]string, 0, len(got.Tasks))
+			for _, task := range got.Tasks {
+				taskNames = append(taskNames, task.Name)
+			}
+			assert.ElementsMatch(t, tt.wantTasks, taskNames)
+
+			buildErrors := make(map[string]pipeline.BuildErrorKind, len(got.BuildErrors))
+			for _, buildError := range got.BuildErrors {
+				require.Error(t, buildError.Err)
+				buildErrors[buildError.Path] = buildError.Kind
+			}
+
+			wantBuildErrors := make(map[string]pipeline.BuildErrorKind, len(tt.wantBuildErrors))
+			for file, kind := range tt.wantBuildErrors {
+				wantBuildErrors[absPath(file)] = kind
+			}
+			assert.Equal(t, wantBuildErrors, buildErrors)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package pipeline
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ExecutionPolicy describes how a task runs, the durations are written in the Go format such as "90s" or "1h30m". The
+// fields that are not set are taken from the default policy of the pipeline.
+type ExecutionPolicy struct {
+	Retries    *int   `yaml:"retries,omitempty" description:"The number of times the task is retried after it fails."`
+	RetryDelay string `yaml:"retry_delay,omitempty" description:"The duration to wait before retrying the task, e.g. 5m."`
+	Timeout    string `yaml:"timeout,omitempty" description:"The maximum duration of a single attempt, e.g. 1h."`
+	SLA        string `yaml:"sla,omitempty" description:"The duration the task is expected to finish in, including the retries, e.g. 2h."`
+	Pool       string `yaml:"pool,omitempty" description:"The pool that limits how many tasks run at the same time, e.g. bigquery."`
+	Priority   *int   `yaml:"priority,omitempty" description:"The tasks with a higher priority start first when they are waiting for a slot."`
+}
+
+// IsEmpty checks if none of the fields are set.
+func (e ExecutionPolicy) IsEmpty() bool {
+	return e == ExecutionPolicy{}
+}
+
+// withDefaults returns the policy with the fields that are not set taken from the defaults.
+func (e ExecutionPolicy) withDefaults(defaults ExecutionPolicy) ExecutionPolicy {
+	if e.Retries == nil {
+		e.Retries = defaults.Retries
+	}
+	if e.RetryDelay == "" {
+		e.RetryDelay = defaults.RetryDelay
+	}
+	if e.Timeout == "" {
+		e.Timeout = defaults.Timeout
+	}
+	if e.SLA == "" {
+		e.SLA = defaults.SLA
+	}
+	if e.Pool == "" {
+		e.Pool = defaults.Pool
+	}
+	if e.Priority == nil {
+		e.Priority = defaults.Priority
+	}
+
+	return e
+}
+
+// setField sets the field with the given annotation key, it returns false if the key is not a policy field.
+func (e *ExecutionPolicy) setField(key, value string) (bool, error) {
+	switch key {
+	case "retries", "priority":
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return true, errors.Errorf("the `%s` must be a whole number, got `%s`", key, value)
+		}
+
+		if key == "retries" {
+			e.Retries = &number
+		} else {
+			e.Priority = &number
+		}
+	case "retry_delay":
+		e.RetryDelay = value
+	case "timeout":
+		e.Timeout = value
+	case "sla":
+		e.SLA = value
+	case "pool":
+		e.Pool = value
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// fields returns the annotation keys and the values of the fields that are set, in the order they are defined.
+func (e ExecutionPolicy) fields() [][2]string {
+	fields := make([][2]string, 0)
+	if e.Retries != nil {
+		fields = append(fields, [2]string{"retries", strconv.Itoa(*e.Retries)})
+	}
+	if e.RetryDelay != "" {
+		fields = append(fields, [2]string{"retry_delay", e.RetryDelay})
+	}
+	if e.Timeout != "" {
+		fields = append(fields, [2]string{"timeout", e.Timeout})
+	}
+	if e.SLA != "" {
+		fields = append(fields, [2]string{"sla", e.SLA})
+	}
+	if e.Pool != "" {
+		fields = append(fields, [2]string{"pool", e.Pool})
+	}
+	if e.Priority != nil {
+		fields = append(fields, [2]string{"priority", strconv.Itoa(*e.Priority)})
+	}
+
+	return fields
+}
+
+// Policy is the execution policy with the durations parsed, the zero values mean the field is not set.
+type Policy struct {
+	Retries    int
+	RetryDelay time.Duration
+	Timeout    time.Duration
+	SLA        time.Duration
+	Pool       string
+	Priority   int
+}
+
+// Resolve parses the durations of the policy, the first invalid duration is returned as an error.
+func (e ExecutionPolicy) Resolve() (*Policy, error) {
+	policy := &Policy{Pool: e.Pool}
+	if e.Retries != nil {
+		policy.Retries = *e.Retries
+	}
+	if e.Priority != nil {
+		policy.Priority = *e.Priority
+	}
+
+	durations := []struct {
+		name   string
+		value  string
+		target *time.Duration
+	}{
+		{name: "retry_delay", value: e.RetryDelay, target: &policy.RetryDelay},
+		{name: "timeout", value: e.Timeout, target: &policy.Timeout},
+		{name: "sla", value: e.SLA, target: &policy.SLA},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+
+		duration, err := time.ParseDuration(d.value)
+		if err != nil {
+			return nil, errors.Errorf("invalid %s '%s', use a duration such as 30s, 5m or 1h30m", d.name, d.value)
+		}
+
+		*d.target = duration
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,92 @@
+package pipeline_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline_EffectivePolicy(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		DefaultPolicy: pipeline.ExecutionPolicy{
+			Retries:    intPtr(2),
+			RetryDelay: "1m",
+			Pool:       "bigquery",
+			Priority:   intPtr(1),
+		},
+	}
+
+	inherited := &pipeline.Task{}
+	assert.Equal(t, p.DefaultPolicy, p.EffectivePolicy(inherited))
+
+	// zero values set on the task override the defaults
+	overridden := &pipeline.Task{Policy: pipeline.ExecutionPolicy{Retries: intPtr(0), Timeout: "10m", Pool: "local"}}
+	assert.Equal(t, pipeline.ExecutionPolicy{
+		Retries:    intPtr(0),
+		RetryDelay: "1m",
+		Timeout:    "10m",
+		Pool:       "local",
+		Priority:   intPtr(1),
+	}, p.EffectivePolicy(overridden))
+}
+
+func TestExecutionPolicy_Resolve(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		policy  pipeline.ExecutionPolicy
+		want    *pipeline.Policy
+		wantErr string
+	}{
+		{
+			name:   "empty policy",
+			policy: pipeline.ExecutionPolicy{},
+			want:   &pipeline.Policy{},
+		},
+		{
+			name: "all the fields are resolved",
+			policy: pipeline.ExecutionPolicy{
+				Retries:    intPtr(3),
+				RetryDelay: "30s",
+				Timeout:    "1h",
+				SLA:        "1h30m",
+				Pool:       "bigquery",
+				Priority:   intPtr(-2),
+			},
+			want: &pipeline.Policy{
+				Retries:    3,
+				RetryDelay: 30 * time.Second,
+				Timeout:    time.Hour,
+				SLA:        90 * time.Minute,
+				Pool:       "bigquery",
+				Priority:   -2,
+			},
+		},
+		{
+			name:    "invalid durations are reported with the field name",
+			policy:  pipeline.ExecutionPolicy{Timeout: "1 hour"},
+			wantErr: "invalid timeout '1 hour', use a duration such as 30s, 5m or 1h30m",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.policy.Resolve()
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package pipeline
+
+import (
+	"github.com/datablast-analytics/blast-cli/pkg/jsonschema"
+)
+
+// PipelineSchema returns the JSON Schema of the pipeline definition files.
+func PipelineSchema() *jsonschema.Schema {
+	schema := jsonschema.Reflect(Pipeline{})
+	schema.Schema = jsonschema.Draft
+	schema.Title = "Blast pipeline definition"
+
+	return schema
+}
+
+// TaskDefinitionSchema returns the JSON Schema of the `task.yml` files, the `type` is limited to the given task types
+// if there are any.
+func TaskDefinitionSchema(taskTypes []string) *jsonschema.Schema {
+	schema := jsonschema.Reflect(taskDefinition{})
+	schema.Schema = jsonschema.Draft
+	schema.Title = "Blast task definition"
+
+	if len(taskTypes) > 0 {
+		schema.Properties["type"].Enum = taskTypes
+	}
+
+	return schema
+}
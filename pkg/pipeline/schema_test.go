@@ -0,0 +1,36 @@
+package pipeline_test
+
+import (
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/jsonschema"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := pipeline.PipelineSchema()
+	assert.ElementsMatch(t, []string{"id", "name", "schedule", "defaultParameters", "defaultConnections", "defaultPolicy", "owner", "team", "tags", "meta"}, keys(schema.Properties))
+	assert.ElementsMatch(t, []string{"retries", "retry_delay", "timeout", "sla", "pool", "priority"}, keys(schema.Properties["defaultPolicy"].Properties))
+}
+
+func TestTaskDefinitionSchema(t *testing.T) {
+	t.Parallel()
+
+	schema := pipeline.TaskDefinitionSchema([]string{"bash", "python"})
+	assert.ElementsMatch(t, []string{"name", "description", "type", "run", "depends", "parameters", "connections", "owner", "team", "tags", "meta", "retries", "retry_delay", "timeout", "sla", "pool", "priority"}, keys(schema.Properties))
+	assert.Equal(t, []string{"bash", "python"}, schema.Properties["type"].Enum)
+
+	assert.Empty(t, pipeline.TaskDefinitionSchema(nil).Properties["type"].Enum)
+}
+
+func keys(values map[string]*jsonschema.Schema) []string {
+	result := make([]string, 0, len(values))
+	for key := range values {
+		result = append(result, key)
+	}
+
+	return result
+}
@@ -0,0 +1,246 @@
+package pipeline
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// MarshalYaml returns the content of a `task.yml` file for the task, the executable file is referenced relative to
+// the directory the definition file is going to be placed in.
+func MarshalYaml(t *Task, definitionDir string) ([]byte, error) {
+	definition := newTaskDefinition(t)
+	if t.ExecutableFile.Path != "" {
+		runFile, err := filepath.Rel(definitionDir, t.ExecutableFile.Path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the path of the executable file relative to '%s'", definitionDir)
+		}
+
+		definition.RunFile = filepath.ToSlash(runFile)
+	}
+
+	return marshalDefinition(definition, t.Name)
+}
+
+func marshalDefinition(definition taskDefinition, taskName string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(definition); err != nil {
+		return nil, errors.Wrapf(err, "failed to encode the task '%s'", taskName)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, errors.Wrapf(err, "failed to encode the task '%s'", taskName)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MarshalComments returns the annotation rows that define the task in a file with the given extension, e.g.
+// `-- @blast.name: orders` for `.sql` files. The rows are read line by line with the surrounding whitespace trimmed,
+// therefore the tasks with values that would change on the way back are written as a YAML block instead.
+func MarshalComments(t *Task, extension string) ([]string, error) {
+	return builtinCommentSyntaxes.MarshalComments(t, extension)
+}
+
+// MarshalComments is the same as the MarshalComments function with the given syntaxes instead of the built-in ones.
+func (s CommentSyntaxes) MarshalComments(t *Task, extension string) ([]string, error) {
+	syntax, ok := s.For(extension)
+	if !ok || syntax.LinePrefix == "" {
+		return nil, errors.Errorf("the '%s' files cannot be annotated with comments", extension)
+	}
+
+	commentMarker := syntax.LinePrefix
+	if rows, ok := marshalRows(t, commentMarker); ok {
+		return rows, nil
+	}
+
+	return marshalBlock(t, commentMarker)
+}
+
+// marshalRows returns false if any of the values cannot be represented as a single `@blast.` row.
+func marshalRows(t *Task, commentMarker string) ([]string, bool) {
+	fields := make([][2]string, 0)
+	addField := func(key, value string) {
+		fields = append(fields, [2]string{key, value})
+	}
+
+	addField("name", t.Name)
+	if t.Description != "" {
+		addField("description", t.Description)
+	}
+	addField("type", t.Type)
+
+	for _, list := range []struct {
+		key    string
+		values []string
+	}{{key: "depends", values: t.DependsOn}, {key: "tags", values: t.Tags}} {
+		if len(list.values) == 0 {
+			continue
+		}
+
+		for _, value := range list.values {
+			if value == "" || strings.Contains(value, ",") {
+				return nil, false
+			}
+		}
+
+		addField(list.key, strings.Join(list.values, ", "))
+	}
+
+	if t.Owner != "" {
+		addField("owner", t.Owner)
+	}
+	if t.Team != "" {
+		addField("team", t.Team)
+	}
+
+	for _, field := range t.Policy.fields() {
+		addField(field[0], field[1])
+	}
+
+	for _, group := range []struct {
+		prefix string
+		values map[string]string
+	}{{prefix: "parameters", values: t.Parameters}, {prefix: "connections", values: t.Connections}, {prefix: "meta", values: t.Meta}} {
+		keys := make([]string, 0, len(group.values))
+		for key := range group.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if key == "" || key != strings.TrimSpace(key) || strings.ContainsAny(key, ".:") {
+				return nil, false
+			}
+
+			addField(group.prefix+"."+key, group.values[key])
+		}
+	}
+
+	rows := make([]string, 0, len(fields))
+	for _, field := range fields {
+		key, value := field[0], field[1]
+		if value != strings.TrimSpace(value) || strings.ContainsAny(value, "\r\n") {
+			return nil, false
+		}
+
+		row := fmt.Sprintf("%s %s%s:", commentMarker, configMarker, key)
+		if value != "" {
+			row += " " + value
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, true
+}
+
+func marshalBlock(t *Task, commentMarker string) ([]string, error) {
+	content, err := marshalDefinition(newTaskDefinition(t), t.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []string{commentMarker + " " + blockMarker}
+	for _, line := range strings.Split(strings.TrimSuffix(string(content), "\n"), "\n") {
+		if line == "" {
+			rows = append(rows, commentMarker)
+			continue
+		}
+
+		rows = append(rows, commentMarker+" "+line)
+	}
+
+	return rows, nil
+}
+
+// StripComments removes the annotations from the content of a comment-defined task, along with the blank lines that
+// separated them from the rest of the file. An existing shebang line is kept at the top.
+func StripComments(content []byte, extension string) ([]byte, error) {
+	return builtinCommentSyntaxes.StripComments(content, extension)
+}
+
+// StripComments is the same as the StripComments function with the given syntaxes instead of the built-in ones.
+func (s CommentSyntaxes) StripComments(content []byte, extension string) ([]byte, error) {
+	syntax, ok := s.For(extension)
+	if !ok {
+		return nil, errors.Errorf("the '%s' files cannot be annotated with comments", extension)
+	}
+
+	found, err := findAnnotations(content, syntax)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	kept := make([]string, 0, len(lines))
+	skipBlankLines := false
+	for i, line := range lines {
+		if found.lines[i] {
+			skipBlankLines = true
+			continue
+		}
+
+		if skipBlankLines && strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		skipBlankLines = false
+		kept = append(kept, line)
+	}
+
+	return []byte(strings.Join(kept, "\n")), nil
+}
+
+// AddComments places the annotation rows at the top of the file content, right after the shebang if there is one.
+func AddComments(content []byte, rows []string) []byte {
+	header := strings.Join(rows, "\n") + "\n"
+
+	body := string(content)
+	shebang := ""
+	if strings.HasPrefix(body, "#!") {
+		shebang, body, _ = strings.Cut(body, "\n")
+		shebang += "\n"
+	}
+
+	if body != "" {
+		header += "\n"
+	}
+
+	return []byte(shebang + header + body)
+}
+
+// HasComments checks if the content contains any annotations, which would define a task on their own.
+func HasComments(content []byte, extension string) bool {
+	return builtinCommentSyntaxes.HasComments(content, extension)
+}
+
+// HasComments is the same as the HasComments function with the given syntaxes instead of the built-in ones.
+func (s CommentSyntaxes) HasComments(content []byte, extension string) bool {
+	syntax, ok := s.For(extension)
+	if !ok {
+		return false
+	}
+
+	found, err := findAnnotations(content, syntax)
+
+	return err != nil || len(found.lines) > 0
+}
+
+// SupportsComments checks if the files with the given extension can define tasks with comments.
+func SupportsComments(extension string) bool {
+	return builtinCommentSyntaxes.SupportsComments(extension)
+}
+
+// SupportsComments is the same as the SupportsComments function with the given syntaxes instead of the built-in ones.
+func (s CommentSyntaxes) SupportsComments(extension string) bool {
+	syntax, ok := s.For(extension)
+	return ok && syntax.LinePrefix != ""
+}
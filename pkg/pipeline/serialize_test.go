@@ -0,0 +1,341 @@
+package pipeline_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalYaml(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	task := &pipeline.Task{
+		Name:        "hello-world",
+		Description: "a description: with a colon",
+		Type:        "bash",
+		ExecutableFile: pipeline.ExecutableFile{
+			Name: "hello.sh",
+			Path: filepath.Join(dir, "scripts", "hello.sh"),
+		},
+		DependsOn:   []string{"gcs-to-bq", "raw:orders"},
+		Parameters:  map[string]string{"param2": "gs://bucket/x", "param1": " padded "},
+		Connections: map[string]string{"conn1": "first connection"},
+	}
+
+	content, err := pipeline.MarshalYaml(task, dir)
+	require.NoError(t, err)
+	assert.Equal(t, `name: hello-world
+description: 'a description: with a colon'
+type: bash
+run: scripts/hello.sh
+depends:
+  - gcs-to-bq
+  - raw:orders
+parameters:
+  param1: ' padded '
+  param2: gs://bucket/x
+connections:
+  conn1: first connection
+`, string(content))
+
+	definitionFile := filepath.Join(dir, "task.yml")
+	require.NoError(t, os.WriteFile(definitionFile, content, 0o600))
+
+	got, err := pipeline.CreateTaskFromYamlDefinition(definitionFile)
+	require.NoError(t, err)
+	assert.Equal(t, task, got)
+}
+
+func TestMarshalComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		task      *pipeline.Task
+		extension string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name: "all the fields are written",
+			task: &pipeline.Task{
+				Name:        "some-python-task",
+				Description: "values can contain colons: gs://bucket/x",
+				Type:        "python",
+				DependsOn:   []string{"task1", "raw:task2"},
+				Parameters:  map[string]string{"param2": "second", "param1": ""},
+				Connections: map[string]string{"conn1": "first-connection"},
+			},
+			extension: ".py",
+			want: []string{
+				"# @blast.name: some-python-task",
+				"# @blast.description: values can contain colons: gs://bucket/x",
+				"# @blast.type: python",
+				"# @blast.depends: task1, raw:task2",
+				"# @blast.parameters.param1:",
+				"# @blast.parameters.param2: second",
+				"# @blast.connections.conn1: first-connection",
+			},
+		},
+		{
+			name: "the ownership fields are written",
+			task: &pipeline.Task{
+				Name:  "orders",
+				Type:  "bq.sql",
+				Owner: "jane@example.com",
+				Team:  "analytics",
+				Tags:  []string{"finance", "daily"},
+				Meta:  map[string]string{"tier": "gold"},
+			},
+			extension: ".sql",
+			want: []string{
+				"-- @blast.name: orders",
+				"-- @blast.type: bq.sql",
+				"-- @blast.tags: finance, daily",
+				"-- @blast.owner: jane@example.com",
+				"-- @blast.team: analytics",
+				"-- @blast.meta.tier: gold",
+			},
+		},
+		{
+			name: "the execution policy is written",
+			task: &pipeline.Task{
+				Name: "orders",
+				Type: "bq.sql",
+				Policy: pipeline.ExecutionPolicy{
+					Retries:  intPtr(0),
+					Timeout:  "1h",
+					Pool:     "bigquery",
+					Priority: intPtr(5),
+				},
+			},
+			extension: ".sql",
+			want: []string{
+				"-- @blast.name: orders",
+				"-- @blast.type: bq.sql",
+				"-- @blast.retries: 0",
+				"-- @blast.timeout: 1h",
+				"-- @blast.pool: bigquery",
+				"-- @blast.priority: 5",
+			},
+		},
+		{
+			name:      "optional fields are skipped",
+			task:      &pipeline.Task{Name: "orders", Type: "bq.sql"},
+			extension: ".sql",
+			want:      []string{"-- @blast.name: orders", "-- @blast.type: bq.sql"},
+		},
+		{
+			name:      "unsupported extensions are refused",
+			task:      &pipeline.Task{Name: "hello", Type: "bash"},
+			extension: ".rb",
+			wantErr:   true,
+		},
+		{
+			name:      "multi-line descriptions are written as a block",
+			task:      &pipeline.Task{Name: "orders", Type: "bq.sql", Description: "first line\n\nsecond line: with a colon\n"},
+			extension: ".sql",
+			want: []string{
+				"-- @blast",
+				"-- name: orders",
+				"-- description: |",
+				"--   first line",
+				"--",
+				"--   second line: with a colon",
+				"-- type: bq.sql",
+			},
+		},
+		{
+			name:      "values with surrounding whitespace are written as a block",
+			task:      &pipeline.Task{Name: "orders", Type: "bq.sql", Parameters: map[string]string{"param": " padded"}},
+			extension: ".sql",
+			want: []string{
+				"-- @blast",
+				"-- name: orders",
+				"-- type: bq.sql",
+				"-- parameters:",
+				"--   param: ' padded'",
+			},
+		},
+		{
+			name:      "keys with dots are written as a block",
+			task:      &pipeline.Task{Name: "orders", Type: "python", Connections: map[string]string{"conn.id": "value"}},
+			extension: ".py",
+			want: []string{
+				"# @blast",
+				"# name: orders",
+				"# type: python",
+				"# connections:",
+				"#   conn.id: value",
+			},
+		},
+		{
+			name:      "dependencies with commas are written as a block",
+			task:      &pipeline.Task{Name: "orders", Type: "bq.sql", DependsOn: []string{"a,b"}},
+			extension: ".sql",
+			want: []string{
+				"-- @blast",
+				"-- name: orders",
+				"-- type: bq.sql",
+				"-- depends:",
+				"--   - a,b",
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := pipeline.MarshalComments(tt.task, tt.extension)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMarshalComments_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		task *pipeline.Task
+	}{
+		{
+			name: "rows",
+			task: &pipeline.Task{
+				Name:        "some-python-task",
+				Description: "values can contain colons: gs://bucket/x",
+				Type:        "python",
+				DependsOn:   []string{"task1", "raw:task2"},
+				Parameters:  map[string]string{"param1": "first", "param2": ""},
+				Connections: map[string]string{"conn1": "first-connection"},
+				Owner:       "jane@example.com",
+				Tags:        []string{"finance", "daily"},
+				Meta:        map[string]string{"tier": "gold"},
+				Policy:      pipeline.ExecutionPolicy{Retries: intPtr(2), RetryDelay: "30s", SLA: "1h"},
+			},
+		},
+		{
+			name: "block",
+			task: &pipeline.Task{
+				Name:        "some-python-task",
+				Description: "first line\n\n  indented second line\n",
+				Type:        "python",
+				DependsOn:   []string{"task1", "a,b"},
+				Parameters:  map[string]string{"param.1": " padded ", "param2": "# not a comment"},
+				Connections: map[string]string{},
+				Team:        "analytics",
+				Tags:        []string{"a,b"},
+				Meta:        map[string]string{"docs.url": "https://wiki.example.com"},
+				Policy:      pipeline.ExecutionPolicy{Pool: "bigquery", Priority: intPtr(-1)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rows, err := pipeline.MarshalComments(tt.task, ".py")
+			require.NoError(t, err)
+
+			filePath := filepath.Join(t.TempDir(), "task.py")
+			require.NoError(t, os.WriteFile(filePath, pipeline.AddComments([]byte("#!/usr/bin/env python3\n# a regular comment\nprint('hello world')\n"), rows), 0o600))
+
+			got, err := pipeline.CreateTaskFromFileComments(filePath)
+			require.NoError(t, err)
+
+			tt.task.ExecutableFile = pipeline.ExecutableFile{Name: "task.py", Path: filePath}
+			assert.Equal(t, tt.task, got)
+		})
+	}
+}
+
+func TestAddAndStripComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		content   string
+		extension string
+		rows      []string
+		want      string
+	}{
+		{
+			name:      "the header is placed at the top",
+			content:   "SELECT 1\n",
+			extension: ".sql",
+			rows:      []string{"-- @blast.name: orders", "-- @blast.type: bq.sql"},
+			want:      "-- @blast.name: orders\n-- @blast.type: bq.sql\n\nSELECT 1\n",
+		},
+		{
+			name:      "the shebang stays on the first line",
+			content:   "#!/usr/bin/env python3\nprint('hello world')\n",
+			extension: ".py",
+			rows:      []string{"# @blast.name: hello", "# @blast.type: python"},
+			want:      "#!/usr/bin/env python3\n# @blast.name: hello\n# @blast.type: python\n\nprint('hello world')\n",
+		},
+		{
+			name:      "blocks are removed entirely",
+			content:   "-- a regular comment\nSELECT 1\n",
+			extension: ".sql",
+			rows:      []string{"-- @blast", "-- name: orders", "-- description: |", "--   first line", "--", "--   second line"},
+			want:      "-- @blast\n-- name: orders\n-- description: |\n--   first line\n--\n--   second line\n\n-- a regular comment\nSELECT 1\n",
+		},
+		{
+			name:      "empty files get only the header",
+			content:   "",
+			extension: ".sql",
+			rows:      []string{"-- @blast.name: orders"},
+			want:      "-- @blast.name: orders\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := pipeline.AddComments([]byte(tt.content), tt.rows)
+			assert.Equal(t, tt.want, string(got))
+			assert.True(t, pipeline.HasComments(got, tt.extension))
+
+			stripped, err := pipeline.StripComments(got, tt.extension)
+			require.NoError(t, err)
+			assert.Equal(t, tt.content, string(stripped))
+			assert.False(t, pipeline.HasComments(stripped, tt.extension))
+		})
+	}
+}
+
+func TestStripComments_BlockComment(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile("testdata/comments/block.sql")
+	require.NoError(t, err)
+
+	stripped, err := pipeline.StripComments(content, ".sql")
+	require.NoError(t, err)
+	assert.Equal(t, "select *\nfrom foo;\n", string(stripped))
+}
+
+func TestStripComments_KeepsTheCommentsAfterTheBlock(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile("testdata/comments/block-with-comments.sql")
+	require.NoError(t, err)
+
+	stripped, err := pipeline.StripComments(content, ".sql")
+	require.NoError(t, err)
+	assert.Equal(t, "-- the regular comments right after the block are not part of it\nselect *\nfrom foo;\n", string(stripped))
+}
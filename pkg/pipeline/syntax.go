@@ -0,0 +1,84 @@
+package pipeline
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// CommentSyntax describes how the annotations are written in the files with a given extension.
+type CommentSyntax struct {
+	// LinePrefix starts a single-line comment, e.g. `--` in SQL. The `@blast.` rows and the `@blast` blocks made of
+	// consecutive line comments use it.
+	LinePrefix string
+
+	// BlockStart and BlockEnd surround a block comment, e.g. `/*` and `*/`, which can hold a `@blast` block. They are
+	// optional, the languages without block comments leave them empty.
+	BlockStart string
+	BlockEnd   string
+
+	// DefaultType is used for the tasks that do not set their type, e.g. `bash` for the shell scripts. It is empty for
+	// the extensions that are used by multiple task types, such as `.sql`.
+	DefaultType string
+}
+
+func (s CommentSyntax) hasBlockComments() bool {
+	return s.BlockStart != "" && s.BlockEnd != ""
+}
+
+// CommentSyntaxes maps the lowercase file extensions, e.g. `.sql`, to the way the annotations are written in them.
+type CommentSyntaxes map[string]CommentSyntax
+
+var builtinCommentSyntaxes = CommentSyntaxes{
+	".sql":   {LinePrefix: "--", BlockStart: "/*", BlockEnd: "*/"},
+	".py":    {LinePrefix: "#", BlockStart: `"""`, BlockEnd: `"""`, DefaultType: "python"},
+	".sh":    {LinePrefix: "#", DefaultType: "bash"},
+	".r":     {LinePrefix: "#"},
+	".js":    {LinePrefix: "//", BlockStart: "/*", BlockEnd: "*/"},
+	".ts":    {LinePrefix: "//", BlockStart: "/*", BlockEnd: "*/"},
+	".scala": {LinePrefix: "//", BlockStart: "/*", BlockEnd: "*/"},
+}
+
+// BuiltinCommentSyntaxes returns a copy of the syntaxes that are supported out of the box, which can be extended with
+// the custom task types of a project.
+func BuiltinCommentSyntaxes() CommentSyntaxes {
+	syntaxes := make(CommentSyntaxes, len(builtinCommentSyntaxes))
+	for extension, syntax := range builtinCommentSyntaxes {
+		syntaxes[extension] = syntax
+	}
+
+	return syntaxes
+}
+
+// Set allows defining tasks with comments in the files with the given extension, replacing the existing syntax for it
+// if there is one. The extensions are matched case-insensitively, e.g. `.R` and `.r` are the same.
+func (s CommentSyntaxes) Set(extension string, syntax CommentSyntax) {
+	s[strings.ToLower(extension)] = syntax
+}
+
+// For returns the comment syntax for the extension of the given file.
+func (s CommentSyntaxes) For(filePath string) (CommentSyntax, bool) {
+	syntax, ok := s[strings.ToLower(filepath.Ext(filePath))]
+	return syntax, ok
+}
+
+// Extensions returns the extensions of the files that can define tasks with comments.
+func (s CommentSyntaxes) Extensions() []string {
+	extensions := make([]string, 0, len(s))
+	for extension := range s {
+		extensions = append(extensions, extension)
+	}
+	sort.Strings(extensions)
+
+	return extensions
+}
+
+// CommentSyntaxFor returns the built-in comment syntax for the extension of the given file.
+func CommentSyntaxFor(filePath string) (CommentSyntax, bool) {
+	return builtinCommentSyntaxes.For(filePath)
+}
+
+// CommentExtensions returns the extensions of the files that can define tasks with comments out of the box.
+func CommentExtensions() []string {
+	return builtinCommentSyntaxes.Extensions()
+}
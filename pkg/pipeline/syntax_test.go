@@ -0,0 +1,108 @@
+package pipeline_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTaskFromFileComments_CommentSyntaxes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		fileName string
+		content  string
+		want     *pipeline.Task
+	}{
+		{
+			name:     "shell scripts default to bash",
+			fileName: "cleanup.sh",
+			content:  "#!/usr/bin/env bash\n# @blast.name: cleanup\n# @blast.depends: orders\n\necho cleanup\n",
+			want:     &pipeline.Task{Name: "cleanup", Type: "bash", DependsOn: []string{"orders"}},
+		},
+		{
+			name:     "the type is not overridden by the default",
+			fileName: "cleanup.sh",
+			content:  "# @blast.name: cleanup\n# @blast.type: custom.bash\n\necho cleanup\n",
+			want:     &pipeline.Task{Name: "cleanup", Type: "custom.bash", DependsOn: []string{}},
+		},
+		{
+			name:     "python files default to python",
+			fileName: "export.py",
+			content:  "# @blast.name: export\n\nprint('hello world')\n",
+			want:     &pipeline.Task{Name: "export", Type: "python", DependsOn: []string{}},
+		},
+		{
+			name:     "R scripts use the hash comments",
+			fileName: "model.R",
+			content:  "# @blast.name: model\n# @blast.type: r\n\nprint(1)\n",
+			want:     &pipeline.Task{Name: "model", Type: "r", DependsOn: []string{}},
+		},
+		{
+			name:     "JavaScript files use the double slash comments",
+			fileName: "udf.js",
+			content:  "// @blast.name: udf\n// @blast.type: bq.udf\n\nfunction add(a, b) { return a + b; }\n",
+			want:     &pipeline.Task{Name: "udf", Type: "bq.udf", DependsOn: []string{}},
+		},
+		{
+			name:     "TypeScript files can use the block comments",
+			fileName: "udf.ts",
+			content:  "/* @blast\n  name: udf\n  type: bq.udf\n  depends: [orders]\n*/\n\nexport const add = (a: number, b: number) => a + b;\n",
+			want:     &pipeline.Task{Name: "udf", Type: "bq.udf", DependsOn: []string{"orders"}},
+		},
+		{
+			name:     "Scala files can use a block of line comments",
+			fileName: "Job.scala",
+			content:  "// @blast\n// name: job\n// type: spark\n\nobject Job\n",
+			want:     &pipeline.Task{Name: "job", Type: "spark", DependsOn: []string{}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			filePath := filepath.Join(t.TempDir(), tt.fileName)
+			require.NoError(t, os.WriteFile(filePath, []byte(tt.content), 0o600))
+
+			got, err := pipeline.CreateTaskFromFileComments(filePath)
+			require.NoError(t, err)
+
+			tt.want.ExecutableFile = pipeline.ExecutableFile{Name: tt.fileName, Path: filePath}
+			tt.want.Parameters = map[string]string{}
+			tt.want.Connections = map[string]string{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCommentSyntaxes_Set(t *testing.T) {
+	t.Parallel()
+
+	filePath := filepath.Join(t.TempDir(), "query.prql")
+	require.NoError(t, os.WriteFile(filePath, []byte("# @blast.name: orders\n\nfrom orders\n"), 0o600))
+
+	syntaxes := pipeline.BuiltinCommentSyntaxes()
+	got, err := syntaxes.CreateTaskFromFileComments(filePath)
+	require.NoError(t, err)
+	assert.Nil(t, got, "the files without a syntax must be skipped")
+
+	syntaxes.Set(".PRQL", pipeline.CommentSyntax{LinePrefix: "#", DefaultType: "bq.prql"})
+	assert.Contains(t, syntaxes.Extensions(), ".prql")
+	assert.NotContains(t, pipeline.CommentExtensions(), ".prql", "the built-in syntaxes must not be changed")
+
+	got, err = syntaxes.CreateTaskFromFileComments(filePath)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "orders", got.Name)
+	assert.Equal(t, "bq.prql", got.Type)
+
+	got, err = pipeline.CreateTaskFromFileComments(filePath)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
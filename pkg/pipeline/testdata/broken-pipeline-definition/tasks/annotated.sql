@@ -0,0 +1,4 @@
+-- @blast.name: annotated
+-- @blast.type: bq.sql
+
+select 1;
@@ -0,0 +1,4 @@
+/* @blast
+name: unclosed
+
+select 1;
@@ -0,0 +1,8 @@
+/* @blast
+name: some-sql-task
+type: bq.sql
+depend:
+  - other-task
+*/
+
+select 1;
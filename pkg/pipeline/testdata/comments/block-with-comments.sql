@@ -0,0 +1,12 @@
+-- @blast
+-- name: some-sql-task
+-- type: bq.sql
+-- description: |
+--   Builds the daily orders.
+--
+--   The source is read from: gs://bucket/orders
+-- depends:
+--   - task1
+-- the regular comments right after the block are not part of it
+select *
+from foo;
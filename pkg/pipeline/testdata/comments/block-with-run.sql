@@ -0,0 +1,6 @@
+/* @blast
+name: some-sql-task
+run: other.sql
+*/
+
+select 1;
@@ -0,0 +1,11 @@
+#!/usr/bin/env python3
+# @blast
+# name: some-python-task
+# description: >
+#   a folded description
+#   with a colon: here
+# type: python
+# depends: [task1, task2]
+
+# a regular comment
+print('hello world')
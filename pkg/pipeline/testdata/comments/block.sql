@@ -0,0 +1,19 @@
+/* @blast
+  name: some-sql-task
+  description: |
+    Builds the daily orders.
+
+    The source is read from: gs://bucket/orders
+  type: bq.sql
+  depends:
+    - task1
+    - raw:task2
+  parameters:
+    source: gs://bucket/orders
+    dataset.table: project.dataset.table
+  connections:
+    gcpConnectionId: gcp
+*/
+
+select *
+from foo;
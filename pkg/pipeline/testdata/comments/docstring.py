@@ -0,0 +1,8 @@
+"""@blast
+name: some-python-task
+type: python
+parameters:
+  url: https://example.com/x
+"""
+
+print('hello world')
@@ -0,0 +1,6 @@
+/* @blast
+name: some-sql-task
+depends: [task1
+*/
+
+select 1;
@@ -0,0 +1,6 @@
+-- @blast.name: some-sql-task
+-- @blast.type: bq.sql
+-- @blast.meta.docs.url: https://wiki.example.com/orders
+
+select *
+from foo;
@@ -0,0 +1,6 @@
+-- @blast.name: some-sql-task
+-- @blast.type: bq.sql
+-- @blast.retries: three
+
+select *
+from foo;
@@ -0,0 +1,4 @@
+-- @blast.name: some-sql-task
+-- @blast.type bq.sql
+
+select 1;
@@ -0,0 +1,11 @@
+-- @blast.name: some-sql-task
+-- @blast.type: bq.sql
+-- @blast.owner: jane@example.com
+-- @blast.team: analytics
+-- @blast.tags: finance, daily
+-- @blast.tags: pii
+-- @blast.meta.tier: gold
+-- @blast.meta.docs: https://wiki.example.com/orders
+
+select *
+from foo;
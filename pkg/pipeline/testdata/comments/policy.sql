@@ -0,0 +1,11 @@
+-- @blast.name: some-sql-task
+-- @blast.type: bq.sql
+-- @blast.retries: 3
+-- @blast.retry_delay: 5m
+-- @blast.timeout: 1h
+-- @blast.sla: 2h
+-- @blast.pool: bigquery
+-- @blast.priority: 10
+
+select *
+from foo;
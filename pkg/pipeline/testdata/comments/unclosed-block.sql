@@ -0,0 +1,4 @@
+/* @blast
+name: some-sql-task
+
+select 1;
@@ -0,0 +1,9 @@
+-- @blast.name: some-sql-task
+-- @blast.type: bq.sql
+-- @blast.dependss: task1
+-- @blast.retires: 3
+-- @blast.parameter.destination: table
+-- @blast.schedule: daily
+
+select *
+from foo;
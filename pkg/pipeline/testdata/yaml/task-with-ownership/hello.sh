@@ -0,0 +1 @@
+echo "hello world from test script"
\ No newline at end of file
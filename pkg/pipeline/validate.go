@@ -0,0 +1,41 @@
+package pipeline
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrEmptyName   = errors.New("The pipeline name cannot be empty, it must be a valid name made of alphanumeric characters, dashes, dots and underscores")
+	ErrInvalidName = errors.New("The pipeline name must be made of alphanumeric characters, dashes, dots and underscores")
+)
+
+var validNameRegex = regexp.MustCompile(`^[\w.-]+$`)
+
+// ValidateName checks that the given pipeline name can be used in the cross-pipeline dependencies and the exports.
+func ValidateName(name string) error {
+	if name == "" {
+		return ErrEmptyName
+	}
+
+	if !validNameRegex.MatchString(name) {
+		return ErrInvalidName
+	}
+
+	return nil
+}
+
+// ValidateSchedule checks that the given schedule is a valid cron expression, an empty schedule is valid.
+func ValidateSchedule(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if _, err := cron.ParseStandard(value); err != nil {
+		return errors.Errorf("Invalid cron schedule '%s'", value)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateName(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateName("my-pipeline.v2_final"))
+	assert.Equal(t, ErrEmptyName, ValidateName(""))
+	assert.Equal(t, ErrInvalidName, ValidateName("my pipeline"))
+}
+
+func TestValidateSchedule(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, ValidateSchedule(""))
+	require.NoError(t, ValidateSchedule("@daily"))
+	require.NoError(t, ValidateSchedule("0 5 * * 1"))
+	require.EqualError(t, ValidateSchedule("some random schedule"), "Invalid cron schedule 'some random schedule'")
+}
@@ -8,13 +8,52 @@ import (
 )
 
 type taskDefinition struct {
-	Name        string            `yaml:"name"`
-	Description string            `yaml:"description"`
-	Type        string            `yaml:"type"`
-	RunFile     string            `yaml:"run"`
-	Depends     []string          `yaml:"depends"`
-	Parameters  map[string]string `yaml:"parameters"`
-	Connections map[string]string `yaml:"connections"`
+	Name        string            `yaml:"name" description:"The unique name of the task in the pipeline."`
+	Description string            `yaml:"description,omitempty" description:"A human-readable description of the task."`
+	Type        string            `yaml:"type" description:"The type of the task, which decides how it runs."`
+	RunFile     string            `yaml:"run,omitempty" description:"The file to run, relative to the task definition."`
+	Depends     []string          `yaml:"depends,omitempty" description:"The tasks that must finish before this one, the tasks in other pipelines are written as pipeline:task."`
+	Parameters  map[string]string `yaml:"parameters,omitempty" description:"The parameters of the task, merged on top of the default parameters of the pipeline."`
+	Connections map[string]string `yaml:"connections,omitempty" description:"The connections of the task, merged on top of the default connections of the pipeline."`
+	Owner       string            `yaml:"owner,omitempty" description:"The person responsible for the task, defaults to the owner of the pipeline."`
+	Team        string            `yaml:"team,omitempty" description:"The team responsible for the task, defaults to the team of the pipeline."`
+	Tags        []string          `yaml:"tags,omitempty" description:"The tags of the task, added to the tags of the pipeline."`
+	Meta        map[string]string `yaml:"meta,omitempty" description:"Free-form metadata, merged on top of the metadata of the pipeline."`
+	Policy      ExecutionPolicy   `yaml:",inline"`
+}
+
+// newTaskDefinition converts the task to its `task.yml` representation, without the file to run.
+func newTaskDefinition(t *Task) taskDefinition {
+	return taskDefinition{
+		Name:        t.Name,
+		Description: t.Description,
+		Type:        t.Type,
+		Depends:     t.DependsOn,
+		Parameters:  t.Parameters,
+		Connections: t.Connections,
+		Owner:       t.Owner,
+		Team:        t.Team,
+		Tags:        t.Tags,
+		Meta:        t.Meta,
+		Policy:      t.Policy,
+	}
+}
+
+// toTask converts the definition to a task, without the executable file.
+func (d taskDefinition) toTask() *Task {
+	return &Task{
+		Name:        d.Name,
+		Description: d.Description,
+		Type:        d.Type,
+		Parameters:  d.Parameters,
+		Connections: d.Connections,
+		DependsOn:   d.Depends,
+		Owner:       d.Owner,
+		Team:        d.Team,
+		Tags:        d.Tags,
+		Meta:        d.Meta,
+		Policy:      d.Policy,
+	}
 }
 
 func CreateTaskFromYamlDefinition(filePath string) (*Task, error) {
@@ -24,7 +63,7 @@ func CreateTaskFromYamlDefinition(filePath string) (*Task, error) {
 	}
 
 	var definition taskDefinition
-	err = path.ReadYaml(filePath, &definition)
+	violations, err := path.ReadYamlLenient(filePath, &definition)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to read the task definition file")
 	}
@@ -41,15 +80,9 @@ func CreateTaskFromYamlDefinition(filePath string) (*Task, error) {
 		executableFile.Path = absRunFile
 	}
 
-	task := Task{
-		Name:           definition.Name,
-		Description:    definition.Description,
-		Type:           definition.Type,
-		Parameters:     definition.Parameters,
-		Connections:    definition.Connections,
-		DependsOn:      definition.Depends,
-		ExecutableFile: executableFile,
-	}
+	task := definition.toTask()
+	task.ExecutableFile = executableFile
+	task.DefinitionFile = DefinitionFile{Violations: violations}
 
-	return &task, nil
+	return task, nil
 }
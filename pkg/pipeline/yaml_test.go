@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/datablast-analytics/blast-cli/pkg/jsonschema"
 	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
 	"github.com/stretchr/testify/require"
 )
@@ -40,6 +41,76 @@ func TestCreateTaskFromYamlDefinition(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unknown and duplicate keys are kept as violations",
+			args: args{
+				filePath: "testdata/yaml/task-with-unknown-key/task.yml",
+			},
+			want: &pipeline.Task{
+				Name: "hello-world",
+				Type: "bash",
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "hello.sh",
+					Path: absPath("testdata/yaml/task-with-unknown-key/hello.sh"),
+				},
+				DependsOn: []string{"second"},
+				DefinitionFile: pipeline.DefinitionFile{
+					Violations: jsonschema.Violations{
+						{
+							Kind:    jsonschema.ViolationUnknownKey,
+							Path:    "depend",
+							Line:    4,
+							Column:  1,
+							Message: "unknown key `depend`, did you mean `depends`?",
+						},
+						{
+							Kind:    jsonschema.ViolationDuplicateKey,
+							Path:    "depends",
+							Line:    8,
+							Column:  1,
+							Message: "duplicate key `depends`, it is already defined on line 6 and only the last value is used",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "reads the ownership fields",
+			args: args{
+				filePath: "testdata/yaml/task-with-ownership/task.yml",
+			},
+			want: &pipeline.Task{
+				Name: "hello-world",
+				Type: "bash",
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "hello.sh",
+					Path: absPath("testdata/yaml/task-with-ownership/hello.sh"),
+				},
+				Owner: "jane@example.com",
+				Team:  "analytics",
+				Tags:  []string{"finance", "daily"},
+				Meta:  map[string]string{"tier": "gold"},
+			},
+		},
+		{
+			name: "reads the execution policy",
+			args: args{
+				filePath: "testdata/yaml/task-with-policy/task.yml",
+			},
+			want: &pipeline.Task{
+				Name: "hello-world",
+				Type: "bash",
+				ExecutableFile: pipeline.ExecutableFile{
+					Name: "hello.sh",
+					Path: absPath("testdata/yaml/task-with-policy/hello.sh"),
+				},
+				Policy: pipeline.ExecutionPolicy{
+					Retries: intPtr(0),
+					Timeout: "10m",
+					Pool:    "local",
+				},
+			},
+		},
 		{
 			name: "reads a valid simple file",
 			args: args{
@@ -0,0 +1,36 @@
+package process
+
+import (
+	"context"
+	"os/exec"
+)
+
+// Run starts the command in its own process group and waits until it exits. The whole group is killed when the
+// context is done, otherwise the children of the command would keep its output open and Run would not return until
+// they exit. The error of the context is returned in that case.
+func Run(ctx context.Context, cmd *exec.Cmd) error {
+	startInProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcessGroup(cmd)
+		case <-done:
+		}
+	}()
+
+	if err := cmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return err
+	}
+
+	return nil
+}
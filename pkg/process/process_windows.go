@@ -0,0 +1,17 @@
+//go:build windows
+
+package process
+
+import (
+	"os/exec"
+)
+
+func startInProcessGroup(cmd *exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+
+	_ = cmd.Process.Kill()
+}
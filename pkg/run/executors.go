@@ -0,0 +1,116 @@
+package run
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/process"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/pkg/errors"
+)
+
+// taskTypes resolves the parameters of the scripts, which are built-in types.
+var taskTypes = tasktype.Builtin()
+
+// ScriptExecutor runs the executable file of the task with the given interpreter from the pipeline directory. The
+// effective parameters of the task are passed as environment variables, the same way as in the exported Airflow DAGs.
+type ScriptExecutor struct {
+	Interpreter string
+}
+
+func (s *ScriptExecutor) Execute(ctx context.Context, p *pipeline.Pipeline, t *pipeline.Task, output io.Writer) error {
+	if t.ExecutableFile.Path == "" {
+		return errors.Errorf("the task '%s' does not have a file to run", t.Name)
+	}
+
+	cmd := exec.Command(s.Interpreter, t.ExecutableFile.Path) //nolint:gosec
+	if err := runInPipeline(ctx, cmd, p, p.EffectiveParameters(t, taskTypes), output); err != nil {
+		return errors.Wrapf(err, "failed to run '%s'", t.ExecutableFile.Path)
+	}
+
+	return nil
+}
+
+// CommandExecutor runs the tasks of a custom type with the shell command rendered out of the command template of the
+// type. The parameters of the task, resolved against the parameters of the type, are available in the template and
+// are passed as environment variables as well.
+type CommandExecutor struct {
+	taskType *tasktype.TaskType
+	command  *template.Template
+}
+
+// commandData is what the command templates can use, e.g. `{{ .Parameters.model }}` or `{{ .File }}`.
+type commandData struct {
+	Name       string
+	Type       string
+	Pipeline   string
+	File       string
+	Parameters map[string]string
+}
+
+func NewCommandExecutor(t *tasktype.TaskType) (*CommandExecutor, error) {
+	command, err := template.New(t.Name).Option("missingkey=error").Parse(t.Command)
+	if err != nil {
+		return nil, errors.Wrapf(err, "the command of the task type '%s' is not a valid template", t.Name)
+	}
+
+	return &CommandExecutor{taskType: t, command: command}, nil
+}
+
+func (c *CommandExecutor) Execute(ctx context.Context, p *pipeline.Pipeline, t *pipeline.Task, output io.Writer) error {
+	parameters := c.taskType.Resolve(t.Parameters, p.DefaultParameters)
+
+	var command strings.Builder
+	err := c.command.Execute(&command, commandData{
+		Name:       t.Name,
+		Type:       t.Type,
+		Pipeline:   p.Name,
+		File:       t.ExecutableFile.Path,
+		Parameters: parameters,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to render the command of the task '%s'", t.Name)
+	}
+
+	cmd := exec.Command("sh", "-c", command.String()) //nolint:gosec
+	if err := runInPipeline(ctx, cmd, p, parameters, output); err != nil {
+		return errors.Wrapf(err, "failed to run '%s'", command.String())
+	}
+
+	return nil
+}
+
+// runInPipeline runs the command from the pipeline directory with the parameters as environment variables, and waits
+// until it exits or the context is done.
+func runInPipeline(ctx context.Context, cmd *exec.Cmd, p *pipeline.Pipeline, parameters map[string]string, output io.Writer) error {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cmd.Dir = filepath.Dir(p.DefinitionFile.Path)
+	cmd.Env = os.Environ()
+	for _, name := range names {
+		cmd.Env = append(cmd.Env, name+"="+parameters[name])
+	}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	return process.Run(ctx, cmd)
+}
+
+// DefaultExecutors returns the executors for the task types that can run locally.
+func DefaultExecutors() map[string]Executor {
+	return map[string]Executor{
+		"bash":   &ScriptExecutor{Interpreter: "bash"},
+		"python": &ScriptExecutor{Interpreter: "python3"},
+	}
+}
@@ -0,0 +1,181 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScriptExecutor_Execute(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "tasks", "hello.sh")
+	require.NoError(t, os.MkdirAll(filepath.Dir(script), 0o755))
+	require.NoError(t, os.WriteFile(script, []byte("echo \"$(basename \"$PWD\") $country $env\"\nexit $EXIT_CODE\n"), 0o600))
+
+	p := &pipeline.Pipeline{
+		DefinitionFile:    pipeline.DefinitionFile{Path: filepath.Join(dir, "pipeline.yml")},
+		DefaultParameters: map[string]string{"env": "production", "EXIT_CODE": "0"},
+	}
+
+	tests := []struct {
+		name       string
+		task       *pipeline.Task
+		wantOutput string
+		wantErr    string
+	}{
+		{
+			name: "parameters are passed as environment variables",
+			task: &pipeline.Task{
+				Name:           "hello",
+				ExecutableFile: pipeline.ExecutableFile{Path: script},
+				Parameters:     map[string]string{"country": "NL"},
+			},
+			wantOutput: filepath.Base(dir) + " NL production\n",
+		},
+		{
+			name: "failing scripts are reported",
+			task: &pipeline.Task{
+				Name:           "hello",
+				ExecutableFile: pipeline.ExecutableFile{Path: script},
+				Parameters:     map[string]string{"EXIT_CODE": "3"},
+			},
+			wantOutput: filepath.Base(dir) + "  production\n",
+			wantErr:    "failed to run '" + script + "': exit status 3",
+		},
+		{
+			name:    "tasks without a file cannot run",
+			task:    &pipeline.Task{Name: "hello"},
+			wantErr: "the task 'hello' does not have a file to run",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var output bytes.Buffer
+			err := (&ScriptExecutor{Interpreter: "bash"}).Execute(context.Background(), p, tt.task, &output)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantOutput, output.String())
+		})
+	}
+}
+
+func TestScriptExecutor_ExecuteStopsTheChildrenOnTimeout(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "slow.sh")
+	require.NoError(t, os.WriteFile(script, []byte("echo started\nsleep 10\n"), 0o600))
+
+	p := &pipeline.Pipeline{DefinitionFile: pipeline.DefinitionFile{Path: filepath.Join(dir, "pipeline.yml")}}
+	task := &pipeline.Task{Name: "slow", ExecutableFile: pipeline.ExecutableFile{Path: script}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	var output bytes.Buffer
+	started := time.Now()
+	err := (&ScriptExecutor{Interpreter: "bash"}).Execute(ctx, p, task, &output)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(started), 5*time.Second)
+	assert.Equal(t, "started\n", output.String())
+}
+
+func TestCommandExecutor_Execute(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	p := &pipeline.Pipeline{
+		Name:              "sales",
+		DefinitionFile:    pipeline.DefinitionFile{Path: filepath.Join(dir, "pipeline.yml")},
+		DefaultParameters: map[string]string{"target": "prod", "unrelated": "value"},
+	}
+	require.NoError(t, os.WriteFile(p.DefinitionFile.Path, []byte("name: sales\n"), 0o600))
+	taskType := &tasktype.TaskType{
+		Name: "dbt.model",
+		Parameters: []tasktype.Parameter{
+			{Name: "model", Type: tasktype.String, Required: true},
+			{Name: "target", Type: tasktype.String},
+			{Name: "threads", Type: tasktype.Int, Default: "4"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		command    string
+		task       *pipeline.Task
+		wantOutput string
+		wantErr    string
+	}{
+		{
+			name:    "the command is rendered with the resolved parameters",
+			command: "echo {{ .Pipeline }} {{ .Name }} {{ .Parameters.model }} --target {{ .Parameters.target }} --threads $threads",
+			task: &pipeline.Task{
+				Name:       "orders",
+				Type:       "dbt.model",
+				Parameters: map[string]string{"model": "orders"},
+			},
+			wantOutput: "sales orders orders --target prod --threads 4\n",
+		},
+		{
+			name:    "the file of the task can be used",
+			command: "cat {{ .File }}",
+			task: &pipeline.Task{
+				Name:           "orders",
+				Type:           "dbt.model",
+				ExecutableFile: pipeline.ExecutableFile{Path: filepath.Join(dir, "pipeline.yml")},
+			},
+			wantOutput: "name: sales\n",
+		},
+		{
+			name:    "failing commands are reported",
+			command: "exit 3",
+			task:    &pipeline.Task{Name: "orders", Type: "dbt.model"},
+			wantErr: "failed to run 'exit 3': exit status 3",
+		},
+		{
+			name:    "missing parameters are reported",
+			command: "echo {{ .Parameters.schema }}",
+			task:    &pipeline.Task{Name: "orders", Type: "dbt.model"},
+			wantErr: "failed to render the command of the task 'orders'",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			customType := *taskType
+			customType.Command = tt.command
+			executor, err := NewCommandExecutor(&customType)
+			require.NoError(t, err)
+
+			var output bytes.Buffer
+			err = executor.Execute(context.Background(), p, tt.task, &output)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantOutput, output.String())
+		})
+	}
+}
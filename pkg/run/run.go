@@ -0,0 +1,461 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Executor runs a single attempt of a task, the output of the task is written to the given writer. The context is
+// cancelled when the attempt times out or the whole run is interrupted.
+type Executor interface {
+	Execute(ctx context.Context, p *pipeline.Pipeline, t *pipeline.Task, output io.Writer) error
+}
+
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+
+	// StatusSkipped is used for the tasks that did not start, either because an upstream task did not succeed or
+	// because the run was interrupted.
+	StatusSkipped Status = "skipped"
+)
+
+type TaskResult struct {
+	Task     *pipeline.Task
+	Status   Status
+	Attempts int
+	Started  time.Time
+	Finished time.Time
+
+	// Err is the error of the last attempt for the failed tasks, and the reason for the skipped ones.
+	Err error
+
+	// SLAMissed is true if the task took longer than its SLA from the start of the first attempt, regardless of
+	// whether it succeeded.
+	SLAMissed bool
+}
+
+// Duration returns the time between the start of the first attempt and the end of the last one, including the delays
+// between the retries.
+func (r *TaskResult) Duration() time.Duration {
+	if r.Started.IsZero() {
+		return 0
+	}
+
+	return r.Finished.Sub(r.Started)
+}
+
+type Result struct {
+	// Tasks are in the order of the tasks in the pipeline.
+	Tasks []*TaskResult
+}
+
+// HasFailures returns true if any of the tasks did not succeed.
+func (r *Result) HasFailures() bool {
+	for _, task := range r.Tasks {
+		if task.Status != StatusSucceeded {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Runner runs the tasks of a pipeline locally in the order of their dependencies, enforcing their execution policies:
+// every attempt is limited by the timeout, the failed attempts are retried after the retry delay, and the tasks in a
+// pool do not run more than the pool allows at the same time. The tasks waiting for a slot start in the order of
+// their priority.
+type Runner struct {
+	Executors map[string]Executor
+	Logger    *zap.SugaredLogger
+
+	// Output receives the output of all the tasks, each line prefixed with the name of the task.
+	Output io.Writer
+
+	// Jobs is the maximum number of tasks running at the same time, defaults to the number of CPUs.
+	Jobs int
+
+	// Pools limits the number of tasks running at the same time in each pool, the tasks in the pools that are not
+	// listed are limited only by Jobs.
+	Pools map[string]int
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+type taskState struct {
+	index      int
+	task       *pipeline.Task
+	policy     *pipeline.Policy
+	result     *TaskResult
+	upstream   int
+	downstream []*taskState
+}
+
+type attemptResult struct {
+	state *taskState
+	err   error
+}
+
+// Run runs all the tasks of the pipeline and waits for them to finish. The policies and the executors are checked
+// before any task starts, the errors of the tasks themselves are reported in the result.
+func (r *Runner) Run(ctx context.Context, p *pipeline.Pipeline) (*Result, error) {
+	states, err := r.prepare(p)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Tasks: make([]*TaskResult, len(states))}
+	ready := make([]*taskState, 0, len(states))
+	for i, state := range states {
+		result.Tasks[i] = state.result
+		if state.upstream == 0 {
+			ready = append(ready, state)
+		}
+	}
+
+	s := &scheduler{
+		runner:      r,
+		pipeline:    p,
+		ready:       ready,
+		poolRunning: make(map[string]int),
+		attempts:    make(chan attemptResult),
+		retries:     make(chan *taskState),
+		remaining:   len(states),
+	}
+
+	return result, s.run(ctx)
+}
+
+func (r *Runner) prepare(p *pipeline.Pipeline) ([]*taskState, error) {
+	for pool, limit := range r.Pools {
+		if limit < 1 {
+			return nil, errors.Errorf("the pool '%s' must allow at least 1 task to run, got %d", pool, limit)
+		}
+	}
+
+	states := make([]*taskState, len(p.Tasks))
+	byName := make(map[string]*taskState, len(p.Tasks))
+	missingExecutors := make(map[string]bool)
+	for i, task := range p.Tasks {
+		policy, err := p.EffectivePolicy(task).Resolve()
+		if err != nil {
+			return nil, errors.Wrapf(err, "the task '%s' has an invalid execution policy", task.Name)
+		}
+
+		if _, ok := r.Executors[task.Type]; !ok {
+			missingExecutors[task.Type] = true
+		}
+
+		states[i] = &taskState{index: i, task: task, policy: policy, result: &TaskResult{Task: task}}
+		byName[task.Name] = states[i]
+	}
+
+	if len(missingExecutors) > 0 {
+		taskTypes := make([]string, 0, len(missingExecutors))
+		for taskType := range missingExecutors {
+			taskTypes = append(taskTypes, taskType)
+		}
+		sort.Strings(taskTypes)
+
+		return nil, errors.Errorf("the tasks of the following types cannot be run locally: %s", strings.Join(taskTypes, ", "))
+	}
+
+	for _, state := range states {
+		for _, dep := range state.task.DependsOn {
+			dependency := pipeline.ParseDependency(dep)
+			if dependency.IsCrossPipeline() && dependency.Pipeline != p.Name {
+				r.Logger.Debugf("the task '%s' depends on '%s' from another pipeline, which is not run", state.task.Name, dep)
+				continue
+			}
+
+			upstream, ok := byName[dependency.Task]
+			if !ok {
+				r.Logger.Debugf("the task '%s' depends on '%s', which is not one of the tasks to run", state.task.Name, dep)
+				continue
+			}
+
+			state.upstream++
+			upstream.downstream = append(upstream.downstream, state)
+		}
+	}
+
+	return states, nil
+}
+
+type scheduler struct {
+	runner   *Runner
+	pipeline *pipeline.Pipeline
+
+	ready       []*taskState
+	running     int
+	poolRunning map[string]int
+	retrying    int
+	remaining   int
+
+	attempts chan attemptResult
+	retries  chan *taskState
+	outputMu sync.Mutex
+}
+
+func (s *scheduler) run(ctx context.Context) error {
+	// done is cleared once the context is done, the loop keeps waiting for the running tasks to stop afterwards.
+	done := ctx.Done()
+	for s.remaining > 0 {
+		if ctx.Err() != nil {
+			for _, state := range s.ready {
+				s.skip(state, errors.Wrap(ctx.Err(), "the run is interrupted"))
+			}
+			s.ready = nil
+		} else {
+			s.startReady(ctx)
+		}
+
+		if s.running == 0 && s.retrying == 0 && len(s.ready) == 0 {
+			if s.remaining == 0 {
+				break
+			}
+
+			return errors.New("the tasks that are left cannot start, their dependencies have a cycle")
+		}
+
+		if s.running == 0 && s.retrying == 0 {
+			return errors.New("the tasks that are left cannot start, their pools do not have any free slots")
+		}
+
+		select {
+		case <-done:
+			done = nil
+		case attempt := <-s.attempts:
+			s.running--
+			s.poolRunning[attempt.state.policy.Pool]--
+			s.handleAttempt(ctx, attempt)
+		case state := <-s.retries:
+			s.retrying--
+			if ctx.Err() != nil {
+				state.result.Status = StatusFailed
+				s.finish(state)
+				continue
+			}
+
+			s.ready = append(s.ready, state)
+		}
+	}
+
+	return nil
+}
+
+// startReady starts the waiting tasks in the order of their priority, the tasks whose pool is full keep waiting while
+// the ones in the other pools can start.
+func (s *scheduler) startReady(ctx context.Context) {
+	sort.SliceStable(s.ready, func(i, j int) bool {
+		if s.ready[i].policy.Priority != s.ready[j].policy.Priority {
+			return s.ready[i].policy.Priority > s.ready[j].policy.Priority
+		}
+
+		return s.ready[i].index < s.ready[j].index
+	})
+
+	waiting := s.ready[:0]
+	for _, state := range s.ready {
+		if s.running >= s.runner.jobs() || !s.hasPoolSlot(state.policy.Pool) {
+			waiting = append(waiting, state)
+			continue
+		}
+
+		s.running++
+		s.poolRunning[state.policy.Pool]++
+		s.start(ctx, state)
+	}
+	s.ready = waiting
+}
+
+func (s *scheduler) hasPoolSlot(pool string) bool {
+	limit, ok := s.runner.Pools[pool]
+	if pool == "" || !ok {
+		return true
+	}
+
+	return s.poolRunning[pool] < limit
+}
+
+func (s *scheduler) start(ctx context.Context, state *taskState) {
+	state.result.Attempts++
+	if state.result.Started.IsZero() {
+		state.result.Started = s.runner.nowFunc()()
+	}
+
+	s.runner.Logger.Debugf("starting attempt %d of the task '%s'", state.result.Attempts, state.task.Name)
+
+	go func() {
+		attemptCtx := ctx
+		if state.policy.Timeout > 0 {
+			var cancel context.CancelFunc
+			attemptCtx, cancel = context.WithTimeout(ctx, state.policy.Timeout)
+			defer cancel()
+		}
+
+		output := &prefixWriter{w: s.runner.Output, mu: &s.outputMu, prefix: fmt.Sprintf("[%s] ", state.task.Name)}
+		err := s.runner.Executors[state.task.Type].Execute(attemptCtx, s.pipeline, state.task, output)
+		output.Flush()
+
+		if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+			err = errors.Errorf("the attempt timed out after %s", state.policy.Timeout)
+		}
+
+		s.attempts <- attemptResult{state: state, err: err}
+	}()
+}
+
+func (s *scheduler) handleAttempt(ctx context.Context, attempt attemptResult) {
+	state := attempt.state
+	if attempt.err == nil {
+		state.result.Status = StatusSucceeded
+		state.result.Err = nil
+		s.finish(state)
+		return
+	}
+
+	if state.result.Attempts > state.policy.Retries || ctx.Err() != nil {
+		state.result.Status = StatusFailed
+		state.result.Err = attempt.err
+		s.finish(state)
+		return
+	}
+
+	s.runner.Logger.Debugf("attempt %d of the task '%s' failed, retrying in %s: %v", state.result.Attempts, state.task.Name, state.policy.RetryDelay, attempt.err)
+
+	// the slot is released while waiting, so that the other tasks can use it in the meantime
+	state.result.Err = attempt.err
+	s.retrying++
+	go func() {
+		_ = s.runner.sleepFunc()(ctx, state.policy.RetryDelay)
+		s.retries <- state
+	}()
+}
+
+func (s *scheduler) finish(state *taskState) {
+	s.remaining--
+	state.result.Finished = s.runner.nowFunc()()
+	if state.policy.SLA > 0 && state.result.Duration() > state.policy.SLA {
+		state.result.SLAMissed = true
+	}
+
+	for _, downstream := range state.downstream {
+		if downstream.result.Status != "" {
+			continue
+		}
+
+		if state.result.Status != StatusSucceeded {
+			s.skip(downstream, errors.Errorf("the upstream task '%s' did not succeed", state.task.Name))
+			continue
+		}
+
+		downstream.upstream--
+		if downstream.upstream == 0 {
+			s.ready = append(s.ready, downstream)
+		}
+	}
+}
+
+// skip marks the task and all its downstream tasks as skipped.
+func (s *scheduler) skip(state *taskState, reason error) {
+	state.result.Status = StatusSkipped
+	state.result.Err = reason
+	s.finish(state)
+}
+
+func (r *Runner) jobs() int {
+	if r.Jobs < 1 {
+		return runtime.NumCPU()
+	}
+
+	return r.Jobs
+}
+
+func (r *Runner) nowFunc() func() time.Time {
+	if r.now != nil {
+		return r.now
+	}
+
+	return time.Now
+}
+
+func (r *Runner) sleepFunc() func(ctx context.Context, d time.Duration) error {
+	if r.sleep != nil {
+		return r.sleep
+	}
+
+	return sleepWithContext
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// prefixWriter prefixes every line with the name of the task, the lines are written as a whole so that the output of
+// the tasks running at the same time does not get mixed within a line.
+type prefixWriter struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	prefix string
+	buf    []byte
+}
+
+func (p *prefixWriter) Write(data []byte) (int, error) {
+	p.buf = append(p.buf, data...)
+	for {
+		end := bytes.IndexByte(p.buf, '\n')
+		if end < 0 {
+			break
+		}
+
+		if err := p.writeLine(p.buf[:end+1]); err != nil {
+			return 0, err
+		}
+		p.buf = p.buf[end+1:]
+	}
+
+	return len(data), nil
+}
+
+// Flush writes the last line even if it does not end with a newline.
+func (p *prefixWriter) Flush() {
+	if len(p.buf) > 0 {
+		_ = p.writeLine(append(p.buf, '\n'))
+		p.buf = nil
+	}
+}
+
+func (p *prefixWriter) writeLine(line []byte) error {
+	if p.w == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err := p.w.Write(append([]byte(p.prefix), line...))
+	return err
+}
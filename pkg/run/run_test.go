@@ -0,0 +1,350 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeExecutor records the order the tasks start in and the maximum number of tasks running at the same time.
+type fakeExecutor struct {
+	mu         sync.Mutex
+	started    []string
+	running    int
+	maxRunning int
+
+	// failures is the number of attempts that fail before the task succeeds, -1 means the task always fails
+	failures map[string]int
+	duration time.Duration
+	blocking map[string]bool
+}
+
+func (f *fakeExecutor) Execute(ctx context.Context, p *pipeline.Pipeline, t *pipeline.Task, output io.Writer) error {
+	f.mu.Lock()
+	f.started = append(f.started, t.Name)
+	f.running++
+	if f.running > f.maxRunning {
+		f.maxRunning = f.running
+	}
+	failures := f.failures[t.Name]
+	if failures > 0 {
+		f.failures[t.Name]--
+	}
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.running--
+		f.mu.Unlock()
+	}()
+
+	if f.blocking[t.Name] {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	time.Sleep(f.duration)
+	_, _ = io.WriteString(output, "hello from "+t.Name+"\nno newline")
+
+	if failures != 0 {
+		return errors.New("task failed")
+	}
+
+	return nil
+}
+
+func newRunner(executor Executor) *Runner {
+	return &Runner{
+		Executors: map[string]Executor{"bash": executor},
+		Logger:    zap.NewNop().Sugar(),
+		Jobs:      4,
+		sleep: func(ctx context.Context, d time.Duration) error {
+			return nil
+		},
+	}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func statuses(result *Result) map[string]Status {
+	got := make(map[string]Status, len(result.Tasks))
+	for _, task := range result.Tasks {
+		got[task.Task.Name] = task.Status
+	}
+
+	return got
+}
+
+func TestRunner_RunFollowsTheDependencies(t *testing.T) {
+	t.Parallel()
+
+	executor := &fakeExecutor{}
+	p := &pipeline.Pipeline{
+		Name: "sales",
+		Tasks: []*pipeline.Task{
+			{Name: "c", Type: "bash", DependsOn: []string{"b", "sales:a"}},
+			{Name: "b", Type: "bash", DependsOn: []string{"a", "other:x", "missing"}},
+			{Name: "a", Type: "bash"},
+		},
+	}
+
+	var output bytes.Buffer
+	r := newRunner(executor)
+	r.Output = &output
+
+	result, err := r.Run(context.Background(), p)
+	require.NoError(t, err)
+	assert.False(t, result.HasFailures())
+	assert.Equal(t, []string{"a", "b", "c"}, executor.started)
+	assert.Equal(t, map[string]Status{"a": StatusSucceeded, "b": StatusSucceeded, "c": StatusSucceeded}, statuses(result))
+	assert.Equal(t, "[a] hello from a\n[a] no newline\n[b] hello from b\n[b] no newline\n[c] hello from c\n[c] no newline\n", output.String())
+}
+
+func TestRunner_RunRetriesTheFailedTasks(t *testing.T) {
+	t.Parallel()
+
+	executor := &fakeExecutor{failures: map[string]int{"flaky": 2, "broken": -1}}
+	p := &pipeline.Pipeline{
+		DefaultPolicy: pipeline.ExecutionPolicy{Retries: intPtr(2), RetryDelay: "1m"},
+		Tasks: []*pipeline.Task{
+			{Name: "flaky", Type: "bash"},
+			{Name: "broken", Type: "bash"},
+			{Name: "no-retries", Type: "bash", Policy: pipeline.ExecutionPolicy{Retries: intPtr(0)}},
+			{Name: "downstream", Type: "bash", DependsOn: []string{"broken"}},
+			{Name: "transitive", Type: "bash", DependsOn: []string{"downstream", "flaky"}},
+		},
+	}
+	executor.failures["no-retries"] = -1
+
+	var delays []time.Duration
+	var mu sync.Mutex
+	r := newRunner(executor)
+	r.sleep = func(ctx context.Context, d time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delays = append(delays, d)
+		return nil
+	}
+
+	result, err := r.Run(context.Background(), p)
+	require.NoError(t, err)
+	assert.True(t, result.HasFailures())
+	assert.Equal(t, map[string]Status{
+		"flaky":      StatusSucceeded,
+		"broken":     StatusFailed,
+		"no-retries": StatusFailed,
+		"downstream": StatusSkipped,
+		"transitive": StatusSkipped,
+	}, statuses(result))
+
+	assert.Equal(t, 3, result.Tasks[0].Attempts)
+	assert.NoError(t, result.Tasks[0].Err)
+	assert.Equal(t, 3, result.Tasks[1].Attempts)
+	assert.EqualError(t, result.Tasks[1].Err, "task failed")
+	assert.Equal(t, 1, result.Tasks[2].Attempts)
+	assert.Equal(t, 0, result.Tasks[3].Attempts)
+	assert.EqualError(t, result.Tasks[3].Err, "the upstream task 'broken' did not succeed")
+	assert.EqualError(t, result.Tasks[4].Err, "the upstream task 'downstream' did not succeed")
+	assert.Equal(t, []time.Duration{time.Minute, time.Minute, time.Minute, time.Minute}, delays)
+}
+
+func TestRunner_RunEnforcesTheTimeouts(t *testing.T) {
+	t.Parallel()
+
+	executor := &fakeExecutor{blocking: map[string]bool{"slow": true}}
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Task{
+			{Name: "slow", Type: "bash", Policy: pipeline.ExecutionPolicy{Timeout: "10ms", Retries: intPtr(1)}},
+		},
+	}
+
+	result, err := newRunner(executor).Run(context.Background(), p)
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, result.Tasks[0].Status)
+	assert.Equal(t, 2, result.Tasks[0].Attempts)
+	assert.EqualError(t, result.Tasks[0].Err, "the attempt timed out after 10ms")
+}
+
+func TestRunner_RunLimitsThePools(t *testing.T) {
+	t.Parallel()
+
+	executor := &fakeExecutor{duration: 10 * time.Millisecond}
+	p := &pipeline.Pipeline{
+		DefaultPolicy: pipeline.ExecutionPolicy{Pool: "bigquery"},
+		Tasks: []*pipeline.Task{
+			{Name: "a", Type: "bash"},
+			{Name: "b", Type: "bash"},
+			{Name: "c", Type: "bash"},
+			{Name: "d", Type: "bash"},
+		},
+	}
+
+	r := newRunner(executor)
+	r.Pools = map[string]int{"bigquery": 1}
+
+	result, err := r.Run(context.Background(), p)
+	require.NoError(t, err)
+	assert.False(t, result.HasFailures())
+	assert.Equal(t, 1, executor.maxRunning)
+}
+
+func TestRunner_RunStartsTheHigherPrioritiesFirst(t *testing.T) {
+	t.Parallel()
+
+	executor := &fakeExecutor{}
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Task{
+			{Name: "low", Type: "bash", Policy: pipeline.ExecutionPolicy{Priority: intPtr(-1)}},
+			{Name: "default", Type: "bash"},
+			{Name: "high", Type: "bash", Policy: pipeline.ExecutionPolicy{Priority: intPtr(10)}},
+			{Name: "also-default", Type: "bash"},
+		},
+	}
+
+	r := newRunner(executor)
+	r.Jobs = 1
+
+	_, err := r.Run(context.Background(), p)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"high", "default", "also-default", "low"}, executor.started)
+}
+
+func TestRunner_RunReportsTheMissedSLAs(t *testing.T) {
+	t.Parallel()
+
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Task{
+			{Name: "late", Type: "bash", Policy: pipeline.ExecutionPolicy{SLA: "1h"}},
+			{Name: "on-time", Type: "bash", Policy: pipeline.ExecutionPolicy{SLA: "3h"}},
+		},
+	}
+
+	var mu sync.Mutex
+	now := time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC)
+	r := newRunner(&fakeExecutor{})
+	r.Jobs = 1
+	r.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(time.Hour)
+		return now
+	}
+
+	result, err := r.Run(context.Background(), p)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, result.Tasks[0].Duration())
+	assert.False(t, result.Tasks[0].SLAMissed)
+	assert.Equal(t, time.Hour, result.Tasks[1].Duration())
+	assert.False(t, result.Tasks[1].SLAMissed)
+
+	p.Tasks[0].Policy.SLA = "30m"
+	result, err = r.Run(context.Background(), p)
+	require.NoError(t, err)
+	assert.True(t, result.Tasks[0].SLAMissed)
+}
+
+func TestRunner_RunSkipsTheTasksWhenInterrupted(t *testing.T) {
+	t.Parallel()
+
+	executor := &fakeExecutor{blocking: map[string]bool{"blocking": true}}
+	p := &pipeline.Pipeline{
+		Tasks: []*pipeline.Task{
+			{Name: "blocking", Type: "bash"},
+			{Name: "downstream", Type: "bash", DependsOn: []string{"blocking"}},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	result, err := newRunner(executor).Run(ctx, p)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Status{"blocking": StatusFailed, "downstream": StatusSkipped}, statuses(result))
+	assert.ErrorIs(t, result.Tasks[0].Err, context.DeadlineExceeded)
+}
+
+func TestRunner_RunSkipsTheTasksWaitingForAPoolWhenInterrupted(t *testing.T) {
+	t.Parallel()
+
+	executor := &fakeExecutor{blocking: map[string]bool{"blocking": true, "waiting": true}}
+	p := &pipeline.Pipeline{
+		DefaultPolicy: pipeline.ExecutionPolicy{Pool: "bigquery"},
+		Tasks: []*pipeline.Task{
+			{Name: "blocking", Type: "bash"},
+			{Name: "waiting", Type: "bash"},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	r := newRunner(executor)
+	r.Pools = map[string]int{"bigquery": 1}
+
+	result, err := r.Run(ctx, p)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Status{"blocking": StatusFailed, "waiting": StatusSkipped}, statuses(result))
+	assert.Equal(t, []string{"blocking"}, executor.started)
+}
+
+func TestRunner_RunRefusesToStart(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		tasks   []*pipeline.Task
+		pools   map[string]int
+		wantErr string
+	}{
+		{
+			name:    "tasks without an executor",
+			tasks:   []*pipeline.Task{{Name: "a", Type: "bq.sql"}, {Name: "b", Type: "bash"}, {Name: "c", Type: "sf.sql"}},
+			wantErr: "the tasks of the following types cannot be run locally: bq.sql, sf.sql",
+		},
+		{
+			name:    "invalid policies",
+			tasks:   []*pipeline.Task{{Name: "a", Type: "bash", Policy: pipeline.ExecutionPolicy{RetryDelay: "soon"}}},
+			wantErr: "the task 'a' has an invalid execution policy: invalid retry_delay 'soon', use a duration such as 30s, 5m or 1h30m",
+		},
+		{
+			name:    "cyclic dependencies",
+			tasks:   []*pipeline.Task{{Name: "a", Type: "bash", DependsOn: []string{"b"}}, {Name: "b", Type: "bash", DependsOn: []string{"a"}}},
+			wantErr: "the tasks that are left cannot start, their dependencies have a cycle",
+		},
+		{
+			name:    "empty pools",
+			tasks:   []*pipeline.Task{{Name: "a", Type: "bash", Policy: pipeline.ExecutionPolicy{Pool: "bigquery"}}},
+			pools:   map[string]int{"bigquery": 0},
+			wantErr: "the pool 'bigquery' must allow at least 1 task to run, got 0",
+		},
+		{
+			name:    "negative pools",
+			tasks:   []*pipeline.Task{{Name: "a", Type: "bash"}},
+			pools:   map[string]int{"bigquery": -1},
+			wantErr: "the pool 'bigquery' must allow at least 1 task to run, got -1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newRunner(&fakeExecutor{})
+			r.Pools = tt.pools
+
+			_, err := r.Run(context.Background(), &pipeline.Pipeline{Tasks: tt.tasks})
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	StyleComment = "comment"
+	StyleYaml    = "yaml"
+
+	DefaultSchedule = "@daily"
+
+	pipelineFileName  = "pipeline.yml"
+	tasksDirName      = "tasks"
+	taskFileName      = "task.yml"
+	folderPermissions = 0o755
+	filePermissions   = 0o644
+
+	// the files of the script types are run as programs, the `valid-executable-file` rule requires them to be executable
+	scriptPermissions = 0o755
+)
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+var validNameRegex = regexp.MustCompile(`^[\w.-]+$`)
+
+// runFiles are the names of the files the built-in task types run, the other types run `run<extension>`.
+var runFiles = map[string]string{
+	".sql": "query.sql",
+	".py":  "main.py",
+	".sh":  "run.sh",
+}
+
+// defaultTemplate is used for the task types without a template of their own, e.g. the custom types.
+const defaultTemplate = "default"
+
+// TaskTypes returns the built-in task types that can be scaffolded.
+func TaskTypes() []string {
+	return scaffoldedTypes(tasktype.Builtin())
+}
+
+// scaffoldedTypes returns the task types in the registry that run a file, the other ones cannot be scaffolded.
+func scaffoldedTypes(registry *tasktype.Registry) []string {
+	types := make([]string, 0)
+	for _, name := range registry.Names() {
+		if registry.Get(name).Extension != "" {
+			types = append(types, name)
+		}
+	}
+
+	return types
+}
+
+type TaskOptions struct {
+	Name      string
+	Type      string
+	DependsOn []string
+
+	// Style is either StyleComment or StyleYaml, it defaults to the comment style when the task type supports it.
+	Style string
+}
+
+type taskTemplateData struct {
+	Name      string
+	Type      string
+	DependsOn []string
+	RunFile   string
+
+	// CommentPrefix starts the comment lines in the file the task runs, it is empty when the file has no comments.
+	CommentPrefix string
+}
+
+// Scaffolder creates new pipelines and tasks out of templates. The templates are looked up in the templates directory first,
+// which allows overriding the built-in ones per repository by placing a file with the same relative path there.
+type Scaffolder struct {
+	fs           afero.Fs
+	templatesDir string
+	taskTypes    *tasktype.Registry
+	syntaxes     pipeline.CommentSyntaxes
+}
+
+func NewScaffolder(fs afero.Fs, templatesDir string) *Scaffolder {
+	return &Scaffolder{
+		fs:           fs,
+		templatesDir: templatesDir,
+		taskTypes:    tasktype.Builtin(),
+		syntaxes:     pipeline.BuiltinCommentSyntaxes(),
+	}
+}
+
+// SetTaskTypes replaces the built-in task types with the given ones, e.g. to scaffold the custom types as well.
+func (s *Scaffolder) SetTaskTypes(registry *tasktype.Registry) {
+	s.taskTypes = registry
+}
+
+// SetCommentSyntaxes replaces the built-in comment syntaxes with the given ones, e.g. to annotate the tasks of the
+// custom types with comments as well.
+func (s *Scaffolder) SetCommentSyntaxes(syntaxes pipeline.CommentSyntaxes) {
+	s.syntaxes = syntaxes
+}
+
+// CreatePipeline creates the pipeline directory with a `pipeline.yml` file and an empty tasks directory, and returns
+// the paths of the created files.
+func (s *Scaffolder) CreatePipeline(dir, name, schedule string) ([]string, error) {
+	if schedule == "" {
+		schedule = DefaultSchedule
+	}
+
+	if exists, _ := afero.Exists(s.fs, dir); exists {
+		return nil, errors.Errorf("the directory '%s' already exists", dir)
+	}
+
+	content, err := s.render(pipelineFileName, struct{ Name, Schedule string }{Name: name, Schedule: schedule})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePipeline(content); err != nil {
+		return nil, err
+	}
+
+	if err := s.fs.MkdirAll(filepath.Join(dir, tasksDirName), folderPermissions); err != nil {
+		return nil, errors.Wrapf(err, "failed to create the pipeline directory '%s'", dir)
+	}
+
+	pipelineFile := filepath.Join(dir, pipelineFileName)
+	if err := s.writeFile(pipelineFile, content, filePermissions); err != nil {
+		return nil, err
+	}
+
+	return []string{pipelineFile}, nil
+}
+
+// validatePipeline checks the rendered definition with the same rules as `blast validate`, so that the new pipelines
+// are valid right away, regardless of the template they are created from.
+func validatePipeline(content []byte) error {
+	var p pipeline.Pipeline
+	if err := yaml.Unmarshal(content, &p); err != nil {
+		return errors.Wrap(err, "the rendered pipeline definition is not valid YAML")
+	}
+
+	if err := pipeline.ValidateName(p.Name); err != nil {
+		return err
+	}
+
+	return pipeline.ValidateSchedule(string(p.Schedule))
+}
+
+// CreateTask writes the files for a new task into the given directory and returns their paths. The comment style
+// creates a single annotated file, while the yaml style creates a folder with a `task.yml` and the file to run.
+func (s *Scaffolder) CreateTask(dir string, opts TaskOptions) ([]string, error) {
+	t := s.taskTypes.Get(opts.Type)
+	if t == nil || t.Extension == "" {
+		return nil, errors.Errorf("cannot create a task of type '%s', the supported types are: %s", opts.Type, strings.Join(scaffoldedTypes(s.taskTypes), ", "))
+	}
+
+	if !validNameRegex.MatchString(opts.Name) {
+		return nil, errors.Errorf("invalid task name '%s', it must be made of alphanumeric characters, dashes, dots and underscores", opts.Name)
+	}
+
+	syntax, hasCommentSyntax := s.syntaxes.For(t.Extension)
+	style := opts.Style
+	if style == "" {
+		style = StyleYaml
+		if hasCommentSyntax {
+			style = StyleComment
+		}
+	}
+
+	runFile, ok := runFiles[t.Extension]
+	if !ok {
+		runFile = "run" + t.Extension
+	}
+
+	data := taskTemplateData{
+		Name:          opts.Name,
+		Type:          opts.Type,
+		DependsOn:     opts.DependsOn,
+		RunFile:       runFile,
+		CommentPrefix: syntax.LinePrefix,
+	}
+
+	switch style {
+	case StyleComment:
+		if !hasCommentSyntax {
+			return nil, errors.Errorf("the '%s' tasks cannot be defined with comments, use the '%s' style instead", opts.Type, StyleYaml)
+		}
+
+		target := filepath.Join(dir, opts.Name+t.Extension)
+		return s.createFiles(map[string]string{
+			target: s.taskTemplate(StyleComment, t),
+		}, data, runFilePermissions(t, target))
+	case StyleYaml:
+		taskDir := filepath.Join(dir, opts.Name)
+		target := filepath.Join(taskDir, runFile)
+		return s.createFiles(map[string]string{
+			filepath.Join(taskDir, taskFileName): path.Join("tasks", StyleYaml, taskFileName),
+			target:                               s.taskTemplate(StyleYaml, t),
+		}, data, runFilePermissions(t, target))
+	}
+
+	return nil, errors.Errorf("unknown task style '%s', it must be either '%s' or '%s'", style, StyleComment, StyleYaml)
+}
+
+// taskTemplate returns the template for the file the given type runs, e.g. `tasks/yaml/bq.sql` or
+// `tasks/comment/python.py`, falling back to the default template when there is no template for the type.
+func (s *Scaffolder) taskTemplate(style string, t *tasktype.TaskType) string {
+	name := t.Name + t.Extension
+	if strings.HasSuffix(t.Name, t.Extension) {
+		name = t.Name
+	}
+
+	name = path.Join("tasks", style, name)
+	if s.hasTemplate(name) {
+		return name
+	}
+
+	return path.Join("tasks", style, defaultTemplate)
+}
+
+// runFilePermissions returns the permissions of the files written for a task, only the run files of the script types
+// are executable.
+func runFilePermissions(t *tasktype.TaskType, runFile string) map[string]os.FileMode {
+	if !t.Script {
+		return nil
+	}
+
+	return map[string]os.FileMode{runFile: scriptPermissions}
+}
+
+// createFiles renders all the templates before writing anything, which avoids leaving half-created tasks behind. The
+// files that are not in the given permissions are written with the default file permissions.
+func (s *Scaffolder) createFiles(files map[string]string, data interface{}, permissions map[string]os.FileMode) ([]string, error) {
+	targets := make([]string, 0, len(files))
+	for target := range files {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	contents := make(map[string][]byte, len(files))
+	for _, target := range targets {
+		if exists, _ := afero.Exists(s.fs, target); exists {
+			return nil, errors.Errorf("the file '%s' already exists", target)
+		}
+
+		content, err := s.render(files[target], data)
+		if err != nil {
+			return nil, err
+		}
+
+		contents[target] = content
+	}
+
+	for _, target := range targets {
+		if err := s.fs.MkdirAll(filepath.Dir(target), folderPermissions); err != nil {
+			return nil, errors.Wrapf(err, "failed to create the directory for '%s'", target)
+		}
+
+		perm, ok := permissions[target]
+		if !ok {
+			perm = filePermissions
+		}
+
+		if err := s.writeFile(target, contents[target], perm); err != nil {
+			return nil, err
+		}
+	}
+
+	return targets, nil
+}
+
+func (s *Scaffolder) render(name string, data interface{}) ([]byte, error) {
+	content, err := s.readTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(name).Funcs(template.FuncMap{"join": strings.Join}).Parse(string(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the template '%s'", name)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, errors.Wrapf(err, "failed to render the template '%s'", name)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (s *Scaffolder) hasTemplate(name string) bool {
+	if s.templatesDir != "" {
+		if exists, _ := afero.Exists(s.fs, filepath.Join(s.templatesDir, filepath.FromSlash(name))); exists {
+			return true
+		}
+	}
+
+	_, err := fs.Stat(builtinTemplates, path.Join("templates", name))
+	return err == nil
+}
+
+func (s *Scaffolder) readTemplate(name string) ([]byte, error) {
+	if s.templatesDir != "" {
+		content, err := afero.ReadFile(s.fs, filepath.Join(s.templatesDir, filepath.FromSlash(name)))
+		if err == nil {
+			return content, nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, errors.Wrapf(err, "failed to read the template '%s' from '%s'", name, s.templatesDir)
+		}
+	}
+
+	content, err := fs.ReadFile(builtinTemplates, path.Join("templates", name))
+	if err != nil {
+		return nil, fmt.Errorf("there is no template named '%s'", name)
+	}
+
+	return content, nil
+}
+
+func (s *Scaffolder) writeFile(target string, content []byte, perm os.FileMode) error {
+	if err := afero.WriteFile(s.fs, target, content, perm); err != nil {
+		return errors.Wrapf(err, "failed to write the file '%s'", target)
+	}
+
+	return nil
+}
@@ -0,0 +1,388 @@
+package scaffold
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/datablast-analytics/blast-cli/pkg/lint"
+	"github.com/datablast-analytics/blast-cli/pkg/path"
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestScaffolder_CreatePipeline(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		pipelineName string
+		schedule     string
+		existing     string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "pipeline is created with the default schedule",
+			pipelineName: "sales",
+			want:         "name: sales\nschedule: \"@daily\"\ndefaultParameters: {}\ndefaultConnections: {}\n",
+		},
+		{
+			name:         "pipeline is created with the given schedule",
+			pipelineName: "sales",
+			schedule:     "0 5 * * *",
+			want:         "name: sales\nschedule: \"0 5 * * *\"\ndefaultParameters: {}\ndefaultConnections: {}\n",
+		},
+		{
+			name:         "invalid names are rejected",
+			pipelineName: "sales pipeline",
+			wantErr:      true,
+		},
+		{
+			name:         "invalid schedules are rejected",
+			pipelineName: "sales",
+			schedule:     "every day",
+			wantErr:      true,
+		},
+		{
+			name:         "existing directories are not touched",
+			pipelineName: "sales",
+			existing:     "/repo/sales/pipeline.yml",
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			if tt.existing != "" {
+				require.NoError(t, afero.WriteFile(fs, tt.existing, []byte("name: existing"), 0o644))
+			}
+
+			files, err := NewScaffolder(fs, "").CreatePipeline("/repo/sales", tt.pipelineName, tt.schedule)
+			if tt.wantErr {
+				require.Error(t, err)
+				if tt.existing != "" {
+					content, err := afero.ReadFile(fs, tt.existing)
+					require.NoError(t, err)
+					assert.Equal(t, "name: existing", string(content))
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{"/repo/sales/pipeline.yml"}, files)
+
+			content, err := afero.ReadFile(fs, "/repo/sales/pipeline.yml")
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(content))
+
+			isDir, err := afero.IsDir(fs, "/repo/sales/tasks")
+			require.NoError(t, err)
+			assert.True(t, isDir)
+		})
+	}
+}
+
+func TestScaffolder_CreateTask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    TaskOptions
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "sql tasks are created with comments by default",
+			opts: TaskOptions{Name: "orders", Type: "bq.sql", DependsOn: []string{"customers", "raw:orders"}},
+			want: map[string]string{
+				"/tasks/orders.sql": "-- @blast.name: orders\n-- @blast.type: bq.sql\n-- @blast.depends: customers, raw:orders\n\nSELECT 1\n",
+			},
+		},
+		{
+			name: "python tasks without dependencies",
+			opts: TaskOptions{Name: "export", Type: "python"},
+			want: map[string]string{
+				"/tasks/export.py": "# @blast.name: export\n# @blast.type: python\n\n\ndef main():\n    print(\"hello from export\")\n\n\nif __name__ == \"__main__\":\n    main()\n",
+			},
+		},
+		{
+			name: "bash tasks are created with comments by default",
+			opts: TaskOptions{Name: "cleanup", Type: "bash", DependsOn: []string{"orders"}},
+			want: map[string]string{
+				"/tasks/cleanup.sh": "#!/usr/bin/env bash\n# @blast.name: cleanup\n# @blast.type: bash\n# @blast.depends: orders\n\nset -euo pipefail\n\necho \"hello from cleanup\"\n",
+			},
+		},
+		{
+			name: "bash tasks can use a task definition",
+			opts: TaskOptions{Name: "cleanup", Type: "bash", DependsOn: []string{"orders"}, Style: StyleYaml},
+			want: map[string]string{
+				"/tasks/cleanup/task.yml": "name: cleanup\ntype: bash\nrun: run.sh\ndepends:\n  - orders\nparameters: {}\nconnections: {}\n",
+				"/tasks/cleanup/run.sh":   "#!/usr/bin/env bash\nset -euo pipefail\n\necho \"hello from cleanup\"\n",
+			},
+		},
+		{
+			name: "sql tasks can use a task definition",
+			opts: TaskOptions{Name: "orders", Type: "sf.sql", Style: StyleYaml},
+			want: map[string]string{
+				"/tasks/orders/task.yml":  "name: orders\ntype: sf.sql\nrun: query.sql\nparameters: {}\nconnections: {}\n",
+				"/tasks/orders/query.sql": "SELECT 1\n",
+			},
+		},
+		{
+			name:    "unknown types are rejected",
+			opts:    TaskOptions{Name: "orders", Type: "bq.sensor.table"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown styles are rejected",
+			opts:    TaskOptions{Name: "orders", Type: "bq.sql", Style: "json"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid names are rejected",
+			opts:    TaskOptions{Name: "../orders", Type: "bq.sql"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			files, err := NewScaffolder(fs, "").CreateTask("/tasks", tt.opts)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, files, len(tt.want))
+			for file, want := range tt.want {
+				assert.Contains(t, files, file)
+
+				content, err := afero.ReadFile(fs, file)
+				require.NoError(t, err)
+				assert.Equal(t, want, string(content))
+			}
+		})
+	}
+}
+
+func TestScaffolder_CreateCustomTask(t *testing.T) {
+	t.Parallel()
+
+	registry, err := tasktype.Load(map[string]config.TaskType{
+		"dbt.model":   {Extension: ".sql"},
+		"prql.query":  {Extension: ".prql"},
+		"http.sensor": {},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		opts    TaskOptions
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "custom tasks are created with comments when the extension supports them",
+			opts: TaskOptions{Name: "orders", Type: "dbt.model", DependsOn: []string{"customers"}},
+			want: map[string]string{
+				"/tasks/orders.sql": "-- @blast.name: orders\n-- @blast.type: dbt.model\n-- @blast.depends: customers\n",
+			},
+		},
+		{
+			name: "custom tasks can use a task definition",
+			opts: TaskOptions{Name: "orders", Type: "dbt.model", Style: StyleYaml},
+			want: map[string]string{
+				"/tasks/orders/task.yml":  "name: orders\ntype: dbt.model\nrun: query.sql\nparameters: {}\nconnections: {}\n",
+				"/tasks/orders/query.sql": "-- orders is a dbt.model task\n",
+			},
+		},
+		{
+			name: "custom tasks use a task definition when the extension does not support comments",
+			opts: TaskOptions{Name: "orders", Type: "prql.query"},
+			want: map[string]string{
+				"/tasks/orders/task.yml": "name: orders\ntype: prql.query\nrun: run.prql\nparameters: {}\nconnections: {}\n",
+				"/tasks/orders/run.prql": "\n",
+			},
+		},
+		{
+			name:    "custom types without an extension are rejected",
+			opts:    TaskOptions{Name: "orders", Type: "http.sensor"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := afero.NewMemMapFs()
+			s := NewScaffolder(fs, "")
+			s.SetTaskTypes(registry)
+
+			files, err := s.CreateTask("/tasks", tt.opts)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, files, len(tt.want))
+			for file, want := range tt.want {
+				content, err := afero.ReadFile(fs, file)
+				require.NoError(t, err)
+				assert.Equal(t, want, string(content))
+			}
+		})
+	}
+}
+
+func TestScaffolder_CreateTaskPermissions(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	s := NewScaffolder(fs, "")
+
+	_, err := s.CreateTask("/tasks", TaskOptions{Name: "orders", Type: "bq.sql"})
+	require.NoError(t, err)
+	_, err = s.CreateTask("/tasks", TaskOptions{Name: "export", Type: "bash"})
+	require.NoError(t, err)
+	_, err = s.CreateTask("/tasks", TaskOptions{Name: "train", Type: "python", Style: StyleYaml})
+	require.NoError(t, err)
+
+	want := map[string]os.FileMode{
+		"/tasks/orders.sql":     0o644,
+		"/tasks/export.sh":      0o755,
+		"/tasks/train/task.yml": 0o644,
+		"/tasks/train/main.py":  0o755,
+	}
+	for file, mode := range want {
+		info, err := fs.Stat(file)
+		require.NoError(t, err)
+		assert.Equal(t, mode, info.Mode().Perm(), file)
+	}
+}
+
+func TestScaffolder_CreateTaskDoesNotOverwriteFiles(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/tasks/cleanup/run.sh", []byte("echo existing"), 0o644))
+
+	_, err := NewScaffolder(fs, "").CreateTask("/tasks", TaskOptions{Name: "cleanup", Type: "bash", Style: StyleYaml})
+	require.Error(t, err)
+
+	exists, err := afero.Exists(fs, "/tasks/cleanup/task.yml")
+	require.NoError(t, err)
+	assert.False(t, exists, "no files must be created if any of them exists")
+
+	content, err := afero.ReadFile(fs, "/tasks/cleanup/run.sh")
+	require.NoError(t, err)
+	assert.Equal(t, "echo existing", string(content))
+}
+
+func TestScaffolder_TemplatesCanBeOverridden(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/repo/.blast/templates/tasks/comment/bq.sql", []byte("-- @blast.name: {{ .Name }}\n-- @blast.type: {{ .Type }}\n\nSELECT * FROM `project.dataset.{{ .Name }}`\n"), 0o644))
+
+	s := NewScaffolder(fs, "/repo/.blast/templates")
+	_, err := s.CreateTask("/tasks", TaskOptions{Name: "orders", Type: "bq.sql"})
+	require.NoError(t, err)
+
+	content, err := afero.ReadFile(fs, "/tasks/orders.sql")
+	require.NoError(t, err)
+	assert.Equal(t, "-- @blast.name: orders\n-- @blast.type: bq.sql\n\nSELECT * FROM `project.dataset.orders`\n", string(content))
+
+	// the templates that are not overridden fall back to the built-in ones
+	_, err = s.CreateTask("/tasks", TaskOptions{Name: "export", Type: "python"})
+	require.NoError(t, err)
+
+	content, err = afero.ReadFile(fs, "/tasks/export.py")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# @blast.name: export\n")
+}
+
+func TestScaffolder_OverriddenPipelineTemplatesAreValidated(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/templates/pipeline.yml", []byte("name: {{ .Name }} pipeline\n"), 0o644))
+
+	_, err := NewScaffolder(fs, "/templates").CreatePipeline("/repo/sales", "sales", "")
+	require.Error(t, err)
+
+	exists, err := afero.Exists(fs, "/repo/sales")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestScaffolder_CreatedPipelinesPassTheLinter(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	pipelineDir := filepath.Join(root, "sales")
+	tasksDir := filepath.Join(pipelineDir, tasksDirName)
+
+	s := NewScaffolder(afero.NewOsFs(), "")
+	_, err := s.CreatePipeline(pipelineDir, "sales", "")
+	require.NoError(t, err)
+
+	tasks := []TaskOptions{
+		{Name: "customers", Type: "bq.sql"},
+		{Name: "orders", Type: "sf.sql", DependsOn: []string{"customers"}, Style: StyleYaml},
+		{Name: "export", Type: "python", DependsOn: []string{"customers", "orders"}},
+		{Name: "cleanup", Type: "bash", DependsOn: []string{"export"}},
+		{Name: "archive", Type: "bash", DependsOn: []string{"cleanup"}, Style: StyleYaml},
+		{Name: "report", Type: "python", DependsOn: []string{"archive"}, Style: StyleYaml},
+	}
+	for _, task := range tasks {
+		_, err := s.CreateTask(tasksDir, task)
+		require.NoError(t, err)
+	}
+
+	builder := pipeline.NewBuilder(pipeline.BuilderConfig{
+		PipelineFileName:   pipelineFileName,
+		TasksDirectoryName: tasksDirName,
+		TasksFileName:      taskFileName,
+	}, pipeline.CreateTaskFromYamlDefinition, pipeline.CreateTaskFromFileComments)
+
+	p, err := builder.CreatePipelineFromPath(pipelineDir)
+	require.NoError(t, err)
+	require.Len(t, p.Tasks, len(tasks))
+
+	for _, task := range tasks {
+		created := p.GetTaskByName(task.Name)
+		require.NotNil(t, created, "task '%s' is missing", task.Name)
+		assert.Equal(t, task.Type, created.Type)
+		assert.ElementsMatch(t, task.DependsOn, created.DependsOn)
+	}
+
+	logger := zap.NewNop().Sugar()
+	rules, err := lint.GetRules(logger, &config.Config{}, tasktype.Builtin())
+	require.NoError(t, err)
+
+	result, err := lint.NewLinter(path.GetPipelinePaths, builder, rules, logger).Lint(context.Background(), root, pipelineFileName)
+	require.NoError(t, err)
+	for _, pipelineIssues := range result.Pipelines {
+		for rule, issues := range pipelineIssues.Issues {
+			for _, issue := range issues {
+				t.Errorf("rule '%s' reported an issue: %s", rule.Name(), issue.Description)
+			}
+		}
+	}
+}
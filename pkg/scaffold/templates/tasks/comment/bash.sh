@@ -0,0 +1,10 @@
+#!/usr/bin/env bash
+# @blast.name: {{ .Name }}
+# @blast.type: {{ .Type }}
+{{- if .DependsOn }}
+# @blast.depends: {{ join .DependsOn ", " }}
+{{- end }}
+
+set -euo pipefail
+
+echo "hello from {{ .Name }}"
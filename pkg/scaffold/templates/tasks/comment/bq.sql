@@ -0,0 +1,7 @@
+-- @blast.name: {{ .Name }}
+-- @blast.type: {{ .Type }}
+{{- if .DependsOn }}
+-- @blast.depends: {{ join .DependsOn ", " }}
+{{- end }}
+
+SELECT 1
@@ -0,0 +1,13 @@
+# @blast.name: {{ .Name }}
+# @blast.type: {{ .Type }}
+{{- if .DependsOn }}
+# @blast.depends: {{ join .DependsOn ", " }}
+{{- end }}
+
+
+def main():
+    print("hello from {{ .Name }}")
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,6 @@
+def main():
+    print("hello from {{ .Name }}")
+
+
+if __name__ == "__main__":
+    main()
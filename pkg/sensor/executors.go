@@ -0,0 +1,104 @@
+package sensor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// TableChecker checks if a BigQuery table exists, it is implemented by bigquery.DB.
+type TableChecker interface {
+	TableExists(ctx context.Context, projectID, datasetID, tableID string) (bool, error)
+}
+
+// Querier runs a query and returns the first column of its first row, it is implemented by bigquery.DB.
+type Querier interface {
+	FirstValue(ctx context.Context, sql string) (interface{}, error)
+}
+
+// NewTableSensor waits for the table given with `project_id`, `dataset_id` and `table_id` to exist.
+func NewTableSensor(tables TableChecker) *Executor {
+	return &Executor{
+		TaskType: TypeBigqueryTable,
+		condition: func(parameters map[string]string) (string, Condition) {
+			projectID, datasetID, tableID := parameters["project_id"], parameters["dataset_id"], parameters["table_id"]
+
+			return fmt.Sprintf("the table '%s.%s.%s'", projectID, datasetID, tableID), func(ctx context.Context) (bool, error) {
+				return tables.TableExists(ctx, projectID, datasetID, tableID)
+			}
+		},
+	}
+}
+
+// NewQuerySensor waits for the query given with `sql` to return a value in its first cell other than NULL, false, zero
+// or an empty string, the same way as the Airflow SQL sensor. The query is run as it is, without rendering templates.
+func NewQuerySensor(querier Querier) *Executor {
+	return &Executor{
+		TaskType: TypeBigqueryQuery,
+		condition: func(parameters map[string]string) (string, Condition) {
+			sql := parameters["sql"]
+
+			return "the query to return a result", func(ctx context.Context) (bool, error) {
+				value, err := querier.FirstValue(ctx, sql)
+				if err != nil {
+					return false, err
+				}
+
+				return isTruthy(value), nil
+			}
+		},
+	}
+}
+
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case *big.Rat:
+		// the NUMERIC and BIGNUMERIC values of BigQuery
+		return v != nil && v.Sign() != 0
+	case string:
+		return v != ""
+	default:
+		return true
+	}
+}
+
+// NewS3KeySensor waits for the object given with `bucket_name` and `bucket_key`, or only with `bucket_key` when it is a
+// full s3:// URL.
+func NewS3KeySensor(store ObjectStore) *Executor {
+	return &Executor{
+		TaskType: TypeS3Key,
+		condition: func(parameters map[string]string) (string, Condition) {
+			bucket, key := parameters["bucket_name"], parameters["bucket_key"]
+			if strings.HasPrefix(key, "s3://") {
+				bucket, key, _ = strings.Cut(strings.TrimPrefix(key, "s3://"), "/")
+			}
+
+			return fmt.Sprintf("the object 's3://%s/%s'", bucket, key), func(ctx context.Context) (bool, error) {
+				return store.ObjectExists(ctx, bucket, key)
+			}
+		},
+	}
+}
+
+// NewGCSPrefixSensor waits for at least one object in `bucket` whose name starts with `prefix`.
+func NewGCSPrefixSensor(store ObjectStore) *Executor {
+	return &Executor{
+		TaskType: TypeGCSPrefix,
+		condition: func(parameters map[string]string) (string, Condition) {
+			bucket, prefix := parameters["bucket"], parameters["prefix"]
+
+			return fmt.Sprintf("an object with the prefix 'gs://%s/%s'", bucket, prefix), func(ctx context.Context) (bool, error) {
+				return store.PrefixExists(ctx, bucket, prefix)
+			}
+		},
+	}
+}
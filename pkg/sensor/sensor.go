@@ -0,0 +1,150 @@
+package sensor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/datablast-analytics/blast-cli/pkg/tasktype"
+	"github.com/pkg/errors"
+)
+
+const (
+	TypeBigqueryTable = "bq.sensor.table"
+	TypeBigqueryQuery = "bq.sensor.query"
+	TypeS3Key         = "s3.sensor.key_sensor"
+	TypeGCSPrefix     = "gcs.sensor.object_sensor_with_prefix"
+)
+
+// Validate returns the problems with the parameters of a sensor task that depend on each other, the parameters on
+// their own are checked against the parameters of the task type. It returns nothing for the other task types.
+func Validate(taskType string, parameters map[string]string) []string {
+	switch taskType {
+	case TypeS3Key:
+		return validateS3Key(parameters)
+	case TypeGCSPrefix:
+		if strings.HasPrefix(parameters["bucket"], "gs://") {
+			return []string{"the parameter 'bucket' must be the name of the bucket without the gs:// prefix"}
+		}
+	}
+
+	return nil
+}
+
+// validateS3Key follows the Airflow sensor, which takes the bucket either from `bucket_name` or from `bucket_key` when
+// it is a full s3:// URL.
+func validateS3Key(parameters map[string]string) []string {
+	key, ok := parameters["bucket_key"]
+	if !ok || key == "" {
+		return nil
+	}
+
+	_, hasBucket := parameters["bucket_name"]
+	isURL := strings.HasPrefix(key, "s3://")
+	if isURL && hasBucket {
+		return []string{"the parameter 'bucket_name' must not be set when 'bucket_key' is a full s3:// URL"}
+	}
+
+	if !isURL && !hasBucket {
+		return []string{"the parameter 'bucket_name' is required unless 'bucket_key' is a full s3:// URL"}
+	}
+
+	return nil
+}
+
+// Condition checks once if the sensor is satisfied.
+type Condition func(ctx context.Context) (bool, error)
+
+// Executor runs a sensor task: it checks the condition every poke interval until it is satisfied, or fails once the
+// timeout of the sensor has passed. The condition errors are not retried here, they fail the attempt and the retries of
+// the execution policy apply.
+type Executor struct {
+	TaskType string
+
+	// condition builds the check from the parameters of the task, which are already validated, along with a
+	// description of what the sensor waits for.
+	condition func(parameters map[string]string) (string, Condition)
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Execute runs the sensor with the parameters of the task resolved against the parameters of its type.
+func (e *Executor) Execute(ctx context.Context, p *pipeline.Pipeline, t *pipeline.Task, output io.Writer) error {
+	taskType := tasktype.Builtin().Get(e.TaskType)
+	parameters := taskType.Resolve(t.Parameters, p.DefaultParameters)
+	problems := append(taskType.Validate(t.Parameters, p.DefaultParameters), Validate(e.TaskType, parameters)...)
+	if len(problems) > 0 {
+		return errors.Errorf("the task '%s' has invalid parameters: %s", t.Name, strings.Join(problems, ", "))
+	}
+
+	// the durations are already validated
+	interval, _ := time.ParseDuration(parameters["poke_interval"])
+	var timeout time.Duration
+	if value, ok := parameters["timeout"]; ok {
+		timeout, _ = time.ParseDuration(value)
+	}
+
+	description, condition := e.condition(parameters)
+	started := e.nowFunc()()
+	for {
+		satisfied, err := condition(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check %s", description)
+		}
+
+		if satisfied {
+			_, _ = fmt.Fprintf(output, "found %s\n", description)
+			return nil
+		}
+
+		wait := interval
+		if timeout > 0 {
+			left := timeout - e.nowFunc()().Sub(started)
+			if left <= 0 {
+				return errors.Errorf("gave up waiting for %s after %s", description, timeout)
+			}
+
+			// the condition is checked one last time once the timeout is reached instead of after the whole interval
+			if left < wait {
+				wait = left
+			}
+		}
+
+		_, _ = fmt.Fprintf(output, "waiting for %s, checking again in %s\n", description, wait)
+		if err := e.sleepFunc()(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *Executor) nowFunc() func() time.Time {
+	if e.now != nil {
+		return e.now
+	}
+
+	return time.Now
+}
+
+func (e *Executor) sleepFunc() func(ctx context.Context, d time.Duration) error {
+	if e.sleep != nil {
+		return e.sleep
+	}
+
+	return sleepWithContext
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,285 @@
+package sensor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/datablast-analytics/blast-cli/pkg/pipeline"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		taskType   string
+		parameters map[string]string
+		want       []string
+	}{
+		{
+			name:       "other task types are not checked",
+			taskType:   "bq.sql",
+			parameters: map[string]string{},
+			want:       nil,
+		},
+		{
+			name:       "s3 key with a bucket",
+			taskType:   TypeS3Key,
+			parameters: map[string]string{"bucket_name": "exports", "bucket_key": "orders/_SUCCESS"},
+			want:       nil,
+		},
+		{
+			name:       "s3 key as a URL",
+			taskType:   TypeS3Key,
+			parameters: map[string]string{"bucket_key": "s3://exports/orders/_SUCCESS"},
+			want:       nil,
+		},
+		{
+			name:       "s3 key without a bucket",
+			taskType:   TypeS3Key,
+			parameters: map[string]string{"bucket_key": "orders/_SUCCESS"},
+			want:       []string{"the parameter 'bucket_name' is required unless 'bucket_key' is a full s3:// URL"},
+		},
+		{
+			name:       "s3 key as a URL with a bucket",
+			taskType:   TypeS3Key,
+			parameters: map[string]string{"bucket_name": "exports", "bucket_key": "s3://exports/orders/_SUCCESS"},
+			want:       []string{"the parameter 'bucket_name' must not be set when 'bucket_key' is a full s3:// URL"},
+		},
+		{
+			name:       "gcs bucket as a URL",
+			taskType:   TypeGCSPrefix,
+			parameters: map[string]string{"bucket": "gs://landing", "prefix": "orders/"},
+			want:       []string{"the parameter 'bucket' must be the name of the bucket without the gs:// prefix"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, Validate(tt.taskType, tt.parameters))
+		})
+	}
+}
+
+type fakeTables struct {
+	existsAfter int
+	checks      int
+	err         error
+}
+
+func (f *fakeTables) TableExists(ctx context.Context, projectID, datasetID, tableID string) (bool, error) {
+	f.checks++
+	if f.err != nil {
+		return false, f.err
+	}
+
+	return f.checks > f.existsAfter, nil
+}
+
+type fakeQuerier struct {
+	value interface{}
+}
+
+func (f *fakeQuerier) FirstValue(ctx context.Context, sql string) (interface{}, error) {
+	return f.value, nil
+}
+
+// withFakeClock makes the sensor sleep instantly while moving the clock forward.
+func withFakeClock(e *Executor) (*Executor, *[]time.Duration) {
+	now := time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC)
+	sleeps := make([]time.Duration, 0)
+	e.now = func() time.Time {
+		return now
+	}
+	e.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		now = now.Add(d)
+		return ctx.Err()
+	}
+
+	return e, &sleeps
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	t.Parallel()
+
+	tableParameters := map[string]string{"project_id": "my-project", "dataset_id": "raw", "table_id": "orders"}
+
+	tests := []struct {
+		name       string
+		executor   *Executor
+		parameters map[string]string
+		wantOutput string
+		wantSleeps []time.Duration
+		wantErr    string
+	}{
+		{
+			name:       "satisfied right away",
+			executor:   NewTableSensor(&fakeTables{}),
+			parameters: tableParameters,
+			wantOutput: "found the table 'my-project.raw.orders'\n",
+			wantSleeps: []time.Duration{},
+		},
+		{
+			name:       "satisfied after poking a few times",
+			executor:   NewTableSensor(&fakeTables{existsAfter: 2}),
+			parameters: map[string]string{"project_id": "my-project", "dataset_id": "raw", "table_id": "orders", "poke_interval": "30s"},
+			wantOutput: "waiting for the table 'my-project.raw.orders', checking again in 30s\n" +
+				"waiting for the table 'my-project.raw.orders', checking again in 30s\n" +
+				"found the table 'my-project.raw.orders'\n",
+			wantSleeps: []time.Duration{30 * time.Second, 30 * time.Second},
+		},
+		{
+			name:       "gives up after the timeout",
+			executor:   NewTableSensor(&fakeTables{existsAfter: 100}),
+			parameters: map[string]string{"project_id": "my-project", "dataset_id": "raw", "table_id": "orders", "timeout": "3m"},
+			wantOutput: "waiting for the table 'my-project.raw.orders', checking again in 1m0s\n" +
+				"waiting for the table 'my-project.raw.orders', checking again in 1m0s\n" +
+				"waiting for the table 'my-project.raw.orders', checking again in 1m0s\n",
+			wantSleeps: []time.Duration{time.Minute, time.Minute, time.Minute},
+			wantErr:    "gave up waiting for the table 'my-project.raw.orders' after 3m0s",
+		},
+		{
+			name:       "does not sleep past the timeout",
+			executor:   NewTableSensor(&fakeTables{existsAfter: 100}),
+			parameters: map[string]string{"project_id": "my-project", "dataset_id": "raw", "table_id": "orders", "poke_interval": "1h", "timeout": "10m"},
+			wantOutput: "waiting for the table 'my-project.raw.orders', checking again in 10m0s\n",
+			wantSleeps: []time.Duration{10 * time.Minute},
+			wantErr:    "gave up waiting for the table 'my-project.raw.orders' after 10m0s",
+		},
+		{
+			name:       "check errors fail the attempt",
+			executor:   NewTableSensor(&fakeTables{err: errors.New("access denied")}),
+			parameters: tableParameters,
+			wantSleeps: []time.Duration{},
+			wantErr:    "failed to check the table 'my-project.raw.orders': access denied",
+		},
+		{
+			name:       "invalid parameters",
+			executor:   NewTableSensor(&fakeTables{}),
+			parameters: map[string]string{"project_id": "my-project"},
+			wantSleeps: []time.Duration{},
+			wantErr:    "the task 'sensor' has invalid parameters: the parameter 'dataset_id' is required, the parameter 'table_id' is required",
+		},
+		{
+			name:       "query with a truthy result",
+			executor:   NewQuerySensor(&fakeQuerier{value: int64(3)}),
+			parameters: map[string]string{"sql": "SELECT COUNT(*) FROM raw.orders", "timeout": "1m"},
+			wantOutput: "found the query to return a result\n",
+			wantSleeps: []time.Duration{},
+		},
+		{
+			name:       "query with a zero numeric result",
+			executor:   NewQuerySensor(&fakeQuerier{value: new(big.Rat)}),
+			parameters: map[string]string{"sql": "SELECT SUM(amount) FROM raw.orders", "timeout": "1m"},
+			wantOutput: "waiting for the query to return a result, checking again in 1m0s\n",
+			wantSleeps: []time.Duration{time.Minute},
+			wantErr:    "gave up waiting for the query to return a result after 1m0s",
+		},
+		{
+			name:       "query with a non-zero numeric result",
+			executor:   NewQuerySensor(&fakeQuerier{value: big.NewRat(1, 2)}),
+			parameters: map[string]string{"sql": "SELECT SUM(amount) FROM raw.orders", "timeout": "1m"},
+			wantOutput: "found the query to return a result\n",
+			wantSleeps: []time.Duration{},
+		},
+		{
+			name:       "query without rows",
+			executor:   NewQuerySensor(&fakeQuerier{}),
+			parameters: map[string]string{"sql": "SELECT 1 FROM raw.orders LIMIT 0", "timeout": "1m"},
+			wantOutput: "waiting for the query to return a result, checking again in 1m0s\n",
+			wantSleeps: []time.Duration{time.Minute},
+			wantErr:    "gave up waiting for the query to return a result after 1m0s",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			executor, sleeps := withFakeClock(tt.executor)
+			task := &pipeline.Task{Name: "sensor", Type: executor.TaskType, Parameters: tt.parameters}
+
+			var output bytes.Buffer
+			err := executor.Execute(context.Background(), &pipeline.Pipeline{}, task, &output)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantOutput, output.String())
+			assert.Equal(t, tt.wantSleeps, *sleeps)
+		})
+	}
+}
+
+func TestExecutor_ExecuteObjectSensors(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/storage/exports/orders/_SUCCESS", []byte{}, 0o644))
+	require.NoError(t, afero.WriteFile(fs, "/storage/landing/orders/2023-03-15.csv", []byte("id\n1\n"), 0o644))
+	store := &FileStore{Fs: fs, Root: "/storage"}
+
+	tests := []struct {
+		name       string
+		executor   *Executor
+		parameters map[string]string
+		wantOutput string
+	}{
+		{
+			name:       "s3 key with a bucket",
+			executor:   NewS3KeySensor(store),
+			parameters: map[string]string{"bucket_name": "exports", "bucket_key": "orders/_SUCCESS"},
+			wantOutput: "found the object 's3://exports/orders/_SUCCESS'\n",
+		},
+		{
+			name:       "s3 key as a URL",
+			executor:   NewS3KeySensor(store),
+			parameters: map[string]string{"bucket_key": "s3://exports/orders/_SUCCESS"},
+			wantOutput: "found the object 's3://exports/orders/_SUCCESS'\n",
+		},
+		{
+			name:       "gcs prefix",
+			executor:   NewGCSPrefixSensor(store),
+			parameters: map[string]string{"bucket": "landing", "prefix": "orders/2023-"},
+			wantOutput: "found an object with the prefix 'gs://landing/orders/2023-'\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			task := &pipeline.Task{Name: "sensor", Type: tt.executor.TaskType, Parameters: tt.parameters}
+
+			var output bytes.Buffer
+			err := tt.executor.Execute(context.Background(), &pipeline.Pipeline{}, task, &output)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOutput, output.String())
+		})
+	}
+}
+
+func TestExecutor_ExecuteStopsWhenCancelled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	task := &pipeline.Task{Name: "sensor", Parameters: map[string]string{"bucket": "landing", "prefix": "orders/"}}
+	store := &FileStore{Fs: afero.NewMemMapFs(), Root: "/storage"}
+
+	err := NewGCSPrefixSensor(store).Execute(ctx, &pipeline.Pipeline{}, task, &bytes.Buffer{})
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,218 @@
+package sensor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+)
+
+const defaultS3Region = "us-east-1"
+
+// ObjectStore is the storage the S3 and GCS sensors look into, which allows running them against a local directory or
+// a fake server instead of the real services.
+type ObjectStore interface {
+	ObjectExists(ctx context.Context, bucket, key string) (bool, error)
+	PrefixExists(ctx context.Context, bucket, prefix string) (bool, error)
+}
+
+// FileStore keeps the buckets as directories under Root, e.g. the object `orders/_SUCCESS` in the bucket `exports` is
+// the file `<Root>/exports/orders/_SUCCESS`. A missing bucket is treated as an empty one.
+type FileStore struct {
+	Fs   afero.Fs
+	Root string
+}
+
+func (f *FileStore) ObjectExists(ctx context.Context, bucket, key string) (bool, error) {
+	filePath, err := f.objectPath(bucket, key)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := f.Fs.Stat(filePath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return !info.IsDir(), nil
+}
+
+func (f *FileStore) PrefixExists(ctx context.Context, bucket, prefix string) (bool, error) {
+	bucketPath, err := f.objectPath(bucket, "")
+	if err != nil {
+		return false, err
+	}
+
+	errFound := errors.New("found")
+	err = afero.Walk(f.Fs, bucketPath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		key, err := filepath.Rel(bucketPath, filePath)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasPrefix(filepath.ToSlash(key), prefix) {
+			return errFound
+		}
+
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, errFound):
+		return true, nil
+	case os.IsNotExist(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// objectPath returns the path of the object on the filesystem, the keys cannot point outside of their bucket.
+func (f *FileStore) objectPath(bucket, key string) (string, error) {
+	if bucket == "" || strings.ContainsAny(bucket, `/\`) || bucket == "." || bucket == ".." {
+		return "", errors.Errorf("invalid bucket name '%s'", bucket)
+	}
+
+	bucketPath := filepath.Join(f.Root, bucket)
+	objectPath := filepath.Join(bucketPath, filepath.FromSlash(key))
+	if objectPath != bucketPath && !strings.HasPrefix(objectPath, bucketPath+string(filepath.Separator)) {
+		return "", errors.Errorf("invalid object key '%s'", key)
+	}
+
+	return objectPath, nil
+}
+
+type S3Config struct {
+	Region          string `envconfig:"AWS_REGION"`
+	AccessKeyID     string `envconfig:"AWS_ACCESS_KEY_ID"`
+	SecretAccessKey string `envconfig:"AWS_SECRET_ACCESS_KEY"`
+	SessionToken    string `envconfig:"AWS_SESSION_TOKEN"`
+
+	// Endpoint replaces the S3 endpoint, e.g. to use MinIO or a fake server, the buckets are then addressed with paths.
+	Endpoint string `ignored:"true"`
+}
+
+func LoadS3ConfigFromEnv() (*S3Config, error) {
+	var cfg S3Config
+	err := envconfig.Process("", &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// S3Store checks the objects in S3, the requests are anonymous when there are no credentials.
+type S3Store struct {
+	client *s3.Client
+}
+
+func NewS3Store(c *S3Config) *S3Store {
+	options := s3.Options{
+		Region:      c.Region,
+		Credentials: aws.AnonymousCredentials{},
+	}
+
+	if options.Region == "" {
+		options.Region = defaultS3Region
+	}
+
+	if c.AccessKeyID != "" {
+		options.Credentials = aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider(c.AccessKeyID, c.SecretAccessKey, c.SessionToken))
+	}
+
+	if c.Endpoint != "" {
+		options.EndpointResolver = s3.EndpointResolverFromURL(c.Endpoint)
+		options.UsePathStyle = true
+	}
+
+	return &S3Store{client: s3.New(options)}
+}
+
+func (s *S3Store) ObjectExists(ctx context.Context, bucket, key string) (bool, error) {
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err == nil {
+		return true, nil
+	}
+
+	var responseError interface{ HTTPStatusCode() int }
+	if errors.As(err, &responseError) && responseError.HTTPStatusCode() == 404 {
+		return false, nil
+	}
+
+	return false, err
+}
+
+func (s *S3Store) PrefixExists(ctx context.Context, bucket, prefix string) (bool, error) {
+	output, err := s.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: aws.String(bucket), Prefix: aws.String(prefix), MaxKeys: 1})
+	if err != nil {
+		return false, err
+	}
+
+	return len(output.Contents) > 0, nil
+}
+
+// GCSStore checks the objects in Google Cloud Storage with the application default credentials.
+type GCSStore struct {
+	service *storage.Service
+}
+
+// NewGCSStore creates the store, a non-empty endpoint replaces the GCS API endpoint, e.g. to use a fake server, and
+// disables the authentication.
+func NewGCSStore(ctx context.Context, endpoint string) (*GCSStore, error) {
+	options := []option.ClientOption{option.WithScopes(storage.DevstorageReadOnlyScope)}
+	if endpoint != "" {
+		options = append(options, option.WithEndpoint(endpoint), option.WithoutAuthentication())
+	}
+
+	service, err := storage.NewService(ctx, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the GCS client")
+	}
+
+	return &GCSStore{service: service}, nil
+}
+
+func (g *GCSStore) ObjectExists(ctx context.Context, bucket, key string) (bool, error) {
+	_, err := g.service.Objects.Get(bucket, key).Fields("name").Context(ctx).Do()
+	if err == nil {
+		return true, nil
+	}
+
+	var googleError *googleapi.Error
+	if errors.As(err, &googleError) && googleError.Code == 404 {
+		return false, nil
+	}
+
+	return false, err
+}
+
+func (g *GCSStore) PrefixExists(ctx context.Context, bucket, prefix string) (bool, error) {
+	objects, err := g.service.Objects.List(bucket).Prefix(prefix).MaxResults(1).Fields("items/name").Context(ctx).Do()
+	if err != nil {
+		return false, err
+	}
+
+	return len(objects.Items) > 0, nil
+}
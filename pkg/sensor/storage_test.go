@@ -0,0 +1,171 @@
+package sensor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storeCase is shared by all the stores, which are expected to behave the same way against the same objects.
+type storeCase struct {
+	name    string
+	check   func(ctx context.Context, store ObjectStore) (bool, error)
+	want    bool
+	wantErr string
+}
+
+var storeCases = []storeCase{
+	{
+		name: "existing object",
+		check: func(ctx context.Context, store ObjectStore) (bool, error) {
+			return store.ObjectExists(ctx, "exports", "orders/_SUCCESS")
+		},
+		want: true,
+	},
+	{
+		name: "missing object",
+		check: func(ctx context.Context, store ObjectStore) (bool, error) {
+			return store.ObjectExists(ctx, "exports", "orders/_FAILURE")
+		},
+		want: false,
+	},
+	{
+		name: "existing prefix",
+		check: func(ctx context.Context, store ObjectStore) (bool, error) {
+			return store.PrefixExists(ctx, "exports", "orders/_SUC")
+		},
+		want: true,
+	},
+	{
+		name: "missing prefix",
+		check: func(ctx context.Context, store ObjectStore) (bool, error) {
+			return store.PrefixExists(ctx, "exports", "customers/")
+		},
+		want: false,
+	},
+}
+
+func runStoreCases(t *testing.T, store ObjectStore, cases []storeCase) {
+	t.Helper()
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.check(context.Background(), store)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/storage/exports/orders/_SUCCESS", []byte{}, 0o644))
+	require.NoError(t, fs.MkdirAll("/storage/exports/customers", 0o755))
+	require.NoError(t, afero.WriteFile(fs, "/storage/secret", []byte{}, 0o644))
+
+	cases := append([]storeCase{
+		{
+			name: "directories are not objects",
+			check: func(ctx context.Context, store ObjectStore) (bool, error) {
+				return store.ObjectExists(ctx, "exports", "orders")
+			},
+			want: false,
+		},
+		{
+			name: "missing bucket",
+			check: func(ctx context.Context, store ObjectStore) (bool, error) {
+				return store.PrefixExists(ctx, "landing", "")
+			},
+			want: false,
+		},
+		{
+			name: "keys outside of the bucket",
+			check: func(ctx context.Context, store ObjectStore) (bool, error) {
+				return store.ObjectExists(ctx, "exports", "../secret")
+			},
+			wantErr: "invalid object key '../secret'",
+		},
+		{
+			name: "invalid bucket",
+			check: func(ctx context.Context, store ObjectStore) (bool, error) {
+				return store.PrefixExists(ctx, "..", "")
+			},
+			wantErr: "invalid bucket name '..'",
+		},
+	}, storeCases...)
+
+	runStoreCases(t, &FileStore{Fs: fs, Root: "/storage"}, cases)
+}
+
+func TestGCSStore(t *testing.T) {
+	t.Parallel()
+
+	// the server mimics the JSON API of GCS for the bucket `exports` with a single object
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.URL.Path == "/storage/v1/b/exports/o/orders/_SUCCESS":
+			_ = json.NewEncoder(w).Encode(map[string]string{"name": "orders/_SUCCESS"})
+		case r.URL.Path == "/storage/v1/b/exports/o":
+			items := make([]map[string]string, 0)
+			if strings.HasPrefix("orders/_SUCCESS", r.URL.Query().Get("prefix")) {
+				items = append(items, map[string]string{"name": "orders/_SUCCESS"})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error": {"code": 404, "message": "No such object"}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	store, err := NewGCSStore(context.Background(), server.URL+"/storage/v1/")
+	require.NoError(t, err)
+
+	runStoreCases(t, store, storeCases)
+}
+
+func TestS3Store(t *testing.T) {
+	t.Parallel()
+
+	// the server mimics the S3 API with path-style addressing for the bucket `exports` with a single object
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodHead && r.URL.Path == "/exports/orders/_SUCCESS":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodGet && strings.TrimSuffix(r.URL.Path, "/") == "/exports":
+			contents := ""
+			if strings.HasPrefix("orders/_SUCCESS", r.URL.Query().Get("prefix")) {
+				contents = "<Contents><Key>orders/_SUCCESS</Key></Contents>"
+			}
+
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><ListBucketResult><Name>exports</Name>` + contents + `</ListBucketResult>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	store := NewS3Store(&S3Config{AccessKeyID: "key", SecretAccessKey: "secret", Endpoint: server.URL})
+
+	runStoreCases(t, store, storeCases)
+}
@@ -1,6 +1,8 @@
 package snowflake
 
 import (
+	"fmt"
+
 	"github.com/kelseyhightower/envconfig"
 	"github.com/snowflakedb/gosnowflake"
 )
@@ -33,6 +35,12 @@ func (c Config) IsValid() bool {
 	return c.Account != "" && c.Username != "" && c.Password != "" && c.Region != ""
 }
 
+// Identity returns a string that identifies the environment the queries are validated against, the password is
+// intentionally left out.
+func (c Config) Identity() string {
+	return fmt.Sprintf("snowflake:%s:%s:%s:%s:%s:%s", c.Account, c.Region, c.Username, c.Role, c.Database, c.Schema)
+}
+
 func LoadConfigFromEnv() (*Config, error) {
 	var cfg Config
 	err := envconfig.Process("", &cfg)
@@ -0,0 +1,55 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+const (
+	errSessionExpired       = 390112
+	errMasterTokenExpired   = 390114
+	sqlStateConnectionClass = "08"
+)
+
+// transientErrorCodes are the driver and server error numbers that are caused by the connection or the session rather
+// than the query itself.
+var transientErrorCodes = map[int]bool{
+	gosnowflake.ErrCodeServiceUnavailable: true,
+	gosnowflake.ErrFailedToPostQuery:      true,
+	gosnowflake.ErrFailedToRenewSession:   true,
+	gosnowflake.ErrFailedToHeartbeat:      true,
+	gosnowflake.ErrFailedToGetChunk:       true,
+	gosnowflake.ErrSessionGone:            true,
+	errSessionExpired:                     true,
+	errMasterTokenExpired:                 true,
+}
+
+// IsTransientError reports whether the error is caused by a temporary problem such as an expired session or a network
+// failure, meaning that the same query may succeed if it is retried.
+func IsTransientError(err error) bool {
+	// the context errors satisfy net.Error as well, but retrying them would not help
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var sfError *gosnowflake.SnowflakeError
+	if errors.As(err, &sfError) {
+		if transientErrorCodes[sfError.Number] {
+			return true
+		}
+
+		return len(sfError.SQLState) >= 2 && sfError.SQLState[:2] == sqlStateConnectionClass
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netError net.Error
+	return errors.As(err, &netError)
+}
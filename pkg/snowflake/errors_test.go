@@ -0,0 +1,77 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/snowflakedb/gosnowflake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransientError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil is not transient",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "expired sessions are transient",
+			err:  &gosnowflake.SnowflakeError{Number: gosnowflake.ErrSessionGone},
+			want: true,
+		},
+		{
+			name: "unavailable service is transient",
+			err:  pkgerrors.Wrap(&gosnowflake.SnowflakeError{Number: gosnowflake.ErrCodeServiceUnavailable}, "failed"),
+			want: true,
+		},
+		{
+			name: "connection exceptions are transient",
+			err:  &gosnowflake.SnowflakeError{Number: 123456, SQLState: "08001"},
+			want: true,
+		},
+		{
+			name: "compilation errors are not transient",
+			err:  &gosnowflake.SnowflakeError{Number: 1003, SQLState: "42000", Message: "SQL compilation error"},
+			want: false,
+		},
+		{
+			name: "bad connections are transient",
+			err:  driver.ErrBadConn,
+			want: true,
+		},
+		{
+			name: "network errors are transient",
+			err:  &net.OpError{Op: "read", Err: errors.New("connection reset by peer")},
+			want: true,
+		},
+		{
+			name: "context errors are not transient",
+			err:  context.Canceled,
+			want: false,
+		},
+		{
+			name: "other errors are not transient",
+			err:  errors.New("Object does not exist"),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, IsTransientError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package sqlparser
+
+import "strings"
+
+// Node is implemented by all the nodes of the syntax tree, see Inspect to walk them.
+type Node interface {
+	node()
+}
+
+type Statement interface {
+	Node
+	statement()
+}
+
+// QueryBody is either a Select, a SetOperation or a parenthesized Query.
+type QueryBody interface {
+	Node
+	queryBody()
+}
+
+// TableExpr is either a TableName, a DerivedTable, a TableFunction or a Join.
+type TableExpr interface {
+	Node
+	tableExpr()
+}
+
+// Expr is a part of an expression. The expressions are not parsed into operator trees, the operators and the keywords
+// between the operands are kept in a Compound in the order they are written.
+type Expr interface {
+	Node
+	expr()
+}
+
+// QueryStatement is a statement that only reads, e.g. SELECT or WITH.
+type QueryStatement struct {
+	Query *Query
+	Line  int
+}
+
+// CreateTable is a CREATE TABLE or a CREATE VIEW statement, Query is nil when the table is not created from a query.
+type CreateTable struct {
+	Name      *TableName
+	View      bool
+	Temporary bool
+	Query     *Query
+	Line      int
+}
+
+// Insert is an INSERT statement, either Query or Values is set depending on how the rows are given.
+type Insert struct {
+	Table  *TableName
+	Query  *Query
+	Values []Expr
+	Line   int
+}
+
+type Delete struct {
+	Table *TableName
+	Using []TableExpr
+	Where Expr
+	Line  int
+}
+
+type Truncate struct {
+	Table *TableName
+	Line  int
+}
+
+// OtherStatement is any other statement, such as MERGE or DECLARE, its structure is not known beyond the expressions.
+type OtherStatement struct {
+	Keyword string
+	Exprs   []Expr
+	Line    int
+}
+
+type Query struct {
+	With    []*CommonTableExpression
+	Body    QueryBody
+	OrderBy []Expr
+	Limit   Expr
+	Line    int
+}
+
+type CommonTableExpression struct {
+	Name  string
+	Query *Query
+}
+
+type Select struct {
+	Distinct bool
+	Columns  []*SelectItem
+	From     []TableExpr
+	Where    Expr
+	GroupBy  []Expr
+	Having   Expr
+	Qualify  Expr
+	Line     int
+}
+
+type SelectItem struct {
+	Expr  Expr
+	Alias string
+}
+
+// SetOperation is a UNION, an INTERSECT or an EXCEPT, with the ALL or DISTINCT modifiers in Operator.
+type SetOperation struct {
+	Operator string
+	Left     QueryBody
+	Right    QueryBody
+}
+
+// TableName is a reference to a table or a view, a name written as `project.dataset.table` in BigQuery has three
+// parts just like the unquoted one.
+type TableName struct {
+	Parts []string
+	Alias string
+	Line  int
+}
+
+// String returns the name as it would be written without the quotes.
+func (t *TableName) String() string {
+	return strings.Join(t.Parts, ".")
+}
+
+type DerivedTable struct {
+	Query *Query
+	Alias string
+}
+
+// TableFunction is a function in the FROM clause, such as UNNEST or FLATTEN.
+type TableFunction struct {
+	Call  *FunctionCall
+	Alias string
+}
+
+// Join joins two tables, Kind is the join type such as LEFT OUTER or CROSS and it is empty for a plain JOIN.
+type Join struct {
+	Kind      string
+	Left      TableExpr
+	Right     TableExpr
+	Condition Expr
+}
+
+type LiteralKind int
+
+const (
+	StringLiteral LiteralKind = iota
+	NumberLiteral
+	BoolLiteral
+	NullLiteral
+)
+
+// Literal is a constant value, Type is set for the typed literals such as DATE '2022-01-01'. The Value of a string
+// literal does not include the quotes.
+type Literal struct {
+	Kind  LiteralKind
+	Type  string
+	Value string
+	Line  int
+}
+
+type Identifier struct {
+	Parts []string
+	Line  int
+}
+
+// Star is a * in the select list, possibly qualified with a table, e.g. t.*, and followed by modifiers such as EXCEPT
+// or EXCLUDE.
+type Star struct {
+	Qualifier []string
+	Modifiers []Expr
+	Line      int
+}
+
+type FunctionCall struct {
+	Name []string
+	Args []Expr
+	Line int
+}
+
+type Subquery struct {
+	Query *Query
+}
+
+// Group is a parenthesized list of expressions.
+type Group struct {
+	Items []Expr
+}
+
+// TemplateExpr is a {{ }} expression, which is replaced with its value only when the query runs.
+type TemplateExpr struct {
+	Value string
+	Line  int
+}
+
+// Param is a query parameter or a variable, e.g. @name, $1 or :name.
+type Param struct {
+	Name string
+}
+
+// Keyword is a keyword within an expression, e.g. AND, IS or CASE.
+type Keyword struct {
+	Value string
+}
+
+type Operator struct {
+	Value string
+}
+
+// Compound is an expression made of multiple terms, e.g. a = 1 AND b IS NULL.
+type Compound struct {
+	Terms []Expr
+}
+
+func (*QueryStatement) node()        {}
+func (*CreateTable) node()           {}
+func (*Insert) node()                {}
+func (*Delete) node()                {}
+func (*Truncate) node()              {}
+func (*OtherStatement) node()        {}
+func (*Query) node()                 {}
+func (*CommonTableExpression) node() {}
+func (*Select) node()                {}
+func (*SelectItem) node()            {}
+func (*SetOperation) node()          {}
+func (*TableName) node()             {}
+func (*DerivedTable) node()          {}
+func (*TableFunction) node()         {}
+func (*Join) node()                  {}
+func (*Literal) node()               {}
+func (*Identifier) node()            {}
+func (*Star) node()                  {}
+func (*FunctionCall) node()          {}
+func (*Subquery) node()              {}
+func (*Group) node()                 {}
+func (*TemplateExpr) node()          {}
+func (*Param) node()                 {}
+func (*Keyword) node()               {}
+func (*Operator) node()              {}
+func (*Compound) node()              {}
+
+func (*QueryStatement) statement() {}
+func (*CreateTable) statement()    {}
+func (*Insert) statement()         {}
+func (*Delete) statement()         {}
+func (*Truncate) statement()       {}
+func (*OtherStatement) statement() {}
+
+func (*Query) queryBody()        {}
+func (*Select) queryBody()       {}
+func (*SetOperation) queryBody() {}
+
+func (*TableName) tableExpr()     {}
+func (*DerivedTable) tableExpr()  {}
+func (*TableFunction) tableExpr() {}
+func (*Join) tableExpr()          {}
+
+func (*Literal) expr()      {}
+func (*Identifier) expr()   {}
+func (*Star) expr()         {}
+func (*FunctionCall) expr() {}
+func (*Subquery) expr()     {}
+func (*Group) expr()        {}
+func (*TemplateExpr) expr() {}
+func (*Param) expr()        {}
+func (*Keyword) expr()      {}
+func (*Operator) expr()     {}
+func (*Compound) expr()     {}
@@ -0,0 +1,587 @@
+package sqlparser
+
+import (
+	"strings"
+)
+
+const indentUnit = "    "
+
+// formatKeywords are uppercased by the formatter, the other words keep their case since changing it might change the
+// column names, e.g. the aliases in BigQuery.
+var formatKeywords = extendKeywords(reservedKeywords,
+	"CREATE", "DECLARE", "DELETE", "INSERT", "MATCHED", "MERGE", "REPLACE", "TABLE", "TEMP", "TEMPORARY", "TRUNCATE",
+	"UPDATE", "VIEW",
+)
+
+// operandKeywords end an operand, so the sign after them is an operator rather than the sign of a number.
+var operandKeywords = keywordSet("END", "FALSE", "NULL", "TRUE")
+
+// selectModifiers can follow SELECT on the same line, e.g. SELECT DISTINCT or SELECT AS STRUCT in BigQuery.
+var selectModifiers = keywordSet("ALL", "AS", "DISTINCT", "STRUCT", "TOP", "VALUE")
+
+// typeKeywords start the parameterized types of BigQuery, e.g. ARRAY<STRING>, whose brackets are not spaced.
+var typeKeywords = keywordSet("ARRAY", "RANGE", "STRUCT")
+
+// conditionClauses are the clauses whose AND and OR start a new line.
+var conditionClauses = keywordSet("HAVING", "JOIN", "QUALIFY", "WHERE")
+
+// Format rewrites the SQL in a single style: the keywords are uppercased, every clause starts on its own line, the
+// selected columns are listed one per line with the commas at the end of the lines, and the subqueries are indented.
+// The comments at the top of the file, e.g. the task annotations, are kept as they are, along with the strings, the
+// quoted identifiers and the template expressions.
+func Format(sql string, dialect Dialect) (string, error) {
+	tokens, err := Tokenize(sql, dialect)
+	if err != nil {
+		return "", err
+	}
+
+	header, body := splitHeader(tokens)
+	formatted := newFormatter(body).format()
+
+	switch {
+	case header == "":
+		return formatted + "\n", nil
+	case formatted == "":
+		return header + "\n", nil
+	case strings.Count(leadingSpace(body), "\n") > 1:
+		return header + "\n\n" + formatted + "\n", nil
+	default:
+		return header + "\n" + formatted + "\n", nil
+	}
+}
+
+// splitHeader separates the comments at the beginning of the file from the rest, the header is returned as it is
+// apart from the whitespace at its end.
+func splitHeader(tokens []Token) (string, []Token) {
+	end := 0
+	for index, token := range tokens {
+		if token.Kind == Comment {
+			end = index + 1
+		} else if token.Kind != Whitespace {
+			break
+		}
+	}
+
+	var header strings.Builder
+	for _, token := range tokens[:end] {
+		header.WriteString(token.Value)
+	}
+
+	return header.String(), tokens[end:]
+}
+
+func leadingSpace(tokens []Token) string {
+	if len(tokens) > 0 && tokens[0].Kind == Whitespace {
+		return tokens[0].Value
+	}
+
+	return ""
+}
+
+// frame holds the layout of a query or a parenthesized expression, the clauses only start new lines in the queries.
+type frame struct {
+	query bool
+
+	// indent is the level of the clauses of the query, the columns and the conditions are indented one level more.
+	indent      int
+	closeIndent int
+
+	clause    string
+	caseDepth int
+	between   bool
+
+	// afterClause is set after SELECT and the set operators, the next token starts a new line unless it is one of the
+	// modifiers that stay on the same line, e.g. DISTINCT.
+	afterClause  map[string]bool
+	afterIndent  int
+	inlineTokens int
+}
+
+type formatter struct {
+	tokens []Token
+
+	// newlines is the number of line breaks before each token in the input, and glued tells if there was no
+	// whitespace before it at all.
+	newlines     []int
+	glued        []bool
+	firstColumns []bool
+
+	out           strings.Builder
+	frames        []*frame
+	lineIndent    int
+	pending       int
+	pendingIndent int
+	last          int
+	unary         map[int]bool
+	typeDepth     int
+}
+
+func newFormatter(tokens []Token) *formatter {
+	f := &formatter{last: -1, unary: make(map[int]bool)}
+	f.reset()
+
+	whitespace := ""
+	for index, token := range tokens {
+		if token.Kind == Whitespace {
+			whitespace = token.Value
+			continue
+		}
+
+		f.tokens = append(f.tokens, token)
+		f.newlines = append(f.newlines, strings.Count(whitespace, "\n"))
+		f.glued = append(f.glued, whitespace == "" && index > 0)
+		f.firstColumns = append(f.firstColumns, strings.HasSuffix(whitespace, "\n"))
+		whitespace = ""
+	}
+
+	return f
+}
+
+func (f *formatter) reset() {
+	f.frames = []*frame{{query: true}}
+	f.typeDepth = 0
+}
+
+func (f *formatter) top() *frame {
+	return f.frames[len(f.frames)-1]
+}
+
+func (f *formatter) format() string {
+	for index, token := range f.tokens {
+		switch {
+		case token.Kind == Comment:
+			f.writeComment(index)
+		case token.IsTemplateBlock():
+			f.writeOwnLine(index, f.standaloneIndent(index), f.newlines[index] > 1)
+		default:
+			f.writeToken(index)
+		}
+	}
+
+	return f.out.String()
+}
+
+// breakLine makes the next token start on a new line, the last call decides the indentation.
+func (f *formatter) breakLine(newlines, indent int) {
+	if f.pending < newlines {
+		f.pending = newlines
+	}
+	f.pendingIndent = indent
+}
+
+func (f *formatter) write(index int, value string) {
+	spaced := f.needsSpace(index)
+
+	switch {
+	case f.out.Len() == 0:
+	case f.pending > 0:
+		f.out.WriteString(strings.Repeat("\n", f.pending))
+		f.out.WriteString(strings.Repeat(indentUnit, f.pendingIndent))
+		f.lineIndent = f.pendingIndent
+	case spaced:
+		f.out.WriteString(" ")
+	}
+
+	if f.out.Len() == 0 {
+		f.lineIndent = 0
+	}
+
+	f.pending = 0
+	f.out.WriteString(value)
+	f.last = index
+}
+
+func (f *formatter) writeComment(index int) {
+	if f.out.Len() > 0 && f.newlines[index] == 0 {
+		// the comments at the end of a line stay there, the line comments end the line
+		pending, pendingIndent := f.pending, f.pendingIndent
+		f.out.WriteString(" " + f.tokens[index].Value)
+		f.last = index
+		f.pending, f.pendingIndent = pending, pendingIndent
+
+		if !strings.HasPrefix(f.tokens[index].Value, "/*") || (index+1 < len(f.tokens) && f.newlines[index+1] > 0) {
+			if f.pending == 0 {
+				f.pendingIndent = f.lineIndent
+			}
+			f.breakLine(1, f.pendingIndent)
+		}
+
+		return
+	}
+
+	// the comments at the beginning of a line in the input stay there, which keeps the annotations such as
+	// `-- @blast.name` valid
+	indent := f.standaloneIndent(index)
+	if f.firstColumns[index] {
+		indent = 0
+	}
+
+	f.writeOwnLine(index, indent, f.newlines[index] > 1)
+}
+
+// writeOwnLine writes the token on a line by itself, which is used for the comments and the template blocks.
+func (f *formatter) writeOwnLine(index, indent int, blankLine bool) {
+	nextIndent := f.lineIndent
+	if f.pending > 0 {
+		nextIndent = f.pendingIndent
+	}
+
+	newlines := 1
+	if blankLine {
+		newlines = 2
+	}
+
+	f.breakLine(newlines, indent)
+	f.write(index, f.tokens[index].Value)
+	f.breakLine(1, nextIndent)
+}
+
+// standaloneIndent is the indentation of the comments and the template blocks on their own line, which is the one of
+// the token after them.
+func (f *formatter) standaloneIndent(index int) int {
+	next := f.nextIndex(index)
+	if next != -1 && f.startsClause(next) {
+		return f.top().indent
+	}
+
+	if f.pending > 0 {
+		return f.pendingIndent
+	}
+
+	return f.lineIndent
+}
+
+func (f *formatter) writeToken(index int) {
+	token := f.tokens[index]
+	top := f.top()
+	keyword := ""
+	if token.Kind == Word {
+		keyword = strings.ToUpper(token.Value)
+	}
+
+	if top.afterClause != nil {
+		switch {
+		case top.inlineTokens > 0:
+			top.inlineTokens--
+		case f.isClauseModifier(index, keyword):
+			if keyword == "TOP" {
+				top.inlineTokens = 1
+			}
+		default:
+			f.breakLine(1, top.afterIndent)
+			top.afterClause = nil
+		}
+	}
+
+	switch {
+	case token.IsPunctuation(";"):
+		f.write(index, ";")
+		f.reset()
+		f.breakLine(2, 0)
+		return
+	case token.IsPunctuation("("):
+		f.write(index, "(")
+		if f.startsQuery(index + 1) {
+			f.frames = append(f.frames, &frame{query: true, indent: f.lineIndent + 1, closeIndent: f.lineIndent})
+			f.breakLine(1, f.lineIndent+1)
+			return
+		}
+
+		f.frames = append(f.frames, &frame{indent: top.indent})
+		return
+	case token.IsPunctuation(")"):
+		if len(f.frames) > 1 {
+			f.frames = f.frames[:len(f.frames)-1]
+			if top.query {
+				f.breakLine(1, top.closeIndent)
+			}
+		}
+
+		f.write(index, ")")
+		return
+	case token.IsPunctuation(","):
+		f.write(index, ",")
+		if f.atClauseLevel() {
+			switch top.clause {
+			case "SELECT":
+				f.breakLine(1, top.indent+1)
+			case "WITH":
+				f.breakLine(1, top.indent)
+			}
+		}
+		return
+	}
+
+	if f.startsClause(index) {
+		f.breakLine(1, top.indent)
+		f.startClause(index, keyword)
+	} else if (keyword == "AND" || keyword == "OR") && f.atClauseLevel() && conditionClauses[top.clause] && !top.between {
+		f.breakLine(1, top.indent+1)
+	}
+
+	switch keyword {
+	case "CASE":
+		top.caseDepth++
+	case "END":
+		if top.caseDepth > 0 {
+			top.caseDepth--
+		}
+	case "BETWEEN":
+		top.between = true
+	case "AND":
+		top.between = false
+	}
+
+	f.write(index, f.casing(index))
+}
+
+// isClauseModifier reports whether the word stays on the line of the clause before it. The words that are not
+// reserved are modifiers only in their place, e.g. STRUCT in SELECT AS STRUCT, so that the columns and the functions
+// with the same names, e.g. SELECT value or SELECT struct(1 AS a), start the list of the columns.
+func (f *formatter) isClauseModifier(index int, keyword string) bool {
+	if !f.top().afterClause[keyword] {
+		return false
+	}
+
+	if reservedKeywords[keyword] {
+		return true
+	}
+
+	if next := index + 1; next < len(f.tokens) && (f.tokens[next].IsPunctuation(",") || f.tokens[next].IsPunctuation("(")) {
+		return false
+	}
+
+	switch keyword {
+	case "STRUCT", "VALUE":
+		return f.last >= 0 && f.tokens[f.last].Is("AS")
+	case "TOP":
+		return index+1 < len(f.tokens) && f.tokens[index+1].Kind == Number
+	}
+
+	return true
+}
+
+func (f *formatter) startClause(index int, keyword string) {
+	top := f.top()
+	switch {
+	case keyword == "SELECT":
+		top.afterClause = selectModifiers
+		top.afterIndent = top.indent + 1
+	case setOperatorKeywords[keyword]:
+		top.afterClause = keywordSet("ALL", "DISTINCT")
+		top.afterIndent = top.indent
+	case joinKeywords[keyword]:
+		keyword = "JOIN"
+	}
+
+	top.clause = keyword
+	top.between = false
+}
+
+func (f *formatter) atClauseLevel() bool {
+	top := f.top()
+	return top.query && top.caseDepth == 0
+}
+
+// startsClause reports whether the token starts a clause of the query, which is written on a new line.
+func (f *formatter) startsClause(index int) bool {
+	token := f.tokens[index]
+	if token.Kind != Word || !f.atClauseLevel() {
+		return false
+	}
+
+	keyword := strings.ToUpper(token.Value)
+	previous, next := f.token(f.previousIndex(index)), f.token(f.nextIndex(index))
+	if previous.IsPunctuation(".") || next.IsPunctuation(".") || next.IsPunctuation("(") && functionKeywords[keyword] {
+		return false
+	}
+
+	switch keyword {
+	case "SELECT", "WHERE", "HAVING", "QUALIFY", "WINDOW", "LIMIT", "VALUES":
+		return true
+	case "FROM":
+		return !previous.Is("DELETE") && !previous.Is("DISTINCT")
+	case "GROUP", "ORDER", "PARTITION", "CLUSTER":
+		return next.Is("BY")
+	case "WITH":
+		return !next.Is("OFFSET") && !next.Is("ORDINALITY")
+	case "EXCEPT":
+		return previous.Value != "*"
+	}
+
+	if setOperatorKeywords[keyword] {
+		return true
+	}
+
+	// the first keyword of a join starts the line, e.g. LEFT OUTER JOIN
+	return joinKeywords[keyword] && !previous.Is("OUTER") && !(previous.Kind == Word && joinKeywords[strings.ToUpper(previous.Value)])
+}
+
+// startsQuery reports whether the tokens from the given index start a query, which is how a subquery is told apart
+// from the other parentheses.
+func (f *formatter) startsQuery(index int) bool {
+	for index < len(f.tokens) && f.skipped(index) {
+		index++
+	}
+
+	next := f.token(index)
+	return next.Is("SELECT") || next.Is("WITH")
+}
+
+// casing uppercases the keywords, unless they are a part of a name, an alias or a function call, e.g. t.date,
+// AS value or LEFT(name, 1).
+func (f *formatter) casing(index int) string {
+	token := f.tokens[index]
+	if token.Kind != Word {
+		return token.Value
+	}
+
+	keyword := strings.ToUpper(token.Value)
+	previous, next := f.token(f.previousIndex(index)), f.token(f.nextIndex(index))
+	switch {
+	case previous.IsPunctuation(".") || next.IsPunctuation(".") || previous.Kind == Symbol && strings.HasSuffix(previous.Value, ":"):
+		return token.Value
+	case previous.Is("AS") && !reservedKeywords[keyword]:
+		return token.Value
+	case functionKeywords[keyword] && next.IsPunctuation("("):
+		return token.Value
+	case formatKeywords[keyword], typedLiterals[keyword] && next.Kind == String:
+		return keyword
+	}
+
+	return token.Value
+}
+
+// needsSpace decides the spacing between the last written token and the given one. It is called for every token,
+// since it also tracks the signs and the type brackets.
+func (f *formatter) needsSpace(index int) bool {
+	current := f.tokens[index]
+	if f.last == -1 {
+		if current.Value == "-" || current.Value == "+" {
+			f.unary[index] = true
+		}
+		return false
+	}
+
+	previous := f.tokens[f.last]
+	if current.Kind == Symbol {
+		if spaced, ok := f.typeBracket(index); ok {
+			return spaced
+		}
+
+		if (current.Value == "-" || current.Value == "+") && f.startsOperand(f.last) {
+			f.unary[index] = true
+			return !previous.IsPunctuation("(") && previous.Value != "["
+		}
+
+		// the dashes in the names are kept, e.g. my-project.dataset.table in BigQuery
+		if current.Value == "-" && f.glued[index] && nameLike(previous) && index+1 < len(f.tokens) && f.glued[index+1] && nameLike(f.tokens[index+1]) {
+			f.unary[index] = true
+			return false
+		}
+	}
+
+	switch {
+	case previous.Kind == Comment:
+		return true
+	case current.Kind == Punctuation && current.Value != "(":
+		return false
+	case previous.IsPunctuation("(") || previous.IsPunctuation("."):
+		return false
+	case current.Value == "::" || previous.Value == "::" || current.Value == ":" || previous.Value == ":":
+		return false
+	case f.unary[f.last]:
+		return false
+	case current.Value == "[":
+		return !(nameLike(previous) || previous.IsPunctuation(")") || previous.Value == "]")
+	case previous.Value == "[" || current.Value == "]":
+		return false
+	case f.typeDepth > 0 && previous.Value == "<":
+		return false
+	case current.IsPunctuation("("):
+		keyword := strings.ToUpper(previous.Value)
+		if previous.Kind == Word && formatKeywords[keyword] && !functionKeywords[keyword] {
+			return true
+		}
+		return !(nameLike(previous) && f.glued[index])
+	}
+
+	return !(f.glued[index] && nameLike(previous) && nameLike(current))
+}
+
+// typeBracket tracks the angle brackets of the parameterized types, e.g. ARRAY<STRUCT<id INT64>>, which are not
+// spaced.
+func (f *formatter) typeBracket(index int) (bool, bool) {
+	previous, current := f.tokens[f.last], f.tokens[index]
+	switch {
+	case current.Value == "<" && previous.Kind == Word && (typeKeywords[strings.ToUpper(previous.Value)] || f.typeDepth > 0):
+		f.typeDepth++
+		return false, true
+	case current.Value == ">" && f.typeDepth > 0:
+		f.typeDepth--
+		return false, true
+	case current.Value == ">>" && f.typeDepth > 1:
+		f.typeDepth -= 2
+		return false, true
+	}
+
+	return false, false
+}
+
+// startsOperand reports whether an operand is expected after the given token, which makes the sign after it unary.
+func (f *formatter) startsOperand(index int) bool {
+	token := f.tokens[index]
+	switch token.Kind {
+	case Symbol:
+		return token.Value != "]"
+	case Punctuation:
+		return token.Value == "(" || token.Value == ","
+	case Word:
+		keyword := strings.ToUpper(token.Value)
+		return formatKeywords[keyword] && !operandKeywords[keyword]
+	}
+
+	return false
+}
+
+func nameLike(token Token) bool {
+	switch token.Kind {
+	case Word, QuotedIdentifier, Number, Parameter, Template:
+		return true
+	}
+
+	return false
+}
+
+// skipped reports whether the token is left out when looking at the neighbours of a token, i.e. the comments and the
+// template blocks.
+func (f *formatter) skipped(index int) bool {
+	return f.tokens[index].Kind == Comment || f.tokens[index].IsTemplateBlock()
+}
+
+func (f *formatter) previousIndex(index int) int {
+	for index--; index >= 0 && f.skipped(index); index-- {
+	}
+
+	return index
+}
+
+func (f *formatter) nextIndex(index int) int {
+	for index++; index < len(f.tokens) && f.skipped(index); index++ {
+	}
+
+	if index == len(f.tokens) {
+		return -1
+	}
+
+	return index
+}
+
+// token returns the token at the index, or an empty token when the index is out of the range.
+func (f *formatter) token(index int) Token {
+	if index < 0 || index >= len(f.tokens) {
+		return Token{Kind: Whitespace}
+	}
+
+	return f.tokens[index]
+}
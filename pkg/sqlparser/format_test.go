@@ -0,0 +1,136 @@
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sql     string
+		dialect Dialect
+		want    string
+	}{
+		{
+			name: "annotations, clauses and subqueries",
+			sql: "-- @blast.name: orders\n-- @blast.type:   bq.sql\n\n" +
+				"with recent as (select * from `p.d.events` where dt = '{{ ds }}')\n" +
+				"select distinct r.id, count(*) as total, case when a then -1 else b end as flag -- note\n" +
+				"from recent r left outer join my-project.d.customers as c on c.id = r.id and c.x between 1 and 2\n" +
+				"where r.id in (select id from p.d.t) and r.dt >= {{ ds }}\n" +
+				"group by 1, 2 order by total desc limit 10;\n",
+			dialect: BigQuery,
+			want: "-- @blast.name: orders\n-- @blast.type:   bq.sql\n\n" +
+				"WITH recent AS (\n" +
+				"    SELECT\n" +
+				"        *\n" +
+				"    FROM `p.d.events`\n" +
+				"    WHERE dt = '{{ ds }}'\n" +
+				")\n" +
+				"SELECT DISTINCT\n" +
+				"    r.id,\n" +
+				"    count(*) AS total,\n" +
+				"    CASE WHEN a THEN -1 ELSE b END AS flag -- note\n" +
+				"FROM recent r\n" +
+				"LEFT OUTER JOIN my-project.d.customers AS c ON c.id = r.id\n" +
+				"    AND c.x BETWEEN 1 AND 2\n" +
+				"WHERE r.id IN (\n" +
+				"    SELECT\n" +
+				"        id\n" +
+				"    FROM p.d.t\n" +
+				")\n" +
+				"    AND r.dt >= {{ ds }}\n" +
+				"GROUP BY 1, 2\n" +
+				"ORDER BY total DESC\n" +
+				"LIMIT 10;\n",
+		},
+		{
+			name:    "multiple statements and types",
+			sql:     "create or replace table p.d.x partition by dt as select cast(x as array<struct<a int64>>) as x from unnest([1, 2]) with offset as pos union all select * except (id) from p.d.y;delete from p.d.t where true",
+			dialect: BigQuery,
+			want: "CREATE OR REPLACE TABLE p.d.x\n" +
+				"PARTITION BY dt AS\n" +
+				"SELECT\n" +
+				"    cast(x AS array<struct<a int64>>) AS x\n" +
+				"FROM unnest([1, 2]) WITH OFFSET AS pos\n" +
+				"UNION ALL\n" +
+				"SELECT\n" +
+				"    * EXCEPT (id)\n" +
+				"FROM p.d.y;\n" +
+				"\n" +
+				"DELETE FROM p.d.t\n" +
+				"WHERE TRUE\n",
+		},
+		{
+			name: "snowflake paths, templates and comments",
+			sql: "SELECT f.value:id::string AS id, a - 1, row_number() over (partition by a order by b) as rn\n" +
+				"FROM \"Raw\".{{ schema }}.events e, LATERAL FLATTEN(input => e.items) f\n" +
+				"{% if x %}\nWHERE e.dt = {{ ds }}\n{% endif %}\n" +
+				"  -- the latest rows\n" +
+				"qualify rn = 1\n" +
+				"-- @blast.depends: events\n",
+			dialect: Snowflake,
+			want: "SELECT\n" +
+				"    f.value:id::string AS id,\n" +
+				"    a - 1,\n" +
+				"    row_number() OVER (PARTITION BY a ORDER BY b) AS rn\n" +
+				"FROM \"Raw\".{{ schema }}.events e, LATERAL FLATTEN(input => e.items) f\n" +
+				"{% if x %}\n" +
+				"WHERE e.dt = {{ ds }}\n" +
+				"{% endif %}\n" +
+				"-- the latest rows\n" +
+				"QUALIFY rn = 1\n" +
+				"-- @blast.depends: events\n",
+		},
+		{
+			name:    "comments between the columns",
+			sql:     "/* @blast\nname: orders\n*/\nSELECT a,\n    -- the b\n  b FROM t WHERE x IS DISTINCT FROM y",
+			dialect: BigQuery,
+			want:    "/* @blast\nname: orders\n*/\nSELECT\n    a,\n    -- the b\n    b\nFROM t\nWHERE x IS DISTINCT FROM y\n",
+		},
+		{
+			name:    "only the annotations",
+			sql:     "-- @blast.name: orders\n\n",
+			dialect: BigQuery,
+			want:    "-- @blast.name: orders\n",
+		},
+		{
+			name:    "select modifiers",
+			sql:     "select as value struct(1 as a);select as struct 1 a, 2 b;select top 10 x from t",
+			dialect: BigQuery,
+			want:    "SELECT AS value\n    struct(1 AS a);\n\nSELECT AS struct\n    1 a,\n    2 b;\n\nSELECT top 10\n    x\nFROM t\n",
+		},
+		{
+			name:    "columns named like the select modifiers",
+			sql:     "select value, x from t;select struct(1 as a) s;select top, x from t",
+			dialect: BigQuery,
+			want:    "SELECT\n    value,\n    x\nFROM t;\n\nSELECT\n    struct(1 AS a) s;\n\nSELECT\n    top,\n    x\nFROM t\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Format(tt.sql, tt.dialect)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			again, err := Format(got, tt.dialect)
+			require.NoError(t, err)
+			assert.Equal(t, got, again, "formatting the output again should not change it")
+		})
+	}
+}
+
+func TestFormat_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Format("SELECT 'abc", BigQuery)
+	require.EqualError(t, err, "line 1: the string is not closed")
+}
@@ -0,0 +1,278 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Dialect decides how the quotes are read: BigQuery uses backticks for the identifiers and both quotes for the
+// strings, while Snowflake uses double quotes for the identifiers.
+type Dialect int
+
+const (
+	BigQuery Dialect = iota
+	Snowflake
+)
+
+type TokenKind int
+
+const (
+	Whitespace TokenKind = iota
+	Comment
+	Word
+	QuotedIdentifier
+	String
+	Number
+	Parameter
+	Template
+	Punctuation
+	Symbol
+)
+
+type Token struct {
+	Kind  TokenKind
+	Value string
+
+	// Offset is the position of the token in the input in bytes, Line starts from 1.
+	Offset int
+	Line   int
+}
+
+// Is reports whether the token is the given keyword, regardless of its case.
+func (t Token) Is(keyword string) bool {
+	return t.Kind == Word && strings.EqualFold(t.Value, keyword)
+}
+
+// IsPunctuation reports whether the token is the given punctuation, i.e. one of ( ) , ; .
+func (t Token) IsPunctuation(value string) bool {
+	return t.Kind == Punctuation && t.Value == value
+}
+
+// IsTemplateBlock reports whether the token is a template statement or comment, such as {% if %} or {# note #}, which
+// are not part of the query itself, unlike the {{ }} expressions.
+func (t Token) IsTemplateBlock() bool {
+	return t.Kind == Template && !strings.HasPrefix(t.Value, "{{")
+}
+
+var operators = []string{"::", "||", "<=", ">=", "<>", "!=", "=>", "->", "<<", ">>"}
+
+// Tokenize splits the given SQL into tokens, including the whitespace and the comments, so that the tokens can be
+// joined back to the same SQL.
+func Tokenize(sql string, dialect Dialect) ([]Token, error) {
+	l := &lexer{input: sql, dialect: dialect, line: 1}
+	for l.pos < len(l.input) {
+		if err := l.next(); err != nil {
+			return nil, errors.Wrapf(err, "line %d", l.line)
+		}
+	}
+
+	return l.tokens, nil
+}
+
+type lexer struct {
+	input   string
+	dialect Dialect
+	pos     int
+	line    int
+	tokens  []Token
+}
+
+func (l *lexer) next() error {
+	rest := l.input[l.pos:]
+	c := rest[0]
+
+	switch {
+	case isSpace(c):
+		return l.emit(Whitespace, l.scanWhile(isSpace))
+	case strings.HasPrefix(rest, "--") || (c == '#' && l.dialect == BigQuery) || (strings.HasPrefix(rest, "//") && l.dialect == Snowflake):
+		end := strings.IndexByte(rest, '\n')
+		if end == -1 {
+			end = len(rest)
+		}
+		return l.emit(Comment, end)
+	case strings.HasPrefix(rest, "/*"):
+		end := strings.Index(rest[2:], "*/")
+		if end == -1 {
+			return errors.New("the block comment is not closed")
+		}
+		return l.emit(Comment, end+4)
+	case strings.HasPrefix(rest, "{{") || strings.HasPrefix(rest, "{%") || strings.HasPrefix(rest, "{#"):
+		closing := map[byte]string{'{': "}}", '%': "%}", '#': "#}"}[rest[1]]
+		end := strings.Index(rest[2:], closing)
+		if end == -1 {
+			return errors.Errorf("the template expression '%s' is not closed", rest[:2])
+		}
+		return l.emit(Template, end+4)
+	case c == '`' && l.dialect == BigQuery:
+		return l.scanQuoted(QuotedIdentifier, "`")
+	case c == '"' && l.dialect == Snowflake:
+		return l.scanQuoted(QuotedIdentifier, `"`)
+	case c == '\'' || c == '"':
+		quote := string(c)
+		if l.dialect == BigQuery && strings.HasPrefix(rest, strings.Repeat(quote, 3)) {
+			quote = strings.Repeat(quote, 3)
+		}
+		return l.scanQuoted(String, quote)
+	case strings.HasPrefix(rest, "$$") && l.dialect == Snowflake:
+		end := strings.Index(rest[2:], "$$")
+		if end == -1 {
+			return errors.New("the string is not closed")
+		}
+		return l.emit(String, end+4)
+	case isDigit(c) || (c == '.' && len(rest) > 1 && isDigit(rest[1]) && !l.followsName()):
+		return l.emit(Number, l.scanNumber())
+	case isWordStart(c):
+		return l.scanWord()
+	case c == '@' || c == '$' || (c == ':' && len(rest) > 1 && isWordStart(rest[1]) && !l.followsName()):
+		return l.scanParameter()
+	case strings.ContainsRune("(),;.", rune(c)):
+		return l.emit(Punctuation, 1)
+	}
+
+	for _, operator := range operators {
+		if strings.HasPrefix(rest, operator) {
+			return l.emit(Symbol, len(operator))
+		}
+	}
+
+	return l.emit(Symbol, 1)
+}
+
+func (l *lexer) emit(kind TokenKind, length int) error {
+	value := l.input[l.pos : l.pos+length]
+	l.tokens = append(l.tokens, Token{Kind: kind, Value: value, Offset: l.pos, Line: l.line})
+	l.pos += length
+	l.line += strings.Count(value, "\n")
+
+	return nil
+}
+
+func (l *lexer) scanWhile(accept func(c byte) bool) int {
+	length := 0
+	for l.pos+length < len(l.input) && accept(l.input[l.pos+length]) {
+		length++
+	}
+
+	return length
+}
+
+// scanQuoted reads a string or a quoted identifier, the quotes can be escaped either with a backslash or by repeating
+// them.
+func (l *lexer) scanQuoted(kind TokenKind, quote string) error {
+	rest := l.input[l.pos:]
+	i := len(quote)
+	for i < len(rest) {
+		switch {
+		case rest[i] == '\\' && kind == String:
+			i += 2
+		case strings.HasPrefix(rest[i:], quote) && len(quote) == 1 && strings.HasPrefix(rest[i+1:], quote):
+			i += 2
+		case strings.HasPrefix(rest[i:], quote):
+			return l.emit(kind, i+len(quote))
+		default:
+			i++
+		}
+	}
+
+	if kind == String {
+		return errors.New("the string is not closed")
+	}
+
+	return errors.Errorf("the quoted identifier %s is not closed", quote)
+}
+
+func (l *lexer) scanNumber() int {
+	rest := l.input[l.pos:]
+	i := 0
+	for i < len(rest) && (isDigit(rest[i]) || rest[i] == '.') {
+		i++
+	}
+
+	if i < len(rest) && (rest[i] == 'e' || rest[i] == 'E') {
+		j := i + 1
+		if j < len(rest) && (rest[j] == '+' || rest[j] == '-') {
+			j++
+		}
+		if j < len(rest) && isDigit(rest[j]) {
+			i = j
+			for i < len(rest) && isDigit(rest[i]) {
+				i++
+			}
+		}
+	}
+
+	return i
+}
+
+func (l *lexer) scanWord() error {
+	length := l.scanWhile(isWordPart)
+
+	// the raw and the bytes strings of BigQuery, e.g. r'\d+' or b"abc"
+	prefix := strings.ToLower(l.input[l.pos : l.pos+length])
+	rest := l.input[l.pos+length:]
+	if l.dialect == BigQuery && (prefix == "r" || prefix == "b" || prefix == "rb" || prefix == "br") && rest != "" && (rest[0] == '\'' || rest[0] == '"') {
+		start := l.pos
+		l.pos += length
+		quote := string(rest[0])
+		if strings.HasPrefix(rest, strings.Repeat(quote, 3)) {
+			quote = strings.Repeat(quote, 3)
+		}
+
+		if err := l.scanQuoted(String, quote); err != nil {
+			l.pos = start
+			return err
+		}
+
+		last := &l.tokens[len(l.tokens)-1]
+		last.Value = l.input[start:l.pos]
+		last.Offset = start
+		return nil
+	}
+
+	return l.emit(Word, length)
+}
+
+func (l *lexer) scanParameter() error {
+	length := 1
+	for l.pos+length < len(l.input) && l.input[l.pos+length] == '@' {
+		length++
+	}
+
+	for l.pos+length < len(l.input) && isWordPart(l.input[l.pos+length]) {
+		length++
+	}
+
+	if length == 1 {
+		return l.emit(Symbol, 1)
+	}
+
+	return l.emit(Parameter, length)
+}
+
+// followsName reports whether the previous token is a name without any space in between, which makes the next dot or
+// colon a part of a path, e.g. t.col or v:field, instead of the start of a number or a parameter.
+func (l *lexer) followsName() bool {
+	if len(l.tokens) == 0 {
+		return false
+	}
+
+	last := l.tokens[len(l.tokens)-1]
+	return last.Kind == Word || last.Kind == QuotedIdentifier || last.IsPunctuation(")")
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isWordPart(c byte) bool {
+	return isWordStart(c) || isDigit(c) || c == '$'
+}
@@ -0,0 +1,123 @@
+package sqlparser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sql     string
+		dialect Dialect
+		want    []Token
+	}{
+		{
+			name:    "bigquery quotes and comments",
+			sql:     "SELECT \"a\", `p.d.t` # note\n/* x */ r'\\d'",
+			dialect: BigQuery,
+			want: []Token{
+				{Kind: Word, Value: "SELECT", Line: 1},
+				{Kind: String, Value: `"a"`, Line: 1},
+				{Kind: Punctuation, Value: ",", Line: 1},
+				{Kind: QuotedIdentifier, Value: "`p.d.t`", Line: 1},
+				{Kind: Comment, Value: "# note", Line: 1},
+				{Kind: Comment, Value: "/* x */", Line: 2},
+				{Kind: String, Value: `r'\d'`, Line: 2},
+			},
+		},
+		{
+			name:    "snowflake quotes, parameters and casts",
+			sql:     "SELECT \"Col\"\"x\", $$it's$$, $1, v:field::date // note",
+			dialect: Snowflake,
+			want: []Token{
+				{Kind: Word, Value: "SELECT", Line: 1},
+				{Kind: QuotedIdentifier, Value: `"Col""x"`, Line: 1},
+				{Kind: Punctuation, Value: ",", Line: 1},
+				{Kind: String, Value: "$$it's$$", Line: 1},
+				{Kind: Punctuation, Value: ",", Line: 1},
+				{Kind: Parameter, Value: "$1", Line: 1},
+				{Kind: Punctuation, Value: ",", Line: 1},
+				{Kind: Word, Value: "v", Line: 1},
+				{Kind: Symbol, Value: ":", Line: 1},
+				{Kind: Word, Value: "field", Line: 1},
+				{Kind: Symbol, Value: "::", Line: 1},
+				{Kind: Word, Value: "date", Line: 1},
+				{Kind: Comment, Value: "// note", Line: 1},
+			},
+		},
+		{
+			name:    "templates, numbers and escaped quotes",
+			sql:     "WHERE dt = '{{ ds }}' AND n >= 1.5e3 AND s = 'it''s' {% if x %}{{ var }}",
+			dialect: BigQuery,
+			want: []Token{
+				{Kind: Word, Value: "WHERE", Line: 1},
+				{Kind: Word, Value: "dt", Line: 1},
+				{Kind: Symbol, Value: "=", Line: 1},
+				{Kind: String, Value: "'{{ ds }}'", Line: 1},
+				{Kind: Word, Value: "AND", Line: 1},
+				{Kind: Word, Value: "n", Line: 1},
+				{Kind: Symbol, Value: ">=", Line: 1},
+				{Kind: Number, Value: "1.5e3", Line: 1},
+				{Kind: Word, Value: "AND", Line: 1},
+				{Kind: Word, Value: "s", Line: 1},
+				{Kind: Symbol, Value: "=", Line: 1},
+				{Kind: String, Value: "'it''s'", Line: 1},
+				{Kind: Template, Value: "{% if x %}", Line: 1},
+				{Kind: Template, Value: "{{ var }}", Line: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tokens, err := Tokenize(tt.sql, tt.dialect)
+			require.NoError(t, err)
+
+			var joined strings.Builder
+			got := make([]Token, 0, len(tokens))
+			for _, token := range tokens {
+				joined.WriteString(token.Value)
+				if token.Kind == Whitespace {
+					continue
+				}
+
+				token.Offset = 0
+				got = append(got, token)
+			}
+
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.sql, joined.String())
+		})
+	}
+}
+
+func TestTokenize_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sql     string
+		wantErr string
+	}{
+		{sql: "SELECT 'abc", wantErr: "line 1: the string is not closed"},
+		{sql: "SELECT 1\n/* note", wantErr: "line 2: the block comment is not closed"},
+		{sql: "SELECT `abc", wantErr: "line 1: the quoted identifier ` is not closed"},
+		{sql: "SELECT {{ ds", wantErr: "line 1: the template expression '{{' is not closed"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.sql, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Tokenize(tt.sql, BigQuery)
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
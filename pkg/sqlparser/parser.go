@@ -0,0 +1,1161 @@
+package sqlparser
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// clauseKeywords end the expressions of a clause, e.g. the select list ends at FROM and the WHERE clause ends at
+// GROUP.
+var clauseKeywords = keywordSet(
+	"AS", "CROSS", "EXCEPT", "FETCH", "FROM", "FULL", "GROUP", "HAVING", "INNER", "INTERSECT", "JOIN", "LEFT", "LIMIT",
+	"MINUS", "NATURAL", "OFFSET", "ON", "ORDER", "QUALIFY", "RIGHT", "UNION", "USING", "WHERE", "WINDOW",
+)
+
+// reservedKeywords cannot be used as aliases without quotes, which is how an alias is told apart from the next term.
+var reservedKeywords = extendKeywords(clauseKeywords,
+	"ALL", "AND", "ANY", "ASC", "BETWEEN", "BY", "CASE", "COLLATE", "DESC", "DISTINCT", "ELSE", "END", "ESCAPE",
+	"EXISTS", "FALSE", "FOR", "IGNORE", "ILIKE", "IN", "INTERVAL", "INTO", "IS", "LATERAL", "LIKE", "NOT", "NULL",
+	"NULLS", "OR", "OUTER", "OVER", "PARTITION", "PIVOT", "RECURSIVE", "REGEXP", "RESPECT", "RLIKE", "SELECT", "SET",
+	"SOME", "TABLESAMPLE", "THEN", "TRUE", "UNPIVOT", "VALUES", "WHEN", "WITH",
+)
+
+// functionKeywords are the reserved keywords that are functions when they are followed by a parenthesis, e.g.
+// LEFT(name, 1).
+var functionKeywords = keywordSet("COLLATE", "IF", "LEFT", "OFFSET", "REPLACE", "RIGHT")
+
+// typedLiterals are the types that can precede a string to make a typed literal, e.g. DATE '2022-01-01'.
+var typedLiterals = keywordSet("DATE", "DATETIME", "TIME", "TIMESTAMP", "TIMESTAMP_LTZ", "TIMESTAMP_NTZ", "TIMESTAMP_TZ")
+
+var (
+	joinKeywords         = keywordSet("CROSS", "FULL", "INNER", "JOIN", "LEFT", "NATURAL", "RIGHT")
+	setOperatorKeywords  = keywordSet("EXCEPT", "INTERSECT", "MINUS", "UNION")
+	starModifierKeywords = keywordSet("EXCEPT", "EXCLUDE", "ILIKE", "RENAME", "REPLACE")
+)
+
+func keywordSet(keywords ...string) map[string]bool {
+	return extendKeywords(nil, keywords...)
+}
+
+func extendKeywords(base map[string]bool, keywords ...string) map[string]bool {
+	set := make(map[string]bool, len(base)+len(keywords))
+	for keyword := range base {
+		set[keyword] = true
+	}
+
+	for _, keyword := range keywords {
+		set[keyword] = true
+	}
+
+	return set
+}
+
+// Parse parses the statements in the given SQL. The statements that cannot be parsed into their own nodes, e.g. the
+// ones using a syntax that is not supported yet, are returned as OtherStatement, which means only the unbalanced
+// parentheses and the lexical errors such as unclosed strings fail the parsing.
+func Parse(sql string, dialect Dialect) ([]Statement, error) {
+	tokens, err := Tokenize(sql, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{dialect: dialect}
+	for _, token := range tokens {
+		if token.Kind == Whitespace || token.Kind == Comment || token.IsTemplateBlock() {
+			continue
+		}
+
+		p.tokens = append(p.tokens, token)
+	}
+
+	statements := make([]Statement, 0)
+	for !p.eof() {
+		if p.peek().IsPunctuation(";") {
+			p.advance()
+			continue
+		}
+
+		statement, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+
+		statements = append(statements, statement)
+	}
+
+	return statements, nil
+}
+
+type parser struct {
+	dialect Dialect
+	tokens  []Token
+	pos     int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() Token {
+	return p.peekAt(0)
+}
+
+// peekAt returns the token at the given distance, the tokens after the end of the input are empty punctuations.
+func (p *parser) peekAt(distance int) Token {
+	if p.pos+distance >= len(p.tokens) {
+		line := 1
+		if len(p.tokens) > 0 {
+			line = p.tokens[len(p.tokens)-1].Line
+		}
+		return Token{Kind: Punctuation, Line: line}
+	}
+
+	return p.tokens[p.pos+distance]
+}
+
+func (p *parser) advance() Token {
+	token := p.peek()
+	if !p.eof() {
+		p.pos++
+	}
+
+	return token
+}
+
+// accept consumes the next token if it is the given keyword.
+func (p *parser) accept(keyword string) bool {
+	if p.peek().Is(keyword) {
+		p.advance()
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) expect(keyword string) error {
+	if !p.accept(keyword) {
+		return p.unexpected()
+	}
+
+	return nil
+}
+
+func (p *parser) expectPunctuation(value string) error {
+	if !p.peek().IsPunctuation(value) {
+		return p.unexpected()
+	}
+
+	p.advance()
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	if p.eof() {
+		return errors.New("unexpected end of the query")
+	}
+
+	token := p.peek()
+	return errors.Errorf("unexpected '%s' at line %d", token.Value, token.Line)
+}
+
+// atStatementEnd reports whether the statement is over, which is either a semicolon or the end of the input.
+func (p *parser) atStatementEnd() bool {
+	return p.eof() || p.peek().IsPunctuation(";")
+}
+
+// isKeyword reports whether the token at the given distance is a keyword from the set, unless it is a function call
+// such as LEFT(name, 1).
+func (p *parser) isKeyword(distance int, set map[string]bool) bool {
+	token := p.peekAt(distance)
+	if token.Kind != Word {
+		return false
+	}
+
+	keyword := strings.ToUpper(token.Value)
+	return set[keyword] && !(functionKeywords[keyword] && p.peekAt(distance+1).IsPunctuation("("))
+}
+
+// startsQuery reports whether the tokens at the given distance start a query, including the parenthesized ones.
+func (p *parser) startsQuery(distance int) bool {
+	for p.peekAt(distance).IsPunctuation("(") {
+		distance++
+	}
+
+	return p.peekAt(distance).Is("SELECT") || p.peekAt(distance).Is("WITH")
+}
+
+// parseStatement parses the statements it knows into their own nodes, and falls back to an OtherStatement for the
+// others and for the known ones using a syntax it does not support.
+func (p *parser) parseStatement() (Statement, error) {
+	start := p.pos
+
+	var statement Statement
+	var err error
+	switch token := p.peek(); {
+	case p.startsQuery(0):
+		statement, err = p.parseQueryStatement()
+	case token.Is("CREATE"):
+		statement, err = p.parseCreate()
+	case token.Is("INSERT"):
+		statement, err = p.parseInsert()
+	case token.Is("DELETE"):
+		statement, err = p.parseDelete()
+	case token.Is("TRUNCATE"):
+		statement, err = p.parseTruncate()
+	default:
+		return p.parseOther()
+	}
+
+	if err == nil && p.atStatementEnd() {
+		return statement, nil
+	}
+
+	p.pos = start
+	return p.parseOther()
+}
+
+func (p *parser) parseQueryStatement() (Statement, error) {
+	line := p.peek().Line
+	query, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryStatement{Query: query, Line: line}, nil
+}
+
+func (p *parser) parseCreate() (Statement, error) {
+	statement := &CreateTable{Line: p.advance().Line}
+	if p.accept("OR") {
+		if err := p.expect("REPLACE"); err != nil {
+			return nil, err
+		}
+	}
+
+	for !p.peek().Is("TABLE") && !p.peek().Is("VIEW") {
+		modifier := p.advance()
+		switch {
+		case modifier.Is("TEMP") || modifier.Is("TEMPORARY") || modifier.Is("VOLATILE"):
+			statement.Temporary = true
+		case modifier.Is("LOCAL") || modifier.Is("GLOBAL") || modifier.Is("TRANSIENT") || modifier.Is("SECURE") || modifier.Is("MATERIALIZED"):
+		default:
+			return nil, errors.Errorf("unsupported CREATE statement at line %d", statement.Line)
+		}
+	}
+
+	statement.View = p.advance().Is("VIEW")
+	if p.accept("IF") {
+		if err := p.expect("NOT"); err != nil {
+			return nil, err
+		}
+		if err := p.expect("EXISTS"); err != nil {
+			return nil, err
+		}
+	}
+
+	name, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	statement.Name = name
+
+	// the column definitions and the options such as PARTITION BY are skipped until the query
+	for !p.atStatementEnd() {
+		if p.peek().Is("AS") && p.startsQuery(1) {
+			p.advance()
+			statement.Query, err = p.parseQuery()
+			return statement, err
+		}
+
+		if p.peek().IsPunctuation(",") {
+			p.advance()
+			continue
+		}
+
+		if _, err := p.parseTerm(); err != nil {
+			return nil, err
+		}
+	}
+
+	return statement, nil
+}
+
+func (p *parser) parseInsert() (Statement, error) {
+	statement := &Insert{Line: p.advance().Line}
+	p.accept("OVERWRITE")
+	p.accept("INTO")
+
+	table, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	statement.Table = table
+
+	if p.peek().IsPunctuation("(") && !p.startsQuery(0) {
+		if _, err := p.parseTerm(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.accept("VALUES") {
+		for !p.atStatementEnd() {
+			row, err := p.parseExpr(false)
+			if err != nil {
+				return nil, err
+			}
+
+			if row == nil {
+				return nil, p.unexpected()
+			}
+			statement.Values = append(statement.Values, row)
+
+			if p.peek().IsPunctuation(",") {
+				p.advance()
+			}
+		}
+
+		return statement, nil
+	}
+
+	statement.Query, err = p.parseQuery()
+	return statement, err
+}
+
+func (p *parser) parseDelete() (Statement, error) {
+	statement := &Delete{Line: p.advance().Line}
+	p.accept("FROM")
+
+	table, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	statement.Table = table
+	table.Alias, err = p.parseAlias()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.accept("USING") {
+		statement.Using, err = p.parseFrom()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.accept("WHERE") {
+		statement.Where, err = p.parseExpr(false)
+	}
+
+	return statement, err
+}
+
+func (p *parser) parseTruncate() (Statement, error) {
+	statement := &Truncate{Line: p.advance().Line}
+	p.accept("TABLE")
+	if p.accept("IF") {
+		if err := p.expect("EXISTS"); err != nil {
+			return nil, err
+		}
+	}
+
+	table, err := p.parseTableName()
+	if err != nil {
+		return nil, err
+	}
+	statement.Table = table
+
+	return statement, nil
+}
+
+// parseOther reads the rest of the statement as a list of expressions, which keeps the literals and the subqueries
+// in the tree even though the statement itself is not understood.
+func (p *parser) parseOther() (Statement, error) {
+	statement := &OtherStatement{Keyword: strings.ToUpper(p.peek().Value), Line: p.peek().Line}
+	for !p.atStatementEnd() {
+		if p.peek().IsPunctuation(",") {
+			p.advance()
+			continue
+		}
+
+		expr, err := p.parseExprUntil(nil, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if expr == nil {
+			return nil, p.unexpected()
+		}
+
+		statement.Exprs = append(statement.Exprs, expr)
+	}
+
+	return statement, nil
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	query := &Query{Line: p.peek().Line}
+	if p.accept("WITH") {
+		p.accept("RECURSIVE")
+		for {
+			cte, err := p.parseCommonTableExpression()
+			if err != nil {
+				return nil, err
+			}
+			query.With = append(query.With, cte)
+
+			if !p.peek().IsPunctuation(",") {
+				break
+			}
+			p.advance()
+		}
+	}
+
+	body, err := p.parseQueryBody()
+	if err != nil {
+		return nil, err
+	}
+	query.Body = body
+
+	if p.peek().Is("ORDER") && p.peekAt(1).Is("BY") {
+		p.pos += 2
+		query.OrderBy, err = p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case p.accept("LIMIT"):
+		query.Limit, err = p.parseExpr(false)
+	case p.peek().Is("FETCH"):
+		// FETCH FIRST 10 ROWS ONLY is the standard form of LIMIT 10
+		p.advance()
+		query.Limit, err = p.parseExpr(false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.accept("OFFSET") {
+		if _, err := p.parseExpr(false); err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
+}
+
+func (p *parser) parseCommonTableExpression() (*CommonTableExpression, error) {
+	name, err := p.parseIdentifier()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().IsPunctuation("(") {
+		if _, err := p.parseTerm(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.expect("AS"); err != nil {
+		return nil, err
+	}
+
+	if err := p.expectPunctuation("("); err != nil {
+		return nil, err
+	}
+
+	query, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expectPunctuation(")"); err != nil {
+		return nil, err
+	}
+
+	return &CommonTableExpression{Name: name, Query: query}, nil
+}
+
+func (p *parser) parseQueryBody() (QueryBody, error) {
+	left, err := p.parseQueryPrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword(0, setOperatorKeywords) {
+		operator := strings.ToUpper(p.advance().Value)
+		if p.peek().Is("ALL") || p.peek().Is("DISTINCT") {
+			operator += " " + strings.ToUpper(p.advance().Value)
+		}
+
+		right, err := p.parseQueryPrimary()
+		if err != nil {
+			return nil, err
+		}
+
+		left = &SetOperation{Operator: operator, Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseQueryPrimary() (QueryBody, error) {
+	if p.peek().IsPunctuation("(") {
+		p.advance()
+		query, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+
+		return query, p.expectPunctuation(")")
+	}
+
+	return p.parseSelect()
+}
+
+func (p *parser) parseSelect() (*Select, error) {
+	if !p.peek().Is("SELECT") {
+		return nil, p.unexpected()
+	}
+
+	selectNode := &Select{Line: p.advance().Line}
+
+	// SELECT AS STRUCT and SELECT AS VALUE of BigQuery
+	if p.accept("AS") {
+		p.advance()
+	}
+
+	selectNode.Distinct = p.accept("DISTINCT")
+	p.accept("ALL")
+	if p.peek().Is("TOP") && p.peekAt(1).Kind == Number {
+		p.advance()
+		p.advance()
+	}
+
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		selectNode.Columns = append(selectNode.Columns, item)
+
+		if !p.peek().IsPunctuation(",") {
+			break
+		}
+		p.advance()
+
+		// BigQuery allows a trailing comma at the end of the select list
+		if p.isKeyword(0, clauseKeywords) || p.atStatementEnd() || p.peek().IsPunctuation(")") {
+			break
+		}
+	}
+
+	return selectNode, p.parseSelectClauses(selectNode)
+}
+
+func (p *parser) parseSelectClauses(selectNode *Select) error {
+	var err error
+	for err == nil {
+		switch {
+		case p.accept("FROM"):
+			selectNode.From, err = p.parseFrom()
+		case p.accept("WHERE"):
+			selectNode.Where, err = p.parseExpr(false)
+		case p.peek().Is("GROUP") && p.peekAt(1).Is("BY"):
+			p.pos += 2
+			selectNode.GroupBy, err = p.parseExprList()
+		case p.accept("HAVING"):
+			selectNode.Having, err = p.parseExpr(false)
+		case p.accept("QUALIFY"):
+			selectNode.Qualify, err = p.parseExpr(false)
+		case p.accept("WINDOW"):
+			_, err = p.parseExprList()
+		default:
+			return nil
+		}
+	}
+
+	return err
+}
+
+func (p *parser) parseSelectItem() (*SelectItem, error) {
+	var expr Expr
+	var err error
+	if p.peek().Kind == Symbol && p.peek().Value == "*" {
+		expr = &Star{Line: p.advance().Line}
+	} else {
+		expr, err = p.parseExpr(true)
+		if err != nil {
+			return nil, err
+		}
+
+		if expr == nil {
+			return nil, p.unexpected()
+		}
+	}
+
+	if star, ok := expr.(*Star); ok {
+		return &SelectItem{Expr: star}, p.parseStarModifiers(star)
+	}
+
+	alias, err := p.parseAlias()
+	return &SelectItem{Expr: expr, Alias: alias}, err
+}
+
+// parseStarModifiers reads the modifiers of BigQuery and Snowflake that change the columns of a *, e.g.
+// * EXCEPT (id) or * EXCLUDE id.
+func (p *parser) parseStarModifiers(star *Star) error {
+	for p.peek().Kind == Word && starModifierKeywords[strings.ToUpper(p.peek().Value)] {
+		star.Modifiers = append(star.Modifiers, &Keyword{Value: strings.ToUpper(p.advance().Value)})
+
+		term, err := p.parseTerm()
+		if err != nil {
+			return err
+		}
+		star.Modifiers = append(star.Modifiers, term)
+	}
+
+	return nil
+}
+
+// parseAlias reads an optional alias, the AS keyword can be omitted as long as the alias is not a reserved keyword.
+func (p *parser) parseAlias() (string, error) {
+	if p.accept("AS") {
+		token := p.peek()
+		if token.Kind != Word && token.Kind != QuotedIdentifier && token.Kind != String {
+			return "", p.unexpected()
+		}
+
+		return p.parseIdentifier()
+	}
+
+	if p.startsAlias() {
+		return p.parseIdentifier()
+	}
+
+	return "", nil
+}
+
+func (p *parser) startsAlias() bool {
+	token := p.peek()
+	switch token.Kind {
+	case QuotedIdentifier:
+		return true
+	case Word:
+		return !reservedKeywords[strings.ToUpper(token.Value)] && !p.peekAt(1).IsPunctuation("(") && !p.peekAt(1).IsPunctuation(".")
+	}
+
+	return false
+}
+
+func (p *parser) parseIdentifier() (string, error) {
+	token := p.advance()
+	switch token.Kind {
+	case Word:
+		return token.Value, nil
+	case QuotedIdentifier, String:
+		return unquote(token.Value), nil
+	}
+
+	p.pos--
+	return "", p.unexpected()
+}
+
+func (p *parser) parseFrom() ([]TableExpr, error) {
+	var tables []TableExpr
+	for {
+		table, err := p.parseTableExpr()
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+
+		if !p.peek().IsPunctuation(",") {
+			return tables, nil
+		}
+		p.advance()
+	}
+}
+
+func (p *parser) parseTableExpr() (TableExpr, error) {
+	left, err := p.parseTablePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isKeyword(0, joinKeywords) {
+		var kind []string
+		for !p.peek().Is("JOIN") {
+			if !p.isKeyword(0, joinKeywords) && !p.peek().Is("OUTER") {
+				return nil, p.unexpected()
+			}
+			kind = append(kind, strings.ToUpper(p.advance().Value))
+		}
+		p.advance()
+
+		right, err := p.parseTablePrimary()
+		if err != nil {
+			return nil, err
+		}
+
+		join := &Join{Kind: strings.Join(kind, " "), Left: left, Right: right}
+		switch {
+		case p.accept("ON"):
+			join.Condition, err = p.parseExpr(false)
+		case p.accept("USING"):
+			join.Condition, err = p.parseTerm()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		left = join
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseTablePrimary() (TableExpr, error) {
+	p.accept("LATERAL")
+
+	var table TableExpr
+	switch {
+	case p.peek().IsPunctuation("(") && p.startsQuery(1):
+		p.advance()
+		query, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunctuation(")"); err != nil {
+			return nil, err
+		}
+
+		derived := &DerivedTable{Query: query}
+		derived.Alias, err = p.parseTableAlias()
+		return derived, err
+	case p.peek().IsPunctuation("("):
+		p.advance()
+		nested, err := p.parseTableExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		return nested, p.expectPunctuation(")")
+	}
+
+	line := p.peek().Line
+	parts, err := p.parseName(true)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().IsPunctuation("(") {
+		args, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+
+		function := &TableFunction{Call: &FunctionCall{Name: parts, Args: args, Line: line}}
+		function.Alias, err = p.parseTableAlias()
+		table = function
+	} else {
+		name := &TableName{Parts: parts, Line: line}
+		if err := p.skipTableModifiers(); err != nil {
+			return nil, err
+		}
+
+		name.Alias, err = p.parseTableAlias()
+		table = name
+	}
+
+	return table, err
+}
+
+// skipTableModifiers skips the time travel and the sampling clauses, e.g. FOR SYSTEM_TIME AS OF or AT(...).
+func (p *parser) skipTableModifiers() error {
+	for {
+		switch {
+		case p.peek().Is("FOR") && p.peekAt(1).Is("SYSTEM_TIME"):
+			p.pos += 2
+			if err := p.expect("AS"); err != nil {
+				return err
+			}
+			if err := p.expect("OF"); err != nil {
+				return err
+			}
+			if _, err := p.parseExpr(false); err != nil {
+				return err
+			}
+		case (p.peek().Is("AT") || p.peek().Is("BEFORE") || p.peek().Is("CHANGES")) && p.peekAt(1).IsPunctuation("("):
+			p.advance()
+			if _, err := p.parseTerm(); err != nil {
+				return err
+			}
+		case p.peek().Is("TABLESAMPLE") || p.peek().Is("SAMPLE"):
+			p.advance()
+			for !p.peek().IsPunctuation("(") && !p.atStatementEnd() {
+				p.advance()
+			}
+			if _, err := p.parseTerm(); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// parseTableAlias reads the alias of a table along with its column names and the clauses that follow the aliases, such
+// as WITH OFFSET of BigQuery or PIVOT.
+func (p *parser) parseTableAlias() (string, error) {
+	alias, err := p.parseAlias()
+	if err != nil {
+		return "", err
+	}
+
+	if alias != "" && p.peek().IsPunctuation("(") {
+		if _, err := p.parseTerm(); err != nil {
+			return "", err
+		}
+	}
+
+	if p.peek().Is("WITH") && p.peekAt(1).Is("OFFSET") {
+		p.pos += 2
+		if _, err := p.parseAlias(); err != nil {
+			return "", err
+		}
+	}
+
+	if p.peek().Is("PIVOT") || p.peek().Is("UNPIVOT") {
+		p.advance()
+		if _, err := p.parseTerm(); err != nil {
+			return "", err
+		}
+
+		if _, err := p.parseAlias(); err != nil {
+			return "", err
+		}
+	}
+
+	return alias, nil
+}
+
+func (p *parser) parseTableName() (*TableName, error) {
+	line := p.peek().Line
+	parts, err := p.parseName(true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TableName{Parts: parts, Line: line}, nil
+}
+
+// parseName reads a dotted name. The quoted parts of BigQuery can contain the dots themselves, e.g.
+// `project.dataset.table`, and the parts written next to a template expression are joined with it, e.g.
+// {{ env }}_sales. The dashes are allowed in the table names, which is how the BigQuery projects are often named.
+func (p *parser) parseName(dashes bool) ([]string, error) {
+	var parts []string
+	for {
+		token := p.advance()
+		switch {
+		case token.Kind == Word:
+			parts = append(parts, p.joinAdjacent(token.Value, token, dashes))
+		case token.Kind == Template:
+			parts = append(parts, p.joinAdjacent(token.Value, token, dashes))
+		case token.Kind == QuotedIdentifier && p.dialect == BigQuery:
+			parts = append(parts, strings.Split(unquote(token.Value), ".")...)
+		case token.Kind == QuotedIdentifier:
+			parts = append(parts, unquote(token.Value))
+		default:
+			p.pos--
+			return nil, p.unexpected()
+		}
+
+		if !p.peek().IsPunctuation(".") || (p.peekAt(1).Kind == Symbol && p.peekAt(1).Value == "*") {
+			return parts, nil
+		}
+		p.advance()
+	}
+}
+
+func (p *parser) joinAdjacent(part string, previous Token, dashes bool) string {
+	for {
+		next := p.peek()
+		switch {
+		case !adjacent(previous, next):
+			return part
+		case next.Kind == Word || next.Kind == Number || next.Kind == Template:
+			part += next.Value
+		case dashes && next.Kind == Symbol && next.Value == "-" && adjacent(next, p.peekAt(1)) && (p.peekAt(1).Kind == Word || p.peekAt(1).Kind == Number):
+			part += next.Value
+		default:
+			return part
+		}
+
+		previous = p.advance()
+	}
+}
+
+func adjacent(previous, next Token) bool {
+	return next.Value != "" && previous.Offset+len(previous.Value) == next.Offset
+}
+
+// parseExprList reads a comma-separated list of expressions, e.g. the GROUP BY clause.
+func (p *parser) parseExprList() ([]Expr, error) {
+	var exprs []Expr
+	for {
+		expr, err := p.parseExpr(false)
+		if err != nil {
+			return nil, err
+		}
+
+		if expr == nil {
+			return nil, p.unexpected()
+		}
+		exprs = append(exprs, expr)
+
+		if !p.peek().IsPunctuation(",") {
+			return exprs, nil
+		}
+		p.advance()
+	}
+}
+
+// parseExpr reads an expression until the end of the clause, see parseExprUntil.
+func (p *parser) parseExpr(aliases bool) (Expr, error) {
+	return p.parseExprUntil(clauseKeywords, aliases)
+}
+
+// parseExprUntil reads the terms of an expression until a comma, a closing parenthesis or bracket, the end of the
+// statement or one of the given keywords. When aliases are allowed, a word following a complete term ends the
+// expression as well, since it is the alias of the expression. A single term is returned as it is, and nil is returned
+// when there are no terms at all.
+func (p *parser) parseExprUntil(stops map[string]bool, aliases bool) (Expr, error) {
+	var terms []Expr
+	for !p.atStatementEnd() && !p.peek().IsPunctuation(",") && !p.peek().IsPunctuation(")") && !p.isClosingBracket() {
+		if p.isKeyword(0, stops) {
+			break
+		}
+
+		if aliases && len(terms) > 0 && endsOperand(terms[len(terms)-1]) && p.startsAlias() {
+			break
+		}
+
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+
+		switch t := term.(type) {
+		case *Star:
+			// the modifiers of a qualified star, e.g. t.* EXCEPT (id), are read by the select list
+			if aliases {
+				return term, nil
+			}
+		case *Keyword:
+			// the unit of an interval is a keyword even though it looks like an alias, e.g. INTERVAL 1 DAY
+			if t.Value == "INTERVAL" && !p.atStatementEnd() {
+				value, err := p.parseTerm()
+				if err != nil {
+					return nil, err
+				}
+				terms = append(terms, value)
+
+				if p.peek().Kind == Word && !reservedKeywords[strings.ToUpper(p.peek().Value)] {
+					terms = append(terms, &Keyword{Value: strings.ToUpper(p.advance().Value)})
+				}
+			}
+		}
+	}
+
+	switch len(terms) {
+	case 0:
+		return nil, nil
+	case 1:
+		return terms[0], nil
+	}
+
+	return &Compound{Terms: terms}, nil
+}
+
+func (p *parser) isClosingBracket() bool {
+	return p.peek().Kind == Symbol && p.peek().Value == "]"
+}
+
+// endsOperand reports whether the term can be the last one of an expression, which means the next word is an alias.
+func endsOperand(term Expr) bool {
+	switch t := term.(type) {
+	case *Operator:
+		return false
+	case *Keyword:
+		return t.Value == "END"
+	}
+
+	return true
+}
+
+func (p *parser) parseTerm() (Expr, error) {
+	token := p.peek()
+	switch token.Kind {
+	case String:
+		p.advance()
+		return &Literal{Kind: StringLiteral, Value: unquote(token.Value), Line: token.Line}, nil
+	case Number:
+		p.advance()
+		return &Literal{Kind: NumberLiteral, Value: token.Value, Line: token.Line}, nil
+	case Parameter:
+		p.advance()
+		return &Param{Name: token.Value}, nil
+	case Template:
+		// a template can be a part of a name, e.g. {{ project }}.sales.orders or {{ env }}_orders
+		next := p.peekAt(1)
+		if next.IsPunctuation(".") || (adjacent(token, next) && (next.Kind == Word || next.Kind == Number || next.Kind == Template)) {
+			return p.parseNameTerm()
+		}
+
+		p.advance()
+		return &TemplateExpr{Value: token.Value, Line: token.Line}, nil
+	case QuotedIdentifier:
+		return p.parseNameTerm()
+	case Word:
+		return p.parseWordTerm()
+	case Symbol:
+		p.advance()
+		if token.Value == "[" {
+			return p.parseGroup("]")
+		}
+
+		return &Operator{Value: token.Value}, nil
+	case Punctuation:
+		switch token.Value {
+		case "(":
+			return p.parseParenthesized()
+		case ".":
+			p.advance()
+			return &Operator{Value: token.Value}, nil
+		}
+	}
+
+	return nil, p.unexpected()
+}
+
+func (p *parser) parseWordTerm() (Expr, error) {
+	token := p.peek()
+	keyword := strings.ToUpper(token.Value)
+	switch {
+	case keyword == "TRUE" || keyword == "FALSE":
+		p.advance()
+		return &Literal{Kind: BoolLiteral, Value: keyword, Line: token.Line}, nil
+	case keyword == "NULL":
+		p.advance()
+		return &Literal{Kind: NullLiteral, Value: keyword, Line: token.Line}, nil
+	case typedLiterals[keyword] && p.peekAt(1).Kind == String:
+		p.advance()
+		value := p.advance()
+		return &Literal{Kind: StringLiteral, Type: keyword, Value: unquote(value.Value), Line: token.Line}, nil
+	case reservedKeywords[keyword] && !(functionKeywords[keyword] && p.peekAt(1).IsPunctuation("(")):
+		p.advance()
+		return &Keyword{Value: keyword}, nil
+	}
+
+	return p.parseNameTerm()
+}
+
+// parseNameTerm reads a column, a qualified star or a function call.
+func (p *parser) parseNameTerm() (Expr, error) {
+	line := p.peek().Line
+	parts, err := p.parseName(false)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case p.peek().IsPunctuation(".") && p.peekAt(1).Kind == Symbol && p.peekAt(1).Value == "*":
+		p.pos += 2
+		return &Star{Qualifier: parts, Line: line}, nil
+	case p.peek().IsPunctuation("("):
+		args, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+
+		return &FunctionCall{Name: parts, Args: args, Line: line}, nil
+	}
+
+	return &Identifier{Parts: parts, Line: line}, nil
+}
+
+func (p *parser) parseArgs() ([]Expr, error) {
+	term, err := p.parseParenthesized()
+	if err != nil {
+		return nil, err
+	}
+
+	if group, ok := term.(*Group); ok {
+		return group.Items, nil
+	}
+
+	return []Expr{term}, nil
+}
+
+// parseParenthesized reads either a subquery or a group of expressions. The subqueries that cannot be parsed are read
+// as a group instead, so that the unsupported syntax only loses the structure of the subquery.
+func (p *parser) parseParenthesized() (Expr, error) {
+	if p.startsQuery(1) {
+		start := p.pos
+		p.advance()
+
+		query, err := p.parseQuery()
+		if err == nil && p.peek().IsPunctuation(")") {
+			p.advance()
+			return &Subquery{Query: query}, nil
+		}
+
+		p.pos = start
+	}
+
+	p.advance()
+	return p.parseGroup(")")
+}
+
+// parseGroup reads the expressions until the given closing token, the opening one is already consumed.
+func (p *parser) parseGroup(closing string) (Expr, error) {
+	group := &Group{}
+	for {
+		token := p.peek()
+		switch {
+		case token.Value == closing && (token.Kind == Punctuation || token.Kind == Symbol):
+			p.advance()
+			return group, nil
+		case token.IsPunctuation(","):
+			p.advance()
+			continue
+		}
+
+		expr, err := p.parseExprUntil(nil, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if expr == nil {
+			return nil, p.unexpected()
+		}
+		group.Items = append(group.Items, expr)
+	}
+}
+
+// unquote removes the quotes around a string or an identifier, including the prefixes of the raw and the bytes strings
+// and the triple quotes, without resolving the escape sequences.
+func unquote(value string) string {
+	value = strings.TrimLeft(value, "rRbB")
+	for _, quote := range []string{`'''`, `"""`, "$$", "'", `"`, "`"} {
+		if len(value) >= 2*len(quote) && strings.HasPrefix(value, quote) && strings.HasSuffix(value, quote) {
+			return value[len(quote) : len(value)-len(quote)]
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,319 @@
+package sqlparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sql     string
+		dialect Dialect
+		want    []Statement
+	}{
+		{
+			name: "select with joins, aliases and clauses",
+			sql: "-- @blast.name: orders\n" +
+				"SELECT o.id, COUNT(*) AS total, c.name customer\n" +
+				"FROM `project.sales.orders` o\n" +
+				"LEFT JOIN sales.customers AS c ON o.customer_id = c.id\n" +
+				"WHERE o.dt = DATE '2022-01-01'\n" +
+				"GROUP BY 1, 3\n" +
+				"ORDER BY total DESC\n" +
+				"LIMIT 10",
+			dialect: BigQuery,
+			want: []Statement{
+				&QueryStatement{
+					Line: 2,
+					Query: &Query{
+						Line: 2,
+						Body: &Select{
+							Line: 2,
+							Columns: []*SelectItem{
+								{Expr: &Identifier{Parts: []string{"o", "id"}, Line: 2}},
+								{Expr: &FunctionCall{Name: []string{"COUNT"}, Args: []Expr{&Operator{Value: "*"}}, Line: 2}, Alias: "total"},
+								{Expr: &Identifier{Parts: []string{"c", "name"}, Line: 2}, Alias: "customer"},
+							},
+							From: []TableExpr{
+								&Join{
+									Kind:  "LEFT",
+									Left:  &TableName{Parts: []string{"project", "sales", "orders"}, Alias: "o", Line: 3},
+									Right: &TableName{Parts: []string{"sales", "customers"}, Alias: "c", Line: 4},
+									Condition: &Compound{Terms: []Expr{
+										&Identifier{Parts: []string{"o", "customer_id"}, Line: 4},
+										&Operator{Value: "="},
+										&Identifier{Parts: []string{"c", "id"}, Line: 4},
+									}},
+								},
+							},
+							Where: &Compound{Terms: []Expr{
+								&Identifier{Parts: []string{"o", "dt"}, Line: 5},
+								&Operator{Value: "="},
+								&Literal{Kind: StringLiteral, Type: "DATE", Value: "2022-01-01", Line: 5},
+							}},
+							GroupBy: []Expr{
+								&Literal{Kind: NumberLiteral, Value: "1", Line: 6},
+								&Literal{Kind: NumberLiteral, Value: "3", Line: 6},
+							},
+						},
+						OrderBy: []Expr{
+							&Compound{Terms: []Expr{&Identifier{Parts: []string{"total"}, Line: 7}, &Keyword{Value: "DESC"}}},
+						},
+						Limit: &Literal{Kind: NumberLiteral, Value: "10", Line: 8},
+					},
+				},
+			},
+		},
+		{
+			name:    "ctes, stars and set operations",
+			sql:     "WITH recent AS (SELECT * FROM raw.events) SELECT r.* EXCEPT (id) FROM recent r UNION ALL SELECT * EXCLUDE id FROM my-project.raw.archive",
+			dialect: BigQuery,
+			want: []Statement{
+				&QueryStatement{
+					Line: 1,
+					Query: &Query{
+						Line: 1,
+						With: []*CommonTableExpression{
+							{
+								Name: "recent",
+								Query: &Query{
+									Line: 1,
+									Body: &Select{
+										Line:    1,
+										Columns: []*SelectItem{{Expr: &Star{Line: 1}}},
+										From:    []TableExpr{&TableName{Parts: []string{"raw", "events"}, Line: 1}},
+									},
+								},
+							},
+						},
+						Body: &SetOperation{
+							Operator: "UNION ALL",
+							Left: &Select{
+								Line: 1,
+								Columns: []*SelectItem{{Expr: &Star{
+									Qualifier: []string{"r"},
+									Modifiers: []Expr{&Keyword{Value: "EXCEPT"}, &Group{Items: []Expr{&Identifier{Parts: []string{"id"}, Line: 1}}}},
+									Line:      1,
+								}}},
+								From: []TableExpr{&TableName{Parts: []string{"recent"}, Alias: "r", Line: 1}},
+							},
+							Right: &Select{
+								Line: 1,
+								Columns: []*SelectItem{{Expr: &Star{
+									Modifiers: []Expr{&Keyword{Value: "EXCLUDE"}, &Identifier{Parts: []string{"id"}, Line: 1}},
+									Line:      1,
+								}}},
+								From: []TableExpr{&TableName{Parts: []string{"my-project", "raw", "archive"}, Line: 1}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "data manipulation statements",
+			sql:     "CREATE OR REPLACE TEMP TABLE staging PARTITION BY dt, region AS SELECT id FROM sales.orders ORDER BY id;\nDELETE FROM sales.orders WHERE dt = '{{ ds }}';\nTRUNCATE TABLE sales.staging;\nINSERT INTO sales.orders (id) VALUES (1), (2)",
+			dialect: BigQuery,
+			want: []Statement{
+				&CreateTable{
+					Line:      1,
+					Name:      &TableName{Parts: []string{"staging"}, Line: 1},
+					Temporary: true,
+					Query: &Query{
+						Line: 1,
+						Body: &Select{
+							Line:    1,
+							Columns: []*SelectItem{{Expr: &Identifier{Parts: []string{"id"}, Line: 1}}},
+							From:    []TableExpr{&TableName{Parts: []string{"sales", "orders"}, Line: 1}},
+						},
+						OrderBy: []Expr{&Identifier{Parts: []string{"id"}, Line: 1}},
+					},
+				},
+				&Delete{
+					Line:  2,
+					Table: &TableName{Parts: []string{"sales", "orders"}, Line: 2},
+					Where: &Compound{Terms: []Expr{
+						&Identifier{Parts: []string{"dt"}, Line: 2},
+						&Operator{Value: "="},
+						&Literal{Kind: StringLiteral, Value: "{{ ds }}", Line: 2},
+					}},
+				},
+				&Truncate{Line: 3, Table: &TableName{Parts: []string{"sales", "staging"}, Line: 3}},
+				&Insert{
+					Line:  4,
+					Table: &TableName{Parts: []string{"sales", "orders"}, Line: 4},
+					Values: []Expr{
+						&Group{Items: []Expr{&Literal{Kind: NumberLiteral, Value: "1", Line: 4}}},
+						&Group{Items: []Expr{&Literal{Kind: NumberLiteral, Value: "2", Line: 4}}},
+					},
+				},
+			},
+		},
+		{
+			name:    "snowflake identifiers, templates and table functions",
+			sql:     "SELECT f.value:id::string AS id FROM \"Raw\".{{ schema }}.events e, LATERAL FLATTEN(input => e.items) f WHERE e.dt = {{ ds }}",
+			dialect: Snowflake,
+			want: []Statement{
+				&QueryStatement{
+					Line: 1,
+					Query: &Query{
+						Line: 1,
+						Body: &Select{
+							Line: 1,
+							Columns: []*SelectItem{{
+								Expr: &Compound{Terms: []Expr{
+									&Identifier{Parts: []string{"f", "value"}, Line: 1},
+									&Operator{Value: ":"},
+									&Identifier{Parts: []string{"id"}, Line: 1},
+									&Operator{Value: "::"},
+									&Identifier{Parts: []string{"string"}, Line: 1},
+								}},
+								Alias: "id",
+							}},
+							From: []TableExpr{
+								&TableName{Parts: []string{"Raw", "{{ schema }}", "events"}, Alias: "e", Line: 1},
+								&TableFunction{
+									Call: &FunctionCall{
+										Name: []string{"FLATTEN"},
+										Args: []Expr{&Compound{Terms: []Expr{
+											&Identifier{Parts: []string{"input"}, Line: 1},
+											&Operator{Value: "=>"},
+											&Identifier{Parts: []string{"e", "items"}, Line: 1},
+										}}},
+										Line: 1,
+									},
+									Alias: "f",
+								},
+							},
+							Where: &Compound{Terms: []Expr{
+								&Identifier{Parts: []string{"e", "dt"}, Line: 1},
+								&Operator{Value: "="},
+								&TemplateExpr{Value: "{{ ds }}", Line: 1},
+							}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "unsupported statements keep their expressions",
+			sql:     "MERGE sales.orders t USING (SELECT id FROM sales.staging) s ON t.id = s.id WHEN MATCHED THEN DELETE",
+			dialect: BigQuery,
+			want: []Statement{
+				&OtherStatement{
+					Keyword: "MERGE",
+					Line:    1,
+					Exprs: []Expr{&Compound{Terms: []Expr{
+						&Identifier{Parts: []string{"MERGE"}, Line: 1},
+						&Identifier{Parts: []string{"sales", "orders"}, Line: 1},
+						&Identifier{Parts: []string{"t"}, Line: 1},
+						&Keyword{Value: "USING"},
+						&Subquery{Query: &Query{
+							Line: 1,
+							Body: &Select{
+								Line:    1,
+								Columns: []*SelectItem{{Expr: &Identifier{Parts: []string{"id"}, Line: 1}}},
+								From:    []TableExpr{&TableName{Parts: []string{"sales", "staging"}, Line: 1}},
+							},
+						}},
+						&Identifier{Parts: []string{"s"}, Line: 1},
+						&Keyword{Value: "ON"},
+						&Identifier{Parts: []string{"t", "id"}, Line: 1},
+						&Operator{Value: "="},
+						&Identifier{Parts: []string{"s", "id"}, Line: 1},
+						&Keyword{Value: "WHEN"},
+						&Identifier{Parts: []string{"MATCHED"}, Line: 1},
+						&Keyword{Value: "THEN"},
+						&Identifier{Parts: []string{"DELETE"}, Line: 1},
+					}}},
+				},
+			},
+		},
+		{
+			name:    "columns named like the select modifiers",
+			sql:     "SELECT top, value FROM t",
+			dialect: Snowflake,
+			want: []Statement{
+				&QueryStatement{
+					Line: 1,
+					Query: &Query{
+						Line: 1,
+						Body: &Select{
+							Line: 1,
+							Columns: []*SelectItem{
+								{Expr: &Identifier{Parts: []string{"top"}, Line: 1}},
+								{Expr: &Identifier{Parts: []string{"value"}, Line: 1}},
+							},
+							From: []TableExpr{&TableName{Parts: []string{"t"}, Line: 1}},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(tt.sql, tt.dialect)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sql     string
+		wantErr string
+	}{
+		{sql: "SELECT (1", wantErr: "unexpected end of the query"},
+		{sql: "SELECT 1)", wantErr: "unexpected ')' at line 1"},
+		{sql: "SELECT 'abc", wantErr: "line 1: the string is not closed"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.sql, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(tt.sql, BigQuery)
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	statements, err := Parse("SELECT 'a' FROM t WHERE x IN (SELECT 'b' FROM u WHERE y = 'c'); DELETE FROM v WHERE z = 'd'", BigQuery)
+	require.NoError(t, err)
+
+	var literals []string
+	var tables []string
+	for _, statement := range statements {
+		Inspect(statement, func(node Node) bool {
+			switch n := node.(type) {
+			case *Literal:
+				literals = append(literals, n.Value)
+			case *TableName:
+				tables = append(tables, n.String())
+			case *Subquery:
+				// the subqueries are skipped along with their children
+				return false
+			}
+
+			return true
+		})
+	}
+
+	assert.Equal(t, []string{"a", "d"}, literals)
+	assert.Equal(t, []string{"t", "v"}, tables)
+}
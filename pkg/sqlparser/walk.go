@@ -0,0 +1,99 @@
+package sqlparser
+
+// Inspect walks the tree in depth-first order, calling fn for every node. The children of a node are skipped when fn
+// returns false for it.
+func Inspect(node Node, fn func(node Node) bool) {
+	if isNil(node) || !fn(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *QueryStatement:
+		Inspect(n.Query, fn)
+	case *CreateTable:
+		Inspect(n.Name, fn)
+		Inspect(n.Query, fn)
+	case *Insert:
+		Inspect(n.Table, fn)
+		Inspect(n.Query, fn)
+		inspectExprs(n.Values, fn)
+	case *Delete:
+		Inspect(n.Table, fn)
+		inspectTables(n.Using, fn)
+		Inspect(n.Where, fn)
+	case *Truncate:
+		Inspect(n.Table, fn)
+	case *OtherStatement:
+		inspectExprs(n.Exprs, fn)
+	case *Query:
+		for _, cte := range n.With {
+			Inspect(cte, fn)
+		}
+		Inspect(n.Body, fn)
+		inspectExprs(n.OrderBy, fn)
+		Inspect(n.Limit, fn)
+	case *CommonTableExpression:
+		Inspect(n.Query, fn)
+	case *Select:
+		for _, column := range n.Columns {
+			Inspect(column, fn)
+		}
+		inspectTables(n.From, fn)
+		Inspect(n.Where, fn)
+		inspectExprs(n.GroupBy, fn)
+		Inspect(n.Having, fn)
+		Inspect(n.Qualify, fn)
+	case *SelectItem:
+		Inspect(n.Expr, fn)
+	case *SetOperation:
+		Inspect(n.Left, fn)
+		Inspect(n.Right, fn)
+	case *DerivedTable:
+		Inspect(n.Query, fn)
+	case *TableFunction:
+		Inspect(n.Call, fn)
+	case *Join:
+		Inspect(n.Left, fn)
+		Inspect(n.Right, fn)
+		Inspect(n.Condition, fn)
+	case *Star:
+		inspectExprs(n.Modifiers, fn)
+	case *FunctionCall:
+		inspectExprs(n.Args, fn)
+	case *Subquery:
+		Inspect(n.Query, fn)
+	case *Group:
+		inspectExprs(n.Items, fn)
+	case *Compound:
+		inspectExprs(n.Terms, fn)
+	}
+}
+
+func inspectExprs(exprs []Expr, fn func(node Node) bool) {
+	for _, expr := range exprs {
+		Inspect(expr, fn)
+	}
+}
+
+func inspectTables(tables []TableExpr, fn func(node Node) bool) {
+	for _, table := range tables {
+		Inspect(table, fn)
+	}
+}
+
+// isNil reports whether the node is nil, including the typed nil pointers stored in the interfaces, e.g. a missing
+// WHERE clause.
+func isNil(node Node) bool {
+	switch n := node.(type) {
+	case nil:
+		return true
+	case *Query:
+		return n == nil
+	case *TableName:
+		return n == nil
+	case *FunctionCall:
+		return n == nil
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+package tasktype
+
+// sensorParameters are shared by all the sensors: `poke_interval` is the time between two checks and `timeout` is the
+// time after which the sensor gives up.
+var sensorParameters = []Parameter{
+	{Name: "poke_interval", Type: Duration, Default: "1m"},
+	{Name: "timeout", Type: Duration},
+}
+
+// builtinTypes are the task types that come with the CLI, the parameters of the types that are exported as Airflow
+// operator arguments are named after the arguments.
+var builtinTypes = []*TaskType{
+	{Name: "bq.sql", AcceptsAnyParameter: true, Extension: ".sql"},
+	{Name: "sf.sql", AcceptsAnyParameter: true, Extension: ".sql"},
+	{Name: "bash", AcceptsAnyParameter: true, Extension: ".sh", Script: true},
+	{Name: "python", AcceptsAnyParameter: true, Extension: ".py", RequiresExecutableFile: true, Script: true},
+	{Name: "bq.cost_tracker", AcceptsAnyParameter: true},
+	{
+		Name: "bq.sensor.table",
+		Parameters: append([]Parameter{
+			{Name: "project_id", Type: String, Required: true},
+			{Name: "dataset_id", Type: String, Required: true},
+			{Name: "table_id", Type: String, Required: true},
+		}, sensorParameters...),
+	},
+	{
+		Name: "bq.sensor.query",
+		Parameters: append([]Parameter{
+			{Name: "sql", Type: String, Required: true},
+		}, sensorParameters...),
+	},
+	{
+		Name: "s3.sensor.key_sensor",
+		Parameters: append([]Parameter{
+			{Name: "bucket_key", Type: String, Required: true},
+			{Name: "bucket_name", Type: String},
+		}, sensorParameters...),
+	},
+	{
+		Name: "gcs.sensor.object_sensor_with_prefix",
+		Parameters: append([]Parameter{
+			{Name: "bucket", Type: String, Required: true},
+			{Name: "prefix", Type: String, Required: true},
+		}, sensorParameters...),
+	},
+	{
+		Name: "gcs.from.s3",
+		Parameters: []Parameter{
+			{Name: "bucket", Type: String, Required: true},
+			{Name: "prefix", Type: String},
+			{Name: "delimiter", Type: String},
+			{Name: "dest_gcs", Type: GCSURI, Required: true},
+			{Name: "replace", Type: Bool},
+			{Name: "gzip", Type: Bool},
+		},
+	},
+}
+
+// Builtin returns a new registry with the built-in task types, more types can be registered on top of them.
+func Builtin() *Registry {
+	r := NewRegistry()
+	for _, t := range builtinTypes {
+		if err := r.Register(t); err != nil {
+			panic(err)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,89 @@
+package tasktype
+
+import (
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/pkg/errors"
+)
+
+// Load returns a registry with the built-in task types and the custom ones declared in the project config.
+func Load(customTypes map[string]config.TaskType) (*Registry, error) {
+	r := Builtin()
+
+	names := make([]string, 0, len(customTypes))
+	for name := range customTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t, err := newCustomType(name, customTypes[name])
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Get(name) != nil {
+			return nil, errors.Errorf("the custom task type '%s' has the same name as a built-in type", name)
+		}
+
+		if err := r.Register(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func newCustomType(name string, c config.TaskType) (*TaskType, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("the custom task types must have a name")
+	}
+
+	if c.Extension != "" && (!strings.HasPrefix(c.Extension, ".") || len(c.Extension) == 1) {
+		return nil, errors.Errorf("the extension of the task type '%s' must start with a dot, e.g. '.sql', got '%s'", name, c.Extension)
+	}
+
+	if c.CommentPrefix != "" && c.Extension == "" {
+		return nil, errors.Errorf("the task type '%s' has a comment prefix without an extension", name)
+	}
+
+	if c.Command != "" {
+		if _, err := template.New(name).Parse(c.Command); err != nil {
+			return nil, errors.Wrapf(err, "the command of the task type '%s' is not a valid template", name)
+		}
+	}
+
+	t := &TaskType{
+		Name:                   name,
+		AcceptsAnyParameter:    c.AcceptsAnyParameter,
+		RequiresExecutableFile: c.RequiresExecutableFile,
+		Extension:              c.Extension,
+		Command:                c.Command,
+	}
+
+	parameterNames := make([]string, 0, len(c.Parameters))
+	for parameterName := range c.Parameters {
+		parameterNames = append(parameterNames, parameterName)
+	}
+	sort.Strings(parameterNames)
+
+	for _, parameterName := range parameterNames {
+		parameter := c.Parameters[parameterName]
+		parameterType := ParameterType(parameter.Type)
+		if parameterType == "" {
+			parameterType = String
+		}
+
+		t.Parameters = append(t.Parameters, Parameter{
+			Name:     parameterName,
+			Type:     parameterType,
+			Required: parameter.Required,
+			Default:  parameter.Default,
+		})
+	}
+
+	return t, nil
+}
@@ -0,0 +1,95 @@
+package tasktype
+
+import (
+	"testing"
+
+	"github.com/datablast-analytics/blast-cli/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	registry, err := Load(map[string]config.TaskType{
+		"dbt.model": {
+			Extension:              ".sql",
+			RequiresExecutableFile: true,
+			Command:                "dbt run --select {{ .Parameters.model }}",
+			Parameters: map[string]config.TaskTypeParameter{
+				"model":   {Required: true},
+				"threads": {Type: "int", Default: "4"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.NotNil(t, registry.Get("bq.sql"))
+	assert.Equal(t, &TaskType{
+		Name: "dbt.model",
+		Parameters: []Parameter{
+			{Name: "model", Type: String, Required: true},
+			{Name: "threads", Type: Int, Default: "4"},
+		},
+		RequiresExecutableFile: true,
+		Extension:              ".sql",
+		Command:                "dbt run --select {{ .Parameters.model }}",
+	}, registry.Get("dbt.model"))
+}
+
+func TestLoad_InvalidTypes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		taskType config.TaskType
+		typeName string
+		wantErr  string
+	}{
+		{
+			name:     "built-in types cannot be replaced",
+			typeName: "bq.sql",
+			wantErr:  "the custom task type 'bq.sql' has the same name as a built-in type",
+		},
+		{
+			name:     "extensions must start with a dot",
+			typeName: "dbt.model",
+			taskType: config.TaskType{Extension: "sql"},
+			wantErr:  "the extension of the task type 'dbt.model' must start with a dot, e.g. '.sql', got 'sql'",
+		},
+		{
+			name:     "comment prefixes require an extension",
+			typeName: "dbt.model",
+			taskType: config.TaskType{CommentPrefix: "--"},
+			wantErr:  "the task type 'dbt.model' has a comment prefix without an extension",
+		},
+		{
+			name:     "commands must be valid templates",
+			typeName: "dbt.model",
+			taskType: config.TaskType{Command: "dbt run --select {{ .Parameters.model"},
+			wantErr:  "the command of the task type 'dbt.model' is not a valid template",
+		},
+		{
+			name:     "parameter types must be known",
+			typeName: "dbt.model",
+			taskType: config.TaskType{Parameters: map[string]config.TaskTypeParameter{"day": {Type: "date"}}},
+			wantErr:  "the parameter 'day' of the task type 'dbt.model' has an unknown type 'date'",
+		},
+		{
+			name:     "defaults must match the parameter types",
+			typeName: "dbt.model",
+			taskType: config.TaskType{Parameters: map[string]config.TaskTypeParameter{"threads": {Type: "int", Default: "four"}}},
+			wantErr:  "the default of the parameter 'threads' of the task type 'dbt.model' must be a whole number, got 'four'",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Load(map[string]config.TaskType{tt.typeName: tt.taskType})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package tasktype
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ParameterType decides which values a parameter accepts, all the parameters are strings in the task definitions.
+type ParameterType string
+
+const (
+	String   ParameterType = "string"
+	Int      ParameterType = "int"
+	Bool     ParameterType = "bool"
+	Duration ParameterType = "duration"
+	GCSURI   ParameterType = "gcs-uri"
+	S3URI    ParameterType = "s3-uri"
+)
+
+// parameterTypes maps the types to the description of the values they accept, which is used in the error messages.
+var parameterTypes = map[ParameterType]string{
+	String:   "a string",
+	Int:      "a whole number",
+	Bool:     "either true or false",
+	Duration: "a positive duration such as 30s, 5m or 1h",
+	GCSURI:   "a GCS URI such as gs://bucket/path",
+	S3URI:    "an S3 URI such as s3://bucket/path",
+}
+
+// Check returns an error if the value is not valid for the type.
+func (t ParameterType) Check(value string) error {
+	valid := true
+	switch t {
+	case String:
+	case Int:
+		_, err := strconv.Atoi(value)
+		valid = err == nil
+	case Bool:
+		_, err := strconv.ParseBool(value)
+		valid = err == nil
+	case Duration:
+		duration, err := time.ParseDuration(value)
+		valid = err == nil && duration > 0
+	case GCSURI:
+		valid = hasBucket(value, "gs://")
+	case S3URI:
+		valid = hasBucket(value, "s3://")
+	default:
+		return errors.Errorf("unknown parameter type '%s'", t)
+	}
+
+	if !valid {
+		return errors.Errorf("must be %s, got '%s'", parameterTypes[t], value)
+	}
+
+	return nil
+}
+
+func hasBucket(value, scheme string) bool {
+	bucket, _, _ := strings.Cut(strings.TrimPrefix(value, scheme), "/")
+	return strings.HasPrefix(value, scheme) && bucket != ""
+}
+
+type Parameter struct {
+	Name     string
+	Type     ParameterType
+	Required bool
+
+	// Default is used when neither the task nor the pipeline sets the parameter.
+	Default string
+}
+
+type TaskType struct {
+	Name       string
+	Parameters []Parameter
+
+	// AcceptsAnyParameter is set for the types whose parameters are free-form, e.g. the template variables of the
+	// queries or the environment variables of the scripts. The parameters that are listed are still checked.
+	AcceptsAnyParameter bool
+
+	// RequiresExecutableFile is set for the types that cannot run without a file, e.g. the Python scripts.
+	RequiresExecutableFile bool
+
+	// Script is set for the types whose files are run as programs, e.g. the Bash scripts, they must be executable.
+	Script bool
+
+	// Extension is the extension of the files the tasks run, the types without one cannot be scaffolded.
+	Extension string
+
+	// Command is the template of the shell command that runs the tasks of the custom types locally.
+	Command string
+}
+
+// Parameter returns the parameter with the given name, nil if the type does not have it.
+func (t *TaskType) Parameter(name string) *Parameter {
+	for i := range t.Parameters {
+		if t.Parameters[i].Name == name {
+			return &t.Parameters[i]
+		}
+	}
+
+	return nil
+}
+
+// Resolve returns the parameters of a task of this type: the defaults of the type, overridden by the default
+// parameters of the pipeline, overridden by the parameters of the task. The default parameters of the pipeline are
+// shared by all the tasks, so the types that do not accept any parameter only take the ones they know.
+func (t *TaskType) Resolve(taskParameters, pipelineParameters map[string]string) map[string]string {
+	resolved := make(map[string]string, len(t.Parameters)+len(taskParameters))
+	for _, parameter := range t.Parameters {
+		if parameter.Default != "" {
+			resolved[parameter.Name] = parameter.Default
+		}
+	}
+
+	for name, value := range pipelineParameters {
+		if t.AcceptsAnyParameter || t.Parameter(name) != nil {
+			resolved[name] = value
+		}
+	}
+
+	for name, value := range taskParameters {
+		resolved[name] = value
+	}
+
+	return resolved
+}
+
+// Validate returns the problems with the parameters of a task of this type, the values are checked after they are
+// resolved with the default parameters of the pipeline.
+func (t *TaskType) Validate(taskParameters, pipelineParameters map[string]string) []string {
+	problems := make([]string, 0)
+	if !t.AcceptsAnyParameter {
+		for _, name := range sortedKeys(taskParameters) {
+			if t.Parameter(name) == nil {
+				problems = append(problems, fmt.Sprintf("the parameter '%s' is not known, the parameters of '%s' are: %s", name, t.Name, strings.Join(t.parameterNames(), ", ")))
+			}
+		}
+	}
+
+	resolved := t.Resolve(taskParameters, pipelineParameters)
+	for _, parameter := range t.Parameters {
+		value, ok := resolved[parameter.Name]
+		if !ok || strings.TrimSpace(value) == "" {
+			if parameter.Required {
+				problems = append(problems, fmt.Sprintf("the parameter '%s' is required", parameter.Name))
+			}
+
+			continue
+		}
+
+		if err := parameter.Type.Check(value); err != nil {
+			problems = append(problems, fmt.Sprintf("the parameter '%s' %v", parameter.Name, err))
+		}
+	}
+
+	return problems
+}
+
+func (t *TaskType) parameterNames() []string {
+	names := make([]string, 0, len(t.Parameters))
+	for _, parameter := range t.Parameters {
+		names = append(names, parameter.Name)
+	}
+
+	return names
+}
+
+// Registry holds the task types the CLI knows about.
+type Registry struct {
+	types map[string]*TaskType
+}
+
+func NewRegistry() *Registry {
+	return &Registry{types: make(map[string]*TaskType)}
+}
+
+// Register adds the task type to the registry, the names of the types must be unique.
+func (r *Registry) Register(t *TaskType) error {
+	if _, ok := r.types[t.Name]; ok {
+		return errors.Errorf("the task type '%s' is already registered", t.Name)
+	}
+
+	for _, parameter := range t.Parameters {
+		if _, ok := parameterTypes[parameter.Type]; !ok {
+			return errors.Errorf("the parameter '%s' of the task type '%s' has an unknown type '%s'", parameter.Name, t.Name, parameter.Type)
+		}
+
+		if parameter.Default == "" {
+			continue
+		}
+
+		if err := parameter.Type.Check(parameter.Default); err != nil {
+			return errors.Errorf("the default of the parameter '%s' of the task type '%s' %v", parameter.Name, t.Name, err)
+		}
+	}
+
+	r.types[t.Name] = t
+
+	return nil
+}
+
+// Get returns the task type with the given name, nil if it is not registered.
+func (r *Registry) Get(name string) *TaskType {
+	return r.types[name]
+}
+
+// Names returns the names of the registered task types, sorted alphabetically.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
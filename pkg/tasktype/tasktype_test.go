@@ -0,0 +1,177 @@
+package tasktype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterType_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		parameterType ParameterType
+		value         string
+		wantErr       string
+	}{
+		{name: "any string", parameterType: String, value: "anything"},
+		{name: "valid int", parameterType: Int, value: "42"},
+		{name: "invalid int", parameterType: Int, value: "4.2", wantErr: "must be a whole number, got '4.2'"},
+		{name: "valid bool", parameterType: Bool, value: "true"},
+		{name: "invalid bool", parameterType: Bool, value: "yes", wantErr: "must be either true or false, got 'yes'"},
+		{name: "valid duration", parameterType: Duration, value: "1h30m"},
+		{name: "duration without a unit", parameterType: Duration, value: "60", wantErr: "must be a positive duration such as 30s, 5m or 1h, got '60'"},
+		{name: "negative duration", parameterType: Duration, value: "-1m", wantErr: "must be a positive duration such as 30s, 5m or 1h, got '-1m'"},
+		{name: "valid gcs uri", parameterType: GCSURI, value: "gs://landing/orders/"},
+		{name: "gcs uri without a bucket", parameterType: GCSURI, value: "gs:///orders", wantErr: "must be a GCS URI such as gs://bucket/path, got 'gs:///orders'"},
+		{name: "s3 uri given for gcs", parameterType: GCSURI, value: "s3://landing", wantErr: "must be a GCS URI such as gs://bucket/path, got 's3://landing'"},
+		{name: "valid s3 uri", parameterType: S3URI, value: "s3://exports"},
+		{name: "s3 uri without a scheme", parameterType: S3URI, value: "exports/orders", wantErr: "must be an S3 URI such as s3://bucket/path, got 'exports/orders'"},
+		{name: "unknown type", parameterType: "date", value: "2022-01-01", wantErr: "unknown parameter type 'date'"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.parameterType.Check(tt.value)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestTaskType_Resolve(t *testing.T) {
+	t.Parallel()
+
+	closed := &TaskType{
+		Name: "closed",
+		Parameters: []Parameter{
+			{Name: "bucket", Type: String},
+			{Name: "interval", Type: Duration, Default: "1m"},
+		},
+	}
+	open := &TaskType{Name: "open", AcceptsAnyParameter: true, Parameters: closed.Parameters}
+	pipelineParameters := map[string]string{"bucket": "landing", "env": "prod"}
+
+	assert.Equal(t,
+		map[string]string{"bucket": "landing", "interval": "5m", "key": "value"},
+		closed.Resolve(map[string]string{"interval": "5m", "key": "value"}, pipelineParameters),
+	)
+	assert.Equal(t,
+		map[string]string{"bucket": "exports", "interval": "1m", "env": "prod"},
+		open.Resolve(map[string]string{"bucket": "exports"}, pipelineParameters),
+	)
+}
+
+func TestTaskType_Validate(t *testing.T) {
+	t.Parallel()
+
+	taskType := &TaskType{
+		Name: "gcs.from.s3",
+		Parameters: []Parameter{
+			{Name: "bucket", Type: String, Required: true},
+			{Name: "dest_gcs", Type: GCSURI, Required: true},
+			{Name: "replace", Type: Bool},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		taskType           *TaskType
+		taskParameters     map[string]string
+		pipelineParameters map[string]string
+		want               []string
+	}{
+		{
+			name:           "valid parameters",
+			taskType:       taskType,
+			taskParameters: map[string]string{"bucket": "exports", "dest_gcs": "gs://landing/", "replace": "false"},
+			want:           []string{},
+		},
+		{
+			name:               "the pipeline defaults are merged in",
+			taskType:           taskType,
+			taskParameters:     map[string]string{"bucket": "exports"},
+			pipelineParameters: map[string]string{"dest_gcs": "gs://landing/", "env": "prod"},
+			want:               []string{},
+		},
+		{
+			name:           "missing, unknown and invalid parameters",
+			taskType:       taskType,
+			taskParameters: map[string]string{"bucket": " ", "dest": "gs://landing/", "replace": "yes"},
+			want: []string{
+				"the parameter 'dest' is not known, the parameters of 'gcs.from.s3' are: bucket, dest_gcs, replace",
+				"the parameter 'bucket' is required",
+				"the parameter 'dest_gcs' is required",
+				"the parameter 'replace' must be either true or false, got 'yes'",
+			},
+		},
+		{
+			name:               "the pipeline defaults are checked as well",
+			taskType:           taskType,
+			taskParameters:     map[string]string{"bucket": "exports"},
+			pipelineParameters: map[string]string{"dest_gcs": "landing"},
+			want:               []string{"the parameter 'dest_gcs' must be a GCS URI such as gs://bucket/path, got 'landing'"},
+		},
+		{
+			name:           "types that accept any parameter only check the known ones",
+			taskType:       &TaskType{Name: "bq.sql", AcceptsAnyParameter: true, Parameters: []Parameter{{Name: "limit", Type: Int}}},
+			taskParameters: map[string]string{"anything": "goes", "limit": "ten"},
+			want:           []string{"the parameter 'limit' must be a whole number, got 'ten'"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, tt.taskType.Validate(tt.taskParameters, tt.pipelineParameters))
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	require.NoError(t, r.Register(&TaskType{Name: "bash", AcceptsAnyParameter: true}))
+	require.EqualError(t, r.Register(&TaskType{Name: "bash"}), "the task type 'bash' is already registered")
+	require.EqualError(t,
+		r.Register(&TaskType{Name: "custom", Parameters: []Parameter{{Name: "day", Type: "date"}}}),
+		"the parameter 'day' of the task type 'custom' has an unknown type 'date'",
+	)
+	require.EqualError(t,
+		r.Register(&TaskType{Name: "custom", Parameters: []Parameter{{Name: "interval", Type: Duration, Default: "60"}}}),
+		"the default of the parameter 'interval' of the task type 'custom' must be a positive duration such as 30s, 5m or 1h, got '60'",
+	)
+
+	require.NotNil(t, r.Get("bash"))
+	require.Nil(t, r.Get("custom"))
+	require.Equal(t, []string{"bash"}, r.Names())
+}
+
+func TestBuiltin(t *testing.T) {
+	t.Parallel()
+
+	r := Builtin()
+	require.Equal(t, []string{
+		"bash",
+		"bq.cost_tracker",
+		"bq.sensor.query",
+		"bq.sensor.table",
+		"bq.sql",
+		"gcs.from.s3",
+		"gcs.sensor.object_sensor_with_prefix",
+		"python",
+		"s3.sensor.key_sensor",
+		"sf.sql",
+	}, r.Names())
+	require.Equal(t, "1m", r.Get("bq.sensor.table").Parameter("poke_interval").Default)
+}